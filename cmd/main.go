@@ -1,7 +1,18 @@
 package main
 
-import "songs-library-go/internal/app"
+import (
+	"os"
+	"songs-library-go/internal/app"
+	"songs-library-go/internal/config"
+)
 
 func main() {
-	app.Run()
+	cfg, args := config.Init(os.Args[1:])
+
+	if len(args) > 0 {
+		app.RunCommand(cfg, args)
+		return
+	}
+
+	app.Run(cfg)
 }
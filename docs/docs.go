@@ -15,9 +15,56 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/songs": {
-            "get": {
-                "description": "Retrieve a paginated list of songs based on various filters like group, song, release date, text, and link.",
+        "/annotations/{annotationID}": {
+            "delete": {
+                "description": "Delete an annotation written by the authenticated user.",
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Delete an annotation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Annotation ID",
+                        "name": "annotationID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Annotation successfully deleted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/annotations/{annotationID}/vote": {
+            "put": {
+                "description": "Upvote (1) or downvote (-1) an annotation, or withdraw the vote (0). Every user has one vote per annotation.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,49 +72,171 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "annotations"
                 ],
-                "summary": "Get list of songs",
+                "summary": "Vote on an annotation",
                 "parameters": [
                     {
-                        "description": "Filters",
+                        "type": "integer",
+                        "description": "Annotation ID",
+                        "name": "annotationID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vote",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SongParamsDto"
+                            "$ref": "#/definitions/dto.VoteDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New score of the annotation",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ScoreDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/charts": {
+            "get": {
+                "description": "Retrieve the most played songs in a period of days ending today (UTC).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plays"
+                ],
+                "summary": "Get a chart",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number for pagination",
-                        "name": "page",
+                        "type": "string",
+                        "default": "7d",
+                        "description": "Period in days, from 1d to 365d",
+                        "name": "period",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Number of songs per page",
+                        "default": 10,
+                        "description": "Number of songs",
                         "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of songs",
+                        "description": "Chart",
                         "schema": {
-                            "$ref": "#/definitions/dto.SongsDto"
+                            "$ref": "#/definitions/dto.ChartDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Report that the process is running. Dependencies are not checked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Alive",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/keys": {
+            "get": {
+                "description": "List all issued API keys without their secrets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keys"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "API keys",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIKeyDto"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Add a new song to the database.",
+                "description": "Issue a new API key with the given role. The key is returned only once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -75,63 +244,100 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "keys"
                 ],
-                "summary": "Create a new song",
+                "summary": "Issue an API key",
                 "parameters": [
                     {
-                        "description": "Song details to create",
+                        "description": "Name and role of the key",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateSongDto"
+                            "$ref": "#/definitions/dto.IssueAPIKeyDto"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created song",
+                        "description": "Issued key",
                         "schema": {
-                            "$ref": "#/definitions/dto.SongDto"
+                            "$ref": "#/definitions/dto.IssuedAPIKeyDto"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     }
                 }
             }
         },
-        "/songs/{songID}": {
-            "get": {
-                "description": "Retrieve the verses of a song based on its ID with pagination.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+        "/keys/{keyID}": {
+            "delete": {
+                "description": "Revoke an API key by its ID. Requests with a revoked key are rejected with 401.",
                 "tags": [
-                    "songs"
+                    "keys"
                 ],
-                "summary": "Get song text by song ID",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
-                        "name": "songID",
+                        "description": "API key ID",
+                        "name": "keyID",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Key successfully revoked"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/favorites": {
+            "get": {
+                "description": "Retrieve a paginated list of songs favorited by the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favorites"
+                ],
+                "summary": "Get favorite songs",
+                "parameters": [
                     {
                         "type": "integer",
                         "description": "Page number for pagination",
@@ -140,44 +346,46 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Number of verses per page",
+                        "description": "Number of songs per page",
                         "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of song verses",
+                        "description": "List of favorite songs",
                         "schema": {
-                            "$ref": "#/definitions/dto.VersesDto"
+                            "$ref": "#/definitions/dto.SongsDto"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/me/favorites/{songID}": {
             "put": {
-                "description": "Update the details of an existing song based on its ID.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Add a song to the favorites of the authenticated user. Adding a song twice has no effect.",
                 "tags": [
-                    "songs"
+                    "favorites"
                 ],
-                "summary": "Update a song by song ID",
+                "summary": "Add a song to favorites",
                 "parameters": [
                     {
                         "type": "integer",
@@ -185,56 +393,44 @@ const docTemplate = `{
                         "name": "songID",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Song details to update",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.SongParamsDto"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated song",
-                        "schema": {
-                            "$ref": "#/definitions/dto.SongDto"
-                        }
+                        "description": "Song added to favorites"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Delete a song from the database based on its ID.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Remove a song from the favorites of the authenticated user. Removing a song that is not a favorite has no effect.",
                 "tags": [
-                    "songs"
+                    "favorites"
                 ],
-                "summary": "Delete a song by song ID",
+                "summary": "Remove a song from favorites",
                 "parameters": [
                     {
                         "type": "integer",
@@ -246,60 +442,2834 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Song successfully deleted"
+                        "description": "Song removed from favorites"
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/delivery.JSONError"
+                            "$ref": "#/definitions/delivery.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "delivery.JSONError": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "invalid input"
-                },
-                "message": {
-                    "type": "string",
-                    "example": "invalid JSON body"
-                }
-            }
         },
-        "dto.CreateSongDto": {
-            "type": "object",
-            "required": [
-                "group",
-                "song"
-            ],
-            "properties": {
-                "group": {
-                    "type": "string",
+        "/playlists": {
+            "get": {
+                "description": "Retrieve a paginated list of the playlists owned by the authenticated user, most recently updated first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Get own playlists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of playlists per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of playlists",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistsDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new playlist owned by the authenticated user. Public playlists can be read by all users.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Create a playlist",
+                "parameters": [
+                    {
+                        "description": "Name, description and visibility of the playlist",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistInputDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created playlist",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{playlistID}": {
+            "get": {
+                "description": "Retrieve a playlist owned by the authenticated user or shared publicly, with a paginated list of its songs in order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Get a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "playlistID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Playlist with entries",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistWithEntriesDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the name, description and visibility of a playlist owned by the authenticated user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Replace a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "playlistID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name, description and visibility of the playlist",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistInputDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Replaced playlist",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a playlist owned by the authenticated user together with its entries.",
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Delete a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "playlistID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Playlist successfully deleted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{playlistID}/entries": {
+            "post": {
+                "description": "Insert a song into a playlist owned by the authenticated user at the given position, shifting the following entries down.\nWithout a position, or with a position after the last entry, the song is appended. A song may appear in a playlist more than once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Add a song to a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "playlistID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Song and position",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddPlaylistEntryDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Added entry",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaylistEntryDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{playlistID}/entries/order": {
+            "put": {
+                "description": "Set the order of the entries of a playlist owned by the authenticated user. The IDs of all entries must be listed exactly once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Reorder a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "playlistID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Entry IDs in the new order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReorderPlaylistDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Playlist successfully reordered"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{playlistID}/entries/{entryID}": {
+            "delete": {
+                "description": "Remove an entry from a playlist owned by the authenticated user. The following entries move up.",
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Remove a song from a playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "playlistID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entry successfully removed"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Check the database, the applied migrations and, as an optional dependency, the music info API. The server is not ready only if a critical check fails.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Ready, possibly degraded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "get": {
+                "description": "Retrieve a paginated list of the reviews of all songs with the given moderation status, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Get reviews for moderation",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Moderation status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of reviews per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of reviews",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewsDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{reviewID}/status": {
+            "put": {
+                "description": "Approve or reject a review. Only approved reviews are shown to other users.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Moderate a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "reviewID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Moderation status",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ModerateReviewDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Moderated review",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs": {
+            "get": {
+                "description": "Retrieve a paginated list of songs based on various filters like group, song, release date, text, and link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get list of songs",
+                "parameters": [
+                    {
+                        "description": "Filters",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongParamsDto"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of songs per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs favorited by the authenticated user",
+                        "name": "favorited",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only songs with at least this average rating, from 1 to 5",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "popular",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Sort order: popular orders songs by their plays in period, rating by their average rating",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "7d",
+                        "description": "Period in days for sort=popular, from 1d to 365d",
+                        "name": "period",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of songs",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongsDto"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new song to the database.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Create a new song",
+                "parameters": [
+                    {
+                        "description": "Song details to create",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateSongDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created song",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/batch": {
+            "post": {
+                "description": "Apply a list of mixed create, update and delete operations. Atomic batches run in one transaction and are rolled back on the first failure, otherwise every operation is applied independently.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Apply a batch of song operations",
+                "parameters": [
+                    {
+                        "description": "Batch of operations",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchInputDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-operation results",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchResultDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Atomic batch rolled back",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchResultDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/daily": {
+            "get": {
+                "description": "Retrieve the song of the current UTC day, which is the same for everyone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get the song of the day",
+                "responses": {
+                    "200": {
+                        "description": "Song of the day",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongDto"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/export": {
+            "get": {
+                "description": "Stream the whole catalog, or the subset matching the same filters as GET /songs, as a CSV, NDJSON or JSON file.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Export songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File format: csv, ndjson or json (default ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by group",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by song",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by release date in the format dd.mm.yyyy",
+                        "name": "release_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by words in the text",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by link",
+                        "name": "link",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Songs file",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.SongDto"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/import": {
+            "post": {
+                "description": "Stream a CSV (with a header row) or NDJSON file of songs and insert them in batches. Conflicts with existing songs are skipped, updated or abort the whole import depending on on_conflict.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Import songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File format: csv or ndjson (defaults to the Content-Type)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conflict policy: skip, update or fail (default skip)",
+                        "name": "on_conflict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-row import report",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportReportDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Import aborted on conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportReportDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/random": {
+            "get": {
+                "description": "Retrieve distinct random songs matching the same filters as the list of songs. Fewer songs are returned if fewer songs match.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get random songs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of songs",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by group name",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by song name",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by release date",
+                        "name": "release_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by song text",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by song link",
+                        "name": "link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only songs favorited by the authenticated user",
+                        "name": "favorited",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimal average rating",
+                        "name": "min_rating",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Random songs",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RandomSongsDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{songID}": {
+            "get": {
+                "description": "Retrieve the verses of a song based on its ID with pagination.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song text by song ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of verses per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of song verses",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VersesDto"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace all fields of an existing song based on its ID. Omitted release_date, text and link are cleared.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Replace a song by song ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Full song representation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplaceSongDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Replaced song",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a song from the database based on its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Delete a song by song ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Song successfully deleted"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially update a song with a JSON Merge Patch (null clears release_date, text or link) or a JSON Patch.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Patch a song by song ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch, or an array of dto.JSONPatchOperationDto for JSON Patch",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongParamsDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Patched song",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "JSON Patch test failed",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{songID}/annotations": {
+            "get": {
+                "description": "Retrieve the annotations of the verses on a page of the song text, paginated like GET /songs/{songID}.\nAnnotations whose fragment was removed from the edited lyrics are flagged as stale and keep their last verse.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Get annotations of song lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number of the song text",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of verses per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Annotations",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AnnotationsDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Annotate a range of lines or characters of a verse. Verses are counted from 0 across the whole song text,\nranges are half-open and counted from 0 within the verse.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Annotate song lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Annotation and its anchor",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateAnnotationDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created annotation",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AnnotationDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{songID}/plays": {
+            "post": {
+                "description": "Record a listen of a song by the authenticated user. Plays are counted asynchronously, so they show up in rankings and charts after a few seconds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plays"
+                ],
+                "summary": "Record a play",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Source and time of the play",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecordPlayDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Play accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{songID}/rating": {
+            "get": {
+                "description": "Retrieve the rating and review of a song by the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ratings"
+                ],
+                "summary": "Get own rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rating",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RatingDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Rate a song with 1 to 5 stars and an optional review, replacing an earlier rating by the authenticated user.\nNew and changed reviews are shown to other users only after a moderator approves them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ratings"
+                ],
+                "summary": "Rate a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating and review",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RateSongDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rating",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RatingDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the rating and review of a song by the authenticated user.",
+                "tags": [
+                    "ratings"
+                ],
+                "summary": "Delete own rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rating successfully deleted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{songID}/reviews": {
+            "get": {
+                "description": "Retrieve a paginated list of the approved reviews of a song, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ratings"
+                ],
+                "summary": "Get reviews of a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of reviews per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of reviews",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewsDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{songID}/similar": {
+            "get": {
+                "description": "Retrieve the songs most similar to a song by lyrics, artist, tags and release year. Similarities are computed offline by the similar command, so new songs show up after the next computation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get similar songs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of songs",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Similar songs",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SimilarSongsDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{songID}/tags": {
+            "get": {
+                "description": "Retrieve the tags of a song, such as genres and moods, in alphabetical order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get tags of a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tags",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongTagsDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the tags of a song with up to 20 tags of up to 50 characters. Tags are lowercased and duplicates are dropped.\nShared tags make songs more similar after the next computation of similar songs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Replace tags of a song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "songID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongTagsDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tags",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SongTagsDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/delivery.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "delivery.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "required"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "group"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "is required"
+                }
+            }
+        },
+        "delivery.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "validation_failed"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "group is required"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/delivery.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/songs"
+                },
+                "missing_permission": {
+                    "type": "string",
+                    "example": "songs:delete"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "host/abcdef-000001"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "invalid create song input body"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "urn:songs-library:problem:validation_failed"
+                }
+            }
+        },
+        "dto.APIKeyDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-11-03T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "sl_Zm9vYmFy"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2024-11-04T12:00:00Z"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "editor"
+                }
+            }
+        },
+        "dto.AddPlaylistEntryDto": {
+            "type": "object",
+            "required": [
+                "song_id"
+            ],
+            "properties": {
+                "position": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "song_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                }
+            }
+        },
+        "dto.AnnotationDto": {
+            "type": "object",
+            "properties": {
+                "anchor_text": {
+                    "type": "string",
+                    "example": "Niemand kann das Bild beschreiben\nGegen seine Fensterscheibe"
+                },
+                "author_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "body": {
+                    "type": "string",
+                    "example": "The narrator watches from outside the window"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-12-08T12:00:00Z"
+                },
+                "end": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_author": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "my_vote": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "stale": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "line"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-12-08T12:00:00Z"
+                },
+                "verse_index": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "dto.AnnotationsDto": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AnnotationDto"
+                    }
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "dto.BatchInputDto": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BatchOperationInputDto"
+                    }
+                }
+            }
+        },
+        "dto.BatchOperationInputDto": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "op": {
+                    "type": "string",
+                    "example": "update"
+                }
+            }
+        },
+        "dto.BatchOperationResultDto": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "song_not_found"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "song with this id not found"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "op": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "song": {
+                    "$ref": "#/definitions/dto.SongDto"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.BatchResultDto": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BatchOperationResultDto"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "dto.ChartDto": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ChartEntryDto"
+                    }
+                },
+                "period": {
+                    "type": "string",
+                    "example": "7d"
+                }
+            }
+        },
+        "dto.ChartEntryDto": {
+            "type": "object",
+            "properties": {
+                "plays": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "song": {
+                    "$ref": "#/definitions/dto.SongDto"
+                }
+            }
+        },
+        "dto.CreateAnnotationDto": {
+            "type": "object",
+            "required": [
+                "body",
+                "unit"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 5000,
+                    "example": "The narrator watches from outside the window"
+                },
+                "end": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "start": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "unit": {
+                    "type": "string",
+                    "enum": [
+                        "line",
+                        "char"
+                    ],
+                    "example": "line"
+                },
+                "verse_index": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "dto.CreateSongDto": {
+            "type": "object",
+            "required": [
+                "group",
+                "song"
+            ],
+            "properties": {
+                "group": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Rammstein"
+                },
+                "song": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Weit Weg"
+                }
+            }
+        },
+        "dto.HealthCheckDto": {
+            "type": "object",
+            "properties": {
+                "critical": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "error": {
+                    "type": "string",
+                    "example": "dial tcp: connection refused"
+                },
+                "latency_ms": {
+                    "type": "number",
+                    "example": 1.25
+                },
+                "name": {
+                    "type": "string",
+                    "example": "database"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "dto.HealthDto": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.HealthCheckDto"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "dto.ImportReportDto": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImportRowDto"
+                    }
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "dto.ImportRowDto": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "song with this name by this group already exist"
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Rammstein"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Weit Weg"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "created"
+                }
+            }
+        },
+        "dto.IssueAPIKeyDto": {
+            "type": "object",
+            "required": [
+                "name",
+                "role"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "ci"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor",
+                        "admin"
+                    ],
+                    "example": "editor"
+                }
+            }
+        },
+        "dto.IssuedAPIKeyDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-11-03T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "key": {
+                    "type": "string",
+                    "example": "sl_Zm9vYmFyYmF6cXV4..."
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ci"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "sl_Zm9vYmFy"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2024-11-04T12:00:00Z"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "editor"
+                }
+            }
+        },
+        "dto.ModerateReviewDto": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ],
+                    "example": "approved"
+                }
+            }
+        },
+        "dto.PlaylistDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-11-17T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Songs for long drives"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_owner": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "is_public": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Road trip"
+                },
+                "songs_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-11-17T12:00:00Z"
+                }
+            }
+        },
+        "dto.PlaylistEntryDto": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string",
+                    "example": "2024-11-17T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "song": {
+                    "$ref": "#/definitions/dto.SongDto"
+                }
+            }
+        },
+        "dto.PlaylistInputDto": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Songs for long drives"
+                },
+                "is_public": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "Road trip"
+                }
+            }
+        },
+        "dto.PlaylistWithEntriesDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-11-17T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Songs for long drives"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PlaylistEntryDto"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_owner": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "is_public": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Road trip"
+                },
+                "songs_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-11-17T12:00:00Z"
+                }
+            }
+        },
+        "dto.PlaylistsDto": {
+            "type": "object",
+            "properties": {
+                "playlists": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PlaylistDto"
+                    }
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.RandomSongsDto": {
+            "type": "object",
+            "properties": {
+                "songs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SongDto"
+                    }
+                }
+            }
+        },
+        "dto.RateSongDto": {
+            "type": "object",
+            "required": [
+                "rating"
+            ],
+            "properties": {
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 5
+                },
+                "review": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "A classic"
+                }
+            }
+        },
+        "dto.RatingDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-12-01T12:00:00Z"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "review": {
+                    "type": "string",
+                    "example": "A classic"
+                },
+                "review_status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-12-01T12:00:00Z"
+                }
+            }
+        },
+        "dto.RecordPlayDto": {
+            "type": "object",
+            "properties": {
+                "played_at": {
+                    "type": "string",
+                    "example": "2024-11-24T12:00:00Z"
+                },
+                "source": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "mobile"
+                }
+            }
+        },
+        "dto.ReorderPlaylistDto": {
+            "type": "object",
+            "required": [
+                "entry_ids"
+            ],
+            "properties": {
+                "entry_ids": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "dto.ReplaceSongDto": {
+            "type": "object",
+            "required": [
+                "group",
+                "song"
+            ],
+            "properties": {
+                "group": {
+                    "type": "string",
                     "maxLength": 100,
                     "example": "Rammstein"
                 },
+                "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=N9AalJuwLyQ\u0026ab_channel=Rammstein-Topic"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "17.05.2019"
+                },
                 "song": {
                     "type": "string",
                     "maxLength": 100,
                     "example": "Weit Weg"
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "minLength": 1,
+                    "example": "Niemand kann das Bild beschreiben"
+                }
+            }
+        },
+        "dto.ReviewDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-12-01T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "review": {
+                    "type": "string",
+                    "example": "A classic"
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "example": "approved"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-12-01T12:00:00Z"
+                }
+            }
+        },
+        "dto.ReviewsDto": {
+            "type": "object",
+            "properties": {
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReviewDto"
+                    }
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.ScoreDto": {
+            "type": "object",
+            "properties": {
+                "score": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "dto.SimilarSongDto": {
+            "type": "object",
+            "properties": {
+                "score": {
+                    "type": "number",
+                    "example": 0.72
+                },
+                "song": {
+                    "$ref": "#/definitions/dto.SongDto"
+                }
+            }
+        },
+        "dto.SimilarSongsDto": {
+            "type": "object",
+            "properties": {
+                "songs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SimilarSongDto"
+                    }
                 }
             }
         },
         "dto.SongDto": {
             "type": "object",
             "properties": {
+                "average_rating": {
+                    "type": "number",
+                    "example": 4.5
+                },
                 "group": {
                     "type": "string",
                     "example": "Rammstein"
@@ -308,10 +3278,22 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "is_favorite": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "link": {
                     "type": "string",
                     "example": "https://www.youtube.com/watch?v=N9AalJuwLyQ\u0026ab_channel=Rammstein-Topic"
                 },
+                "plays": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "ratings_count": {
+                    "type": "integer",
+                    "example": 12
+                },
                 "release_date": {
                     "type": "string",
                     "example": "17.05.2019"
@@ -357,6 +3339,25 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.SongTagsDto": {
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "rock",
+                        "ballad"
+                    ]
+                }
+            }
+        },
         "dto.SongsDto": {
             "type": "object",
             "properties": {
@@ -392,6 +3393,20 @@ const docTemplate = `{
                     ]
                 }
             }
+        },
+        "dto.VoteDto": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "integer",
+                    "enum": [
+                        -1,
+                        0,
+                        1
+                    ],
+                    "example": 1
+                }
+            }
         }
     }
 }`
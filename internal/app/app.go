@@ -1,34 +1,170 @@
 package app
 
 import (
+	"context"
+	"database/sql"
 	"github.com/go-chi/chi/v5"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 	"songs-library-go/internal/config"
 	"songs-library-go/internal/delivery/handlers"
+	"songs-library-go/internal/delivery/middleware"
+	"songs-library-go/internal/metrics"
 	"songs-library-go/internal/repository"
 	"songs-library-go/internal/service"
+	"songs-library-go/internal/tracing"
 	"songs-library-go/internal/validator"
 )
 
-const serverStart = "server starting on port"
+const (
+	serverStart = "server starting on port"
+	errServing  = "error serving http"
+	errTracing  = "error initializing tracing"
+)
 
 // Run initializes whole application.
-func Run() {
-	cfg := config.Init()
-
+func Run(cfg *config.Config) {
 	conn := repository.Init(cfg)
-	defer conn.Close()
+	metrics.RegisterDB(conn, cfg.DbName)
+
+	replica := repository.ConnectReplica(cfg)
+	if replica != nil {
+		metrics.RegisterDB(replica, cfg.DbName+"_replica")
+	}
+
+	migrator, err := repository.NewMigrator(conn)
+	if err != nil {
+		log.WithError(err).Fatal(errMigrating)
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.TracesExporter)
+	if err != nil {
+		log.WithError(err).Fatal(errTracing)
+	}
+
+	lc := newLifecycle(cfg.ShutdownTimeout)
+	lc.OnClose("tracing", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
 
-	songsRepo := repository.NewSongsRepo(conn)
+		return shutdownTracing(ctx)
+	})
+	lc.OnClose("db", conn.Close)
+	if replica != nil {
+		lc.OnClose("db replica", replica.Close)
+	}
+	lc.Go(func(ctx context.Context) { repository.PingDatabase(ctx, conn) })
+
+	songsRepo := repository.NewSongsRepo(conn, replica)
+	idempotencyRepo := repository.NewIdempotencyRepo(conn)
+	authService := service.NewAuthService(repository.NewAPIKeysRepo(conn), repository.NewUsersRepo(conn), jwtKeys(cfg))
+	lc.Go(func(ctx context.Context) { idempotencyRepo.PurgeExpired(ctx, cfg.IdempotencyTTL) })
 
 	v := validator.Init()
-	songsService := service.NewSongsService(songsRepo, cfg.MusicInfoAPIURL)
+	songsService := service.NewSongsService(songsRepo, v, cfg.MusicInfoAPIURL)
+	lc.OnDrain("enrichment", songsService.WaitForEnrichment)
+	playlistsService := service.NewPlaylistsService(repository.NewPlaylistsRepo(conn))
+	playsService := service.NewPlaysService(repository.NewPlaysRepo(conn))
+	lc.Go(func(ctx context.Context) { playsService.FlushPeriodically(ctx, cfg.PlaysFlushInterval) })
+	ratingsService := service.NewRatingsService(repository.NewRatingsRepo(conn))
+	annotationsService := service.NewAnnotationsService(repository.NewAnnotationsRepo(conn), songsRepo)
+	rateLimitService := newRateLimitService(cfg, conn)
+	if rateLimitService != nil {
+		lc.Go(rateLimitService.PurgeIdle)
+	}
+	tagsRepo := repository.NewTagsRepo(conn)
+	tagsService := service.NewTagsService(tagsRepo)
+	similarityService := service.NewSimilarityService(repository.NewSimilaritiesRepo(conn), songsRepo, tagsRepo, cfg.SimilarityWeights)
 
 	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Metrics)
+	r.Use(middleware.Tracing)
+	r.Use(middleware.Logger)
+	r.Use(middleware.SecureHeaders)
+	if len(cfg.CORSOrigins) > 0 {
+		r.Use(middleware.CORS(middleware.CORSOptions{
+			AllowedOrigins:   cfg.CORSOrigins,
+			AllowedMethods:   cfg.CORSMethods,
+			AllowedHeaders:   cfg.CORSHeaders,
+			AllowCredentials: cfg.CORSCredentials,
+			MaxAge:           cfg.CORSMaxAge,
+		}))
+	}
+	r.Use(middleware.BodyLimit(cfg.BodyLimitDefault, cfg.BodyLimitRoutes))
+	if rateLimitService != nil {
+		r.Use(middleware.RateLimitIP(rateLimitService))
+	}
+	r.Use(middleware.Authenticate(authService))
+	if rateLimitService != nil {
+		r.Use(middleware.RateLimit(rateLimitService))
+	}
+	r.Use(middleware.Audit)
+	r.Use(middleware.Idempotency(idempotencyRepo, cfg.IdempotencyTTL))
+
+	healthHandler := handlers.NewHealthHandler(service.NewHealthService(repository.NewHealthRepo(conn, migrator), cfg.MusicInfoAPIURL))
+	healthHandler.RegisterRoutes(r)
+
 	songsHandler := handlers.NewSongsHandler(v, songsService)
 	songsHandler.RegisterRoutes(r)
 
+	playsHandler := handlers.NewPlaysHandler(v, playsService)
+	playsHandler.RegisterRoutes(r)
+
+	similarSongsHandler := handlers.NewSimilarSongsHandler(v, similarityService)
+	similarSongsHandler.RegisterRoutes(r)
+
+	tagsHandler := handlers.NewTagsHandler(v, tagsService)
+	tagsHandler.RegisterRoutes(r)
+
+	annotationsHandler := handlers.NewAnnotationsHandler(v, annotationsService)
+	annotationsHandler.RegisterRoutes(r)
+
+	ratingsHandler := handlers.NewRatingsHandler(v, ratingsService)
+	ratingsHandler.RegisterRoutes(r)
+
+	playlistsHandler := handlers.NewPlaylistsHandler(v, playlistsService)
+	playlistsHandler.RegisterRoutes(r)
+
+	keysHandler := handlers.NewKeysHandler(v, authService)
+	keysHandler.RegisterRoutes(r)
+
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
 	log.Infof(serverStart+" %s", cfg.Port)
-	log.Fatal(http.ListenAndServe(":"+cfg.Port, r))
+	if err := lc.Serve(server); err != nil {
+		log.WithError(err).Fatal(errServing)
+	}
+}
+
+func jwtKeys(cfg *config.Config) service.JWTKeys {
+	return service.JWTKeys{
+		HMACSecret:   cfg.JWTHMACSecret,
+		RSAPublicKey: cfg.JWTRSAPublicKey,
+		Issuer:       cfg.JWTIssuer,
+		Audience:     cfg.JWTAudience,
+	}
+}
+
+// newRateLimitService returns the rate limiter with the configured backend, or nil if rate limiting is disabled.
+func newRateLimitService(cfg *config.Config, conn *sql.DB) *service.RateLimitService {
+	var store service.RateLimitStore
+
+	switch cfg.RateLimitBackend {
+	case config.RateLimitBackendMemory:
+		store = service.NewMemoryRateLimitStore()
+	case config.RateLimitBackendPostgres:
+		store = repository.NewRateLimitRepo(conn)
+	default:
+		return nil
+	}
+
+	return service.NewRateLimitService(store, cfg.RateLimitDefault, cfg.RateLimitRoutes, cfg.RateLimitIP)
 }
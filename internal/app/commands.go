@@ -0,0 +1,296 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"path/filepath"
+	"songs-library-go/internal/config"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/repository"
+	"songs-library-go/internal/service"
+	"songs-library-go/internal/validator"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	errUnknownCommand   = "unknown command"
+	errInvalidArguments = "invalid command arguments"
+	errOpeningFile      = "error opening file"
+	errImportingSongs   = "error importing songs"
+	errIssuingKey       = "error issuing API key"
+	errRevokingKey      = "error revoking API key"
+	errListingKeys      = "error listing API keys"
+	errComputingSimilar = "error computing similar songs"
+	errMigrating        = "error migrating database"
+	errPrintingConfig   = "error printing config"
+	usage               = "usage: server [flags] [import|keys|similar|migrate|config]"
+	importUsage         = "usage: server import [-format csv|ndjson] [-on-conflict skip|update|fail] <file|->"
+	keysUsage           = "usage: server keys issue -name <name> [-role viewer|editor|admin] | server keys revoke <id> | server keys list"
+	similarUsage        = "usage: server similar [-top N]"
+	migrateUsage        = "usage: server migrate up|down|status|redo"
+	configUsage         = "usage: server config print"
+)
+
+const defaultSimilarTop = 20
+
+// RunCommand executes the CLI subcommand named by the first argument.
+func RunCommand(cfg *config.Config, args []string) {
+	switch args[0] {
+	case "import":
+		runImport(cfg, args[1:])
+	case "keys":
+		runKeys(cfg, args[1:])
+	case "similar":
+		runSimilar(cfg, args[1:])
+	case "migrate":
+		runMigrate(cfg, args[1:])
+	case "config":
+		runConfig(cfg, args[1:])
+	default:
+		log.Fatalf("%s %q, %s", errUnknownCommand, args[0], usage)
+	}
+}
+
+func runImport(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "", "file format: csv or ndjson (defaults to the file extension)")
+	onConflict := flags.String("on-conflict", string(domain.ConflictSkip), "conflict policy: skip, update or fail")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		log.Fatalf("%s, %s", errInvalidArguments, importUsage)
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		if *format == "jsonl" {
+			*format = domain.FormatNDJSON
+		}
+	}
+
+	importParams := dto.ImportParamsDto{
+		Format:     *format,
+		OnConflict: *onConflict,
+	}
+
+	v := validator.Init()
+	if err := v.Struct(importParams); err != nil {
+		log.WithError(err).Fatalf("%s, %s", errInvalidArguments, importUsage)
+	}
+
+	var file io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.WithError(err).Fatal(errOpeningFile)
+		}
+		defer f.Close()
+
+		file = f
+	}
+
+	conn := repository.Init(cfg)
+	defer conn.Close()
+
+	songsService := service.NewSongsService(repository.NewSongsRepo(conn, nil), v, cfg.MusicInfoAPIURL)
+
+	report, err := songsService.Import(context.Background(), file, importParams)
+
+	counts := make(map[domain.ImportStatus]int)
+	for _, row := range report.Rows {
+		counts[row.Status]++
+
+		if row.Error != "" {
+			fmt.Printf("row %d (%s - %s): %s: %s\n", row.Row, row.Group, row.Song, row.Status, row.Error)
+		}
+	}
+
+	fmt.Printf("total: %d, created: %d, updated: %d, skipped: %d, invalid: %d, conflict: %d, rolled back: %d\n",
+		len(report.Rows), counts[domain.ImportCreated], counts[domain.ImportUpdated], counts[domain.ImportSkipped],
+		counts[domain.ImportInvalid], counts[domain.ImportConflict], counts[domain.ImportRolledBack])
+
+	if err != nil {
+		log.WithError(err).Fatal(errImportingSongs)
+	}
+
+	songsService.WaitForEnrichment(context.Background())
+}
+
+func runKeys(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatalf("%s, %s", errInvalidArguments, keysUsage)
+	}
+
+	var run func(authService *service.AuthService)
+
+	switch args[0] {
+	case "issue":
+		flags := flag.NewFlagSet("keys issue", flag.ExitOnError)
+		name := flags.String("name", "", "name of the key owner, recorded in the audit trail")
+		role := flags.String("role", string(domain.RoleViewer), "role granted to the key: viewer, editor or admin")
+		flags.Parse(args[1:])
+
+		if *name == "" || flags.NArg() != 0 || !domain.Role(*role).IsValid() {
+			log.Fatalf("%s, %s", errInvalidArguments, keysUsage)
+		}
+
+		run = func(authService *service.AuthService) {
+			key, secret, err := authService.IssueKey(context.Background(), *name, domain.Role(*role))
+			if err != nil {
+				log.WithError(err).Fatal(errIssuingKey)
+			}
+
+			fmt.Printf("id: %d, name: %s, role: %s\n", key.ID, key.Name, key.Role)
+			fmt.Printf("key: %s\n", secret)
+			fmt.Println("store the key now, it can't be shown again")
+		}
+	case "revoke":
+		if len(args) != 2 {
+			log.Fatalf("%s, %s", errInvalidArguments, keysUsage)
+		}
+
+		keyID, err := strconv.ParseInt(args[1], 10, 32)
+		if err != nil || keyID <= 0 {
+			log.Fatalf("%s, %s", errInvalidArguments, keysUsage)
+		}
+
+		run = func(authService *service.AuthService) {
+			if err := authService.RevokeKey(context.Background(), int32(keyID)); err != nil {
+				log.WithError(err).Fatal(errRevokingKey)
+			}
+
+			fmt.Printf("key %d revoked\n", keyID)
+		}
+	case "list":
+		run = func(authService *service.AuthService) {
+			keys, err := authService.ListKeys(context.Background())
+			if err != nil {
+				log.WithError(err).Fatal(errListingKeys)
+			}
+
+			for _, key := range keys {
+				status := "active"
+				if key.RevokedAt != nil {
+					status = "revoked at " + key.RevokedAt.Format(time.RFC3339)
+				}
+
+				fmt.Printf("%d\t%s\t%s\t%s...\t%s\t%s\n", key.ID, key.Name, key.Role, key.Prefix, key.CreatedAt.Format(time.RFC3339), status)
+			}
+		}
+	default:
+		log.Fatalf("%s %q, %s", errUnknownCommand, args[0], keysUsage)
+	}
+
+	conn := repository.Init(cfg)
+	defer conn.Close()
+
+	run(service.NewAuthService(repository.NewAPIKeysRepo(conn), repository.NewUsersRepo(conn), jwtKeys(cfg)))
+}
+
+func runSimilar(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("similar", flag.ExitOnError)
+	top := flags.Int("top", defaultSimilarTop, "number of similar songs stored per song")
+	flags.Parse(args)
+
+	if *top <= 0 || flags.NArg() != 0 {
+		log.Fatalf("%s, %s", errInvalidArguments, similarUsage)
+	}
+
+	conn := repository.Init(cfg)
+	defer conn.Close()
+
+	similarityService := service.NewSimilarityService(repository.NewSimilaritiesRepo(conn), repository.NewSongsRepo(conn, nil), repository.NewTagsRepo(conn), cfg.SimilarityWeights)
+
+	count, err := similarityService.Compute(context.Background(), *top)
+	if err != nil {
+		log.WithError(err).Fatal(errComputingSimilar)
+	}
+
+	fmt.Printf("similar songs stored: %d\n", count)
+}
+
+func runMigrate(cfg *config.Config, args []string) {
+	if len(args) != 1 {
+		log.Fatalf("%s, %s", errInvalidArguments, migrateUsage)
+	}
+
+	var run func(ctx context.Context, migrator *repository.Migrator) error
+
+	switch args[0] {
+	case "up":
+		run = func(ctx context.Context, migrator *repository.Migrator) error {
+			results, err := migrator.Up(ctx)
+			printMigrationResults(results...)
+			return err
+		}
+	case "down":
+		run = func(ctx context.Context, migrator *repository.Migrator) error {
+			result, err := migrator.Down(ctx)
+			if err == nil {
+				printMigrationResults(result)
+			}
+			return err
+		}
+	case "redo":
+		run = func(ctx context.Context, migrator *repository.Migrator) error {
+			results, err := migrator.Redo(ctx)
+			printMigrationResults(results...)
+			return err
+		}
+	case "status":
+		run = func(ctx context.Context, migrator *repository.Migrator) error {
+			migrations, err := migrator.Status(ctx)
+			for _, migration := range migrations {
+				appliedAt := "pending"
+				if migration.Applied {
+					appliedAt = migration.AppliedAt.Format(time.RFC3339)
+				}
+
+				fmt.Printf("%d\t%s\t%s\n", migration.Version, appliedAt, migration.Name)
+			}
+			return err
+		}
+	default:
+		log.Fatalf("%s %q, %s", errUnknownCommand, args[0], migrateUsage)
+	}
+
+	conn := repository.Connect(cfg)
+	defer conn.Close()
+
+	migrator, err := repository.NewMigrator(conn)
+	if err != nil {
+		log.WithError(err).Fatal(errMigrating)
+	}
+
+	if err := run(context.Background(), migrator); err != nil {
+		log.WithError(err).Fatal(errMigrating)
+	}
+}
+
+func runConfig(cfg *config.Config, args []string) {
+	if len(args) != 1 || args[0] != "print" {
+		log.Fatalf("%s, %s", errInvalidArguments, configUsage)
+	}
+
+	if err := cfg.Print(os.Stdout); err != nil {
+		log.WithError(err).Fatal(errPrintingConfig)
+	}
+}
+
+func printMigrationResults(results ...domain.MigrationResult) {
+	if len(results) == 0 {
+		fmt.Println("no migrations to apply")
+	}
+
+	for _, result := range results {
+		fmt.Printf("%s\t%d\t%s\t%s\n", result.Direction, result.Version, result.Name, result.Duration.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	mesShuttingDown       = "shutting down"
+	successfulShutdown    = "server stopped"
+	errShuttingDownServer = "error shutting down http server"
+	errDraining           = "error draining background work"
+	errStoppingWorkers    = "background workers didn't stop in time"
+	errClosing            = "error closing"
+)
+
+// drain is a step of the shutdown that finishes work started by requests after the HTTP server has stopped.
+type drain struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// closer is a resource closed at the very end of the shutdown.
+type closer struct {
+	name string
+	fn   func() error
+}
+
+// lifecycle runs the HTTP server and the background workers, and on SIGINT or SIGTERM shuts them down in order
+// within a deadline: the HTTP server stops accepting connections and waits for in-flight requests, the drains run,
+// the workers are stopped, and finally the resources are closed in the reverse order of registration.
+type lifecycle struct {
+	timeout     time.Duration
+	workersCtx  context.Context
+	stopWorkers context.CancelFunc
+	workers     *sync.WaitGroup
+	drains      []drain
+	closers     []closer
+}
+
+func newLifecycle(timeout time.Duration) *lifecycle {
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+
+	return &lifecycle{
+		timeout:     timeout,
+		workersCtx:  workersCtx,
+		stopWorkers: stopWorkers,
+		workers:     &sync.WaitGroup{},
+	}
+}
+
+// Go runs a background worker until its context is done on shutdown.
+func (l *lifecycle) Go(worker func(ctx context.Context)) {
+	l.workers.Add(1)
+
+	go func() {
+		defer l.workers.Done()
+		worker(l.workersCtx)
+	}()
+}
+
+// OnDrain registers a step run after the HTTP server has stopped, before the workers are stopped.
+func (l *lifecycle) OnDrain(name string, fn func(ctx context.Context) error) {
+	l.drains = append(l.drains, drain{name: name, fn: fn})
+}
+
+// OnClose registers a resource closed after the workers have stopped.
+func (l *lifecycle) OnClose(name string, fn func() error) {
+	l.closers = append(l.closers, closer{name: name, fn: fn})
+}
+
+// Serve runs the server until the process receives SIGINT or SIGTERM and then shuts everything down.
+// It returns the error of the server if it fails to serve, after shutting down the rest.
+func (l *lifecycle) Serve(server *http.Server) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+	case sig := <-signals:
+		log.Infof("%s (signal: %s)", mesShuttingDown, sig)
+	}
+
+	// A second signal kills the process without waiting for the shutdown.
+	signal.Stop(signals)
+
+	l.shutdown(server)
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func (l *lifecycle) shutdown(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.WithError(err).Error(errShuttingDownServer)
+	}
+
+	for _, d := range l.drains {
+		if err := d.fn(ctx); err != nil {
+			log.WithError(err).Errorf("%s (%s)", errDraining, d.name)
+		}
+	}
+
+	l.stopWorkers()
+
+	stopped := make(chan struct{})
+	go func() {
+		l.workers.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.WithError(ctx.Err()).Error(errStoppingWorkers)
+	}
+
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		if err := l.closers[i].fn(); err != nil {
+			log.WithError(err).Errorf("%s %s", errClosing, l.closers[i].name)
+		}
+	}
+
+	log.Info(successfulShutdown)
+}
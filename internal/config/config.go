@@ -1,78 +1,244 @@
 package config
 
 import (
+	"crypto/rsa"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/joho/godotenv"
 	log "github.com/sirupsen/logrus"
 	"os"
+	"slices"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"strings"
+	"time"
 )
 
 const (
 	errLoadingConfig     = "error loading config"
-	errEnvVarNotDefined  = "environment variable is not defined"
+	errInvalidConfig     = "config is invalid"
 	successfulConfigLoad = "config has been loaded successfully"
 )
 
+// Sources of configuration values, from the lowest to the highest precedence.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+const (
+	configFileFlag = "config"
+	configFileEnv  = "CONFIG_FILE"
+)
+
 // Config is a struct that holds the configuration settings for the application.
 type Config struct {
-	Port            string
-	DbUser          string
-	DbPassword      string
-	DbHost          string
-	DbPort          string
-	DbName          string
-	MusicInfoAPIURL string
+	Port               string
+	ReadHeaderTimeout  time.Duration
+	ReadTimeout        time.Duration
+	WriteTimeout       time.Duration
+	IdleTimeout        time.Duration
+	DbUser             string
+	DbPassword         string
+	DbHost             string
+	DbPort             string
+	DbName             string
+	DbDSN              string
+	DbSSLMode          string
+	DbSSLRootCert      string
+	DbSSLCert          string
+	DbSSLKey           string
+	DbConnectTimeout   time.Duration
+	DbStatementTimeout time.Duration
+	DbMaxOpenConns     int
+	DbMaxIdleConns     int
+	DbConnMaxLifetime  time.Duration
+	DbReplicaDSN       string
+	MusicInfoAPIURL    string
+	IdempotencyTTL     time.Duration
+	PlaysFlushInterval time.Duration
+	ShutdownTimeout    time.Duration
+	SimilarityWeights  domain.SimilarityWeights
+	TracesExporter     string
+	LogLevel           log.Level
+	LogFormat          string
+	RateLimitBackend   string
+	RateLimitDefault   domain.RateLimit
+	RateLimitRoutes    map[string]domain.RateLimit
+	RateLimitIP        domain.RateLimit
+	CORSOrigins        []string
+	CORSMethods        []string
+	CORSHeaders        []string
+	CORSCredentials    bool
+	CORSMaxAge         time.Duration
+	BodyLimitDefault   int64
+	BodyLimitRoutes    map[string]int64
+	JWTHMACSecret      []byte
+	JWTRSAPublicKey    *rsa.PublicKey
+	JWTIssuer          string
+	JWTAudience        string
+
+	// values are the raw values of the settings and sources the layers they were taken from, for Print.
+	values  map[string]string
+	sources map[string]string
 }
 
-// Init loads environment variables from the .env file and returns a Config struct.
-func Init() *Config {
-	if err := godotenv.Load(".env"); err != nil {
+// Backends of the rate limiter.
+const (
+	RateLimitBackendNone     = "none"
+	RateLimitBackendMemory   = "memory"
+	RateLimitBackendPostgres = "postgres"
+)
+
+// TLS modes of the database connection.
+const (
+	SSLModeDisable    = "disable"
+	SSLModeRequire    = "require"
+	SSLModeVerifyCA   = "verify-ca"
+	SSLModeVerifyFull = "verify-full"
+)
+
+var (
+	errUnknownKey          = errors.New("unknown setting")
+	errWildcardCredentials = errors.New("credentials can't be allowed for the wildcard origin")
+	errIdleConnsOverOpen   = errors.New("can't be greater than db.max_open_conns")
+	errCertWithoutKey      = errors.New("db.sslcert and db.sslkey must be set together")
+)
+
+// Init loads the configuration and configures the logger. Every setting is taken from the highest layer that sets it:
+// defaults < config file < environment variables (including the .env file) < command line flags.
+// Flags are parsed from args up to the first argument that is not a flag, and the remaining arguments are returned.
+// All invalid settings are reported at once.
+func Init(args []string) (*Config, []string) {
+	cfg, rest, err := load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		errs := []error{err}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			errs = joined.Unwrap()
+		}
+
+		for _, err := range errs {
+			log.Error(err)
+		}
+		log.Fatalf("%s (problems: %d)", errInvalidConfig, len(errs))
+	}
+
+	if err := logging.Configure(cfg.LogLevel, cfg.LogFormat); err != nil {
 		log.WithError(err).Fatal(errLoadingConfig)
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		log.Fatalf("PORT: %s", errEnvVarNotDefined)
+	log.Info(successfulConfigLoad)
+
+	return cfg, rest
+}
+
+func load(args []string) (*Config, []string, error) {
+	// The .env file is optional, variables set in the environment take precedence over it.
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("%s: %w", errLoadingConfig, err)
+	}
+
+	flagValues, configFile, rest, err := parseFlags(args)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	dbUser := os.Getenv("DB_USER")
-	if dbUser == "" {
-		log.Fatalf("DB_USER: %s", errEnvVarNotDefined)
+	if configFile == "" {
+		configFile = os.Getenv(configFileEnv)
 	}
 
-	dbPassword := os.Getenv("DB_PASSWORD")
-	if dbPassword == "" {
-		log.Fatalf("DB_PASSWORD: %s", errEnvVarNotDefined)
+	var fileValues map[string]string
+	if configFile != "" {
+		if fileValues, err = readFile(configFile); err != nil {
+			return nil, nil, fmt.Errorf("%s %s: %w", errLoadingConfig, configFile, err)
+		}
 	}
 
-	dbHost := os.Getenv("DB_HOST")
-	if dbHost == "" {
-		log.Fatalf("DB_HOST: %s", errEnvVarNotDefined)
+	cfg := &Config{
+		values:  make(map[string]string),
+		sources: make(map[string]string),
 	}
 
-	dbPort := os.Getenv("DB_PORT")
-	if dbPort == "" {
-		log.Fatalf("DB_PORT: %s", errEnvVarNotDefined)
+	var errs []error
+
+	for _, s := range settings {
+		value, source := s.def, SourceDefault
+		if fileValue, ok := fileValues[s.key]; ok {
+			value, source = fileValue, SourceFile
+		}
+		if envValue, ok := os.LookupEnv(s.env); ok && (envValue != "" || s.clearable) {
+			value, source = envValue, SourceEnv
+		}
+		if flagValue, ok := flagValues[s.key]; ok {
+			value, source = flagValue, SourceFlag
+		}
+
+		cfg.values[s.key], cfg.sources[s.key] = value, source
+
+		if err := s.parse(cfg, strings.TrimSpace(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s (%s): %w", s.key, s.origin(source), err))
+		}
 	}
 
-	dbName := os.Getenv("DB_NAME")
-	if dbName == "" {
-		log.Fatalf("DB_NAME: %s", errEnvVarNotDefined)
+	var unknownKeys []string
+	for key := range fileValues {
+		if !isSettingKey(key) {
+			unknownKeys = append(unknownKeys, key)
+		}
 	}
+	slices.Sort(unknownKeys)
 
-	musicInfoAPIURL := os.Getenv("MUSIC_INFO_API_URL")
-	if musicInfoAPIURL == "" {
-		log.Fatalf("MUSIC_INFO_API_URL: %s", errEnvVarNotDefined)
+	for _, key := range unknownKeys {
+		errs = append(errs, fmt.Errorf("%s (%s %s): %w", key, SourceFile, configFile, errUnknownKey))
 	}
 
-	log.Info(successfulConfigLoad)
+	errs = append(errs, cfg.validate()...)
+
+	return cfg, rest, errors.Join(errs...)
+}
+
+// validate checks the settings that depend on each other.
+func (c *Config) validate() []error {
+	var errs []error
+
+	// Browsers refuse credentials for the wildcard origin, so it is a configuration error rather than a silent failure.
+	if c.CORSCredentials && slices.Contains(c.CORSOrigins, "*") {
+		errs = append(errs, fmt.Errorf("cors.allow_credentials: %w", errWildcardCredentials))
+	}
+
+	// database/sql silently lowers the idle connections to the open connections, which hides a misconfigured pool.
+	if c.DbMaxOpenConns > 0 && c.DbMaxIdleConns > c.DbMaxOpenConns {
+		errs = append(errs, fmt.Errorf("db.max_idle_conns: %w", errIdleConnsOverOpen))
+	}
 
-	return &Config{
-		Port:            port,
-		DbUser:          dbUser,
-		DbPassword:      dbPassword,
-		DbHost:          dbHost,
-		DbPort:          dbPort,
-		DbName:          dbName,
-		MusicInfoAPIURL: musicInfoAPIURL,
+	if (c.DbSSLCert == "") != (c.DbSSLKey == "") {
+		errs = append(errs, fmt.Errorf("db.sslcert: %w", errCertWithoutKey))
 	}
+
+	return errs
+}
+
+func parseFlags(args []string) (map[string]string, string, []string, error) {
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	values := make(map[string]string)
+
+	configFile := flags.String(configFileFlag, "", "path to a YAML or TOML config file (env "+configFileEnv+")")
+	for _, s := range settings {
+		flags.Func(s.flag(), s.usage+" (env "+s.env+")", func(value string) error {
+			values[s.key] = value
+			return nil
+		})
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, "", nil, err
+	}
+
+	return values, *configFile, flags.Args(), nil
 }
@@ -0,0 +1,332 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"songs-library-go/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolateEnv unsets the variables of every setting and of the config file for the test,
+// so the values of the environment running the tests don't leak into it.
+func isolateEnv(t *testing.T) {
+	t.Helper()
+
+	for _, env := range append([]string{configFileEnv}, settingEnvs()...) {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+}
+
+func settingEnvs() []string {
+	envs := make([]string, 0, len(settings))
+	for _, s := range settings {
+		envs = append(envs, s.env)
+	}
+
+	return envs
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing config file: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	isolateEnv(t)
+
+	file := writeConfigFile(t, "config.yaml", `
+server:
+  port: 9000
+  shutdown_timeout: 20s
+db:
+  host: db.internal
+  user: file-user
+log.level: debug
+`)
+
+	t.Setenv("PORT", "9100")
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("DB_USER", "")
+
+	cfg, rest, err := load([]string{"-config", file, "-server-port", "9200", "migrate", "-server-port", "1"})
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	if want := []string{"migrate", "-server-port", "1"}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("rest = %q, want %q", rest, want)
+	}
+
+	tests := []struct {
+		key        string
+		got        interface{}
+		want       interface{}
+		wantSource string
+	}{
+		{key: "server.port", got: cfg.Port, want: "9200", wantSource: SourceFlag},
+		{key: "db.host", got: cfg.DbHost, want: "env-host", wantSource: SourceEnv},
+		{key: "db.user", got: cfg.DbUser, want: "file-user", wantSource: SourceFile},
+		{key: "server.shutdown_timeout", got: cfg.ShutdownTimeout, want: 20 * time.Second, wantSource: SourceFile},
+		{key: "log.level", got: cfg.LogLevel.String(), want: "debug", wantSource: SourceFile},
+		{key: "db.name", got: cfg.DbName, want: "postgres", wantSource: SourceDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("%s = %v, want %v", tt.key, tt.got, tt.want)
+			}
+
+			if got := cfg.sources[tt.key]; got != tt.wantSource {
+				t.Errorf("source of %s = %q, want %q", tt.key, got, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestLoadClearableSettings(t *testing.T) {
+	isolateEnv(t)
+
+	t.Setenv("RATE_LIMIT_ROUTES", "")
+	t.Setenv("CORS_ALLOWED_METHODS", "")
+
+	cfg, _, err := load(nil)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	if len(cfg.RateLimitRoutes) != 0 {
+		t.Errorf("RateLimitRoutes = %v, want them cleared by the empty variable", cfg.RateLimitRoutes)
+	}
+
+	if want := []string{"GET", "POST", "PUT", "PATCH", "DELETE"}; !reflect.DeepEqual(cfg.CORSMethods, want) {
+		t.Errorf("CORSMethods = %q, want the default %q", cfg.CORSMethods, want)
+	}
+}
+
+func TestLoadConfigFileFromEnv(t *testing.T) {
+	isolateEnv(t)
+
+	t.Setenv(configFileEnv, writeConfigFile(t, "config.yml", "db.name: songs\n"))
+
+	cfg, _, err := load(nil)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	if cfg.DbName != "songs" {
+		t.Errorf("DbName = %q, want %q", cfg.DbName, "songs")
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name: "yaml tables, dotted keys, lists and routes",
+			file: "config.yaml",
+			content: `
+db:
+  host: db.internal
+  port: 5433
+cors.allowed_origins: [https://a.example, https://b.example]
+cors.allow_credentials: true
+rate_limit:
+  routes:
+    POST /songs/import: 5/1m
+    GET /songs: 120/1m
+similarity:
+  weight_text: 0.6
+jwt:
+  issuer:
+`,
+			want: map[string]string{
+				"db.host":                "db.internal",
+				"db.port":                "5433",
+				"cors.allowed_origins":   "https://a.example,https://b.example",
+				"cors.allow_credentials": "true",
+				"rate_limit.routes":      "GET /songs=120/1m,POST /songs/import=5/1m",
+				"similarity.weight_text": "0.6",
+				"jwt.issuer":             "",
+			},
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `
+cors.allowed_methods = ["GET", "POST"]
+
+[db]
+host = "db.internal"
+max_open_conns = 50
+
+[body_limit.routes]
+"POST /songs/import" = "64MiB"
+`,
+			want: map[string]string{
+				"cors.allowed_methods": "GET,POST",
+				"db.host":              "db.internal",
+				"db.max_open_conns":    "50",
+				"body_limit.routes":    "POST /songs/import=64MiB",
+			},
+		},
+		{
+			name:    "unknown format",
+			file:    "config.json",
+			content: `{"db": {"host": "db.internal"}}`,
+			wantErr: errUnknownFileFormat,
+		},
+		{
+			name:    "unsupported value",
+			file:    "config.toml",
+			content: "server.shutdown_timeout = 1979-05-27T07:32:00Z\n",
+			wantErr: errInvalidFileValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readFile(writeConfigFile(t, tt.file, tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readFile() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadReportsAllProblems(t *testing.T) {
+	isolateEnv(t)
+
+	file := writeConfigFile(t, "config.yaml", `
+db:
+  hots: db.internal
+  max_open_conns: 5
+  max_idle_conns: 10
+cors:
+  allowed_origins: "*"
+  allow_credentials: true
+`)
+
+	t.Setenv("PORT", "http")
+	t.Setenv("RATE_LIMIT_DEFAULT", "600")
+
+	_, _, err := load([]string{"-config", file, "-log-format", "xml"})
+	if err == nil {
+		t.Fatal("load() error = nil, want the invalid settings")
+	}
+
+	for _, want := range []string{
+		"server.port (env PORT)",
+		"rate_limit.default (env RATE_LIMIT_DEFAULT)",
+		"log.format (flag -log-format)",
+		"db.hots (file " + file + "): " + errUnknownKey.Error(),
+		"db.max_idle_conns: " + errIdleConnsOverOpen.Error(),
+		"cors.allow_credentials: " + errWildcardCredentials.Error(),
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("load() error = %q, want it to contain %q", err, want)
+		}
+	}
+
+	for _, wantErr := range []error{errInvalidValue, domain.ErrInvalidRateLimit, errUnknownKey} {
+		if !errors.Is(err, wantErr) {
+			t.Errorf("load() error = %v, want %v", err, wantErr)
+		}
+	}
+
+	if got := len(err.(interface{ Unwrap() []error }).Unwrap()); got != 6 {
+		t.Errorf("load() reported %d problems, want 6", got)
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	isolateEnv(t)
+
+	t.Setenv("DB_PASSWORD", "hunter2")
+	t.Setenv("DB_DSN", "postgres://app:hunter2@db/songs")
+
+	cfg, _, err := load([]string{"-jwt-hmac-secret", "signing-secret"})
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	var out strings.Builder
+	if err := cfg.Print(&out); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	printed := out.String()
+
+	for _, secret := range []string{"hunter2", "signing-secret"} {
+		if strings.Contains(printed, secret) {
+			t.Errorf("Print() leaked %q:\n%s", secret, printed)
+		}
+	}
+
+	for _, want := range []string{
+		`db.password: "[REDACTED]" # env DB_PASSWORD`,
+		`db.dsn: "[REDACTED]" # env DB_DSN`,
+		`jwt.hmac_secret: "[REDACTED]" # flag -jwt-hmac-secret`,
+		`db.replica_dsn: "" # default`,
+		`db.host: "localhost" # default`,
+		`rate_limit.routes: "GET /songs=120/1m" # default`,
+	} {
+		if !strings.Contains(printed, want+"\n") {
+			t.Errorf("Print() = %q, want a line %q", printed, want)
+		}
+	}
+
+	if got, want := strings.Count(printed, "\n"), len(settings); got != want {
+		t.Errorf("Print() wrote %d lines, want one per setting (%d)", got, want)
+	}
+}
+
+func TestPostgresDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "built from the connection settings",
+			cfg: Config{
+				DbHost: "localhost", DbPort: "5432", DbUser: "postgres", DbPassword: "it's a secret", DbName: "songs",
+				DbSSLMode: SSLModeDisable, DbConnectTimeout: 2500 * time.Millisecond,
+			},
+			want: `host=localhost port=5432 user=postgres password='it\'s a secret' dbname=songs sslmode=disable connect_timeout=3`,
+		},
+		{
+			name: "DSN overrides the connection settings",
+			cfg:  Config{DbHost: "localhost", DbDSN: "host=db dbname=songs", DbStatementTimeout: 30 * time.Second},
+			want: "host=db dbname=songs statement_timeout=30000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.PostgresDSN(); got != tt.want {
+				t.Errorf("PostgresDSN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	errUnknownFileFormat = errors.New("unknown config file format, expected .yaml, .yml or .toml")
+	errInvalidFileValue  = errors.New("invalid value type")
+)
+
+// readFile reads a YAML or TOML config file into raw values keyed by setting key. Settings can be nested in tables,
+// such as db: {host: localhost}, or written with dotted keys, such as db.host: localhost. Lists are read as
+// comma-separated values, and tables of routes as comma-separated "METHOD /pattern=value" entries.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make(map[string]interface{})
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, errUnknownFileFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	if err := flatten(values, "", tree); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// flatten adds the values of the tree to values, keyed by their dotted keys. Tables are flattened until their key is
+// the key of a setting.
+func flatten(values map[string]string, prefix string, tree map[string]interface{}) error {
+	for name, node := range tree {
+		key := prefix + name
+
+		table, isTable := node.(map[string]interface{})
+		if isTable && !isSettingKey(key) {
+			if err := flatten(values, key+".", table); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := fileValue(node)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+
+		values[key] = value
+	}
+
+	return nil
+}
+
+// fileValue formats a value decoded from a config file as the raw value of a setting.
+func fileValue(node interface{}) (string, error) {
+	switch node := node.(type) {
+	case nil:
+		return "", nil
+	case []interface{}:
+		items := make([]string, 0, len(node))
+		for _, item := range node {
+			value, err := fileValue(item)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, value)
+		}
+
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		entries := make([]string, 0, len(node))
+		for name, item := range node {
+			value, err := fileValue(item)
+			if err != nil {
+				return "", err
+			}
+
+			entries = append(entries, name+"="+value)
+		}
+		sort.Strings(entries)
+
+		return strings.Join(entries, ","), nil
+	case string, bool, int, int64, uint64, float64:
+		return fmt.Sprint(node), nil
+	default:
+		return "", fmt.Errorf("%w: %T", errInvalidFileValue, node)
+	}
+}
+
+func isSettingKey(key string) bool {
+	return slices.ContainsFunc(settings, func(s setting) bool { return s.key == key })
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"math"
+	"net/url"
+	"os"
+	"slices"
+	"songs-library-go/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errRequired          = errors.New("value is required")
+	errInvalidValue      = errors.New("invalid value")
+	errInvalidRouteEntry = errors.New("invalid route entry")
+	errInvalidByteSize   = errors.New("invalid size in bytes")
+)
+
+// parseRequired returns the value if it isn't empty.
+func parseRequired(value string) (string, error) {
+	if value == "" {
+		return "", errRequired
+	}
+
+	return value, nil
+}
+
+// parsePort parses a TCP port number.
+func parsePort(value string) (string, error) {
+	if value == "" {
+		return "", errRequired
+	}
+
+	port, err := strconv.Atoi(value)
+	if err != nil || port <= 0 || port > math.MaxUint16 {
+		return "", fmt.Errorf("%w: %q, expected a port number", errInvalidValue, value)
+	}
+
+	return value, nil
+}
+
+// parseCount parses a non-negative integer.
+func parseCount(value string) (int, error) {
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("%w: %q, expected a non-negative integer", errInvalidValue, value)
+	}
+
+	return count, nil
+}
+
+// parseFile returns the path of an optional file if the file exists.
+func parseFile(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	if _, err := os.Stat(value); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// parseDSN parses an optional Postgres connection string. URLs are converted to key=value pairs,
+// so parameters such as the statement timeout can be appended to them.
+func parseDSN(value string) (string, error) {
+	if !strings.HasPrefix(value, "postgres://") && !strings.HasPrefix(value, "postgresql://") {
+		return value, nil
+	}
+
+	dsn, err := pq.ParseURL(value)
+	if err != nil {
+		// The URL may carry a password, so it isn't repeated in the error.
+		return "", fmt.Errorf("%w, expected a postgres:// URL or key=value pairs", errInvalidValue)
+	}
+
+	return dsn, nil
+}
+
+// parseURL parses an optional absolute http or https URL, dropping a trailing slash.
+func parseURL(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: %q, expected an http or https URL", errInvalidValue, value)
+	}
+
+	return strings.TrimSuffix(value, "/"), nil
+}
+
+// parseDuration parses a duration such as 30s or 5m. Zero is only valid if positive isn't required.
+func parseDuration(value string, positive bool) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 || (positive && d == 0) {
+		return 0, fmt.Errorf("%w: %q, expected a duration such as 30s", errInvalidValue, value)
+	}
+
+	return d, nil
+}
+
+// parseBool parses a boolean such as true, false, 1 or 0.
+func parseBool(value string) (bool, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: %q, expected true or false", errInvalidValue, value)
+	}
+
+	return b, nil
+}
+
+// parseWeight parses a finite non-negative weight.
+func parseWeight(value string) (float64, error) {
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil || weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 1) {
+		return 0, fmt.Errorf("%w: %q, expected a non-negative number", errInvalidValue, value)
+	}
+
+	return weight, nil
+}
+
+// parseOneOf returns the value if it is one of the allowed values.
+func parseOneOf(value string, allowed ...string) (string, error) {
+	if !slices.Contains(allowed, value) {
+		return "", fmt.Errorf("%w: %q, expected one of %s", errInvalidValue, value, strings.Join(allowed, ", "))
+	}
+
+	return value, nil
+}
+
+// parseRouteValues parses comma-separated values of routes written as "METHOD /pattern=value" into values keyed by
+// method and chi route pattern.
+func parseRouteValues(value string) (map[string]string, error) {
+	values := make(map[string]string)
+
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		route, routeValue, found := strings.Cut(entry, "=")
+		method, pattern, hasPattern := strings.Cut(strings.TrimSpace(route), " ")
+		pattern = strings.TrimSpace(pattern)
+		if !found || !hasPattern || !strings.HasPrefix(pattern, "/") {
+			return nil, fmt.Errorf("%w: %s", errInvalidRouteEntry, entry)
+		}
+
+		values[strings.ToUpper(method)+" "+pattern] = strings.TrimSpace(routeValue)
+	}
+
+	return values, nil
+}
+
+// parseRouteRateLimits parses limits of routes written as "METHOD /pattern=requests/period",
+// such as "GET /songs=120/1m,POST /songs/import=5/1m".
+func parseRouteRateLimits(value string) (map[string]domain.RateLimit, error) {
+	values, err := parseRouteValues(value)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]domain.RateLimit, len(values))
+	for route, limitValue := range values {
+		if limits[route], err = domain.ParseRateLimit(limitValue); err != nil {
+			return nil, err
+		}
+	}
+
+	return limits, nil
+}
+
+// parseRouteBodyLimits parses body size limits of routes written as "METHOD /pattern=size",
+// such as "POST /songs/import=64MiB".
+func parseRouteBodyLimits(value string) (map[string]int64, error) {
+	values, err := parseRouteValues(value)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]int64, len(values))
+	for route, sizeValue := range values {
+		if limits[route], err = parseByteSize(sizeValue); err != nil {
+			return nil, err
+		}
+	}
+
+	return limits, nil
+}
+
+// parseByteSize parses a positive size in bytes with an optional KiB, MiB or GiB unit, such as 1048576 or 1MiB.
+func parseByteSize(value string) (int64, error) {
+	multiplier := int64(1)
+	for unit, unitBytes := range map[string]int64{"KiB": 1 << 10, "MiB": 1 << 20, "GiB": 1 << 30} {
+		if number, found := strings.CutSuffix(value, unit); found {
+			value, multiplier = strings.TrimSpace(number), unitBytes
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 || size > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("%w: %s", errInvalidByteSize, value)
+	}
+
+	return size * multiplier, nil
+}
+
+// parseList parses a comma-separated list, dropping empty items.
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,274 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"songs-library-go/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr error
+	}{
+		{value: "8888"},
+		{value: "65535"},
+		{value: "", wantErr: errRequired},
+		{value: "0", wantErr: errInvalidValue},
+		{value: "65536", wantErr: errInvalidValue},
+		{value: "http", wantErr: errInvalidValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parsePort(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parsePort(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+			}
+
+			if err == nil && got != tt.value {
+				t.Errorf("parsePort(%q) = %q, want %q", tt.value, got, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		positive bool
+		want     time.Duration
+		wantErr  bool
+	}{
+		{name: "seconds", value: "30s", want: 30 * time.Second},
+		{name: "compound", value: "1h30m", positive: true, want: 90 * time.Minute},
+		{name: "zero", value: "0s", want: 0},
+		{name: "zero when positive is required", value: "0s", positive: true, wantErr: true},
+		{name: "negative", value: "-1s", wantErr: true},
+		{name: "without a unit", value: "30", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDuration(tt.value, tt.positive)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDuration(%q, %t) error = %v, want error %t", tt.value, tt.positive, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseDuration(%q, %t) = %v, want %v", tt.value, tt.positive, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: ""},
+		{name: "key=value pairs are kept", value: "host=db user=app", want: "host=db user=app"},
+		{
+			name:  "URL is converted to key=value pairs",
+			value: "postgres://app:secret@db:5433/songs?sslmode=require",
+			want:  "dbname='songs' host='db' password='secret' port='5433' sslmode='require' user='app'",
+		},
+		{name: "postgresql scheme", value: "postgresql://db/songs", want: "dbname='songs' host='db'"},
+		{name: "malformed URL", value: "postgres://app:secret@db:port/songs", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDSN(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDSN(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			}
+
+			if err != nil && strings.Contains(err.Error(), "secret") {
+				t.Errorf("parseDSN(%q) error = %q, want it not to leak the password", tt.value, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseDSN(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: ""},
+		{value: "https://api.example.com/", want: "https://api.example.com"},
+		{value: "http://localhost:8080/v1", want: "http://localhost:8080/v1"},
+		{value: "ftp://example.com", wantErr: true},
+		{value: "example.com", wantErr: true},
+		{value: "https://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseURL(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseURL(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseURL(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWeight(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "0.5", want: 0.5},
+		{value: "0", want: 0},
+		{value: "2", want: 2},
+		{value: "-0.1", wantErr: true},
+		{value: "NaN", wantErr: true},
+		{value: "+Inf", wantErr: true},
+		{value: "half", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseWeight(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseWeight(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseWeight(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "1048576", want: 1 << 20},
+		{value: "512KiB", want: 512 << 10},
+		{value: "64MiB", want: 64 << 20},
+		{value: "2 GiB", want: 2 << 30},
+		{value: "0", wantErr: true},
+		{value: "-1MiB", wantErr: true},
+		{value: "1MB", wantErr: true},
+		{value: "1.5MiB", wantErr: true},
+		{value: "9223372036854775807GiB", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseByteSize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseByteSize(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, errInvalidByteSize) {
+				t.Errorf("parseByteSize(%q) error = %v, want %v", tt.value, err, errInvalidByteSize)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseByteSize(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRouteRateLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]domain.RateLimit
+		wantErr error
+	}{
+		{name: "empty", value: "", want: map[string]domain.RateLimit{}},
+		{
+			name:  "several routes",
+			value: "GET /songs=120/1m, post /songs/import = 5/1m,",
+			want: map[string]domain.RateLimit{
+				"GET /songs":         {Requests: 120, Period: time.Minute},
+				"POST /songs/import": {Requests: 5, Period: time.Minute},
+			},
+		},
+		{
+			name:  "later entry of the same route wins",
+			value: "GET /songs=120/1m,GET /songs=60/1m",
+			want:  map[string]domain.RateLimit{"GET /songs": {Requests: 60, Period: time.Minute}},
+		},
+		{name: "without a limit", value: "GET /songs", wantErr: errInvalidRouteEntry},
+		{name: "without a method", value: "/songs=120/1m", wantErr: errInvalidRouteEntry},
+		{name: "pattern without a slash", value: "GET songs=120/1m", wantErr: errInvalidRouteEntry},
+		{name: "invalid limit", value: "GET /songs=120", wantErr: domain.ErrInvalidRateLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRouteRateLimits(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseRouteRateLimits(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+			}
+
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRouteRateLimits(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRouteBodyLimits(t *testing.T) {
+	got, err := parseRouteBodyLimits("POST /songs/import=64MiB,PUT /songs/{id}=1024")
+	if err != nil {
+		t.Fatalf("parseRouteBodyLimits() error = %v", err)
+	}
+
+	want := map[string]int64{"POST /songs/import": 64 << 20, "PUT /songs/{id}": 1024}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRouteBodyLimits() = %v, want %v", got, want)
+	}
+
+	if _, err := parseRouteBodyLimits("POST /songs/import=lots"); !errors.Is(err, errInvalidByteSize) {
+		t.Errorf("parseRouteBodyLimits() error = %v, want %v", err, errInvalidByteSize)
+	}
+}
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "", want: nil},
+		{value: " , ,", want: nil},
+		{value: "GET,POST", want: []string{"GET", "POST"}},
+		{value: " https://a.example , https://b.example,", want: []string{"https://a.example", "https://b.example"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := parseList(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseList(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
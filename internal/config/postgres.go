@@ -0,0 +1,66 @@
+package config
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PostgresDSN returns the connection string of the database in key=value form. It is DbDSN if it is set,
+// and is built from the host, port, user, password, name, TLS and connect timeout settings otherwise.
+// The statement timeout is added to both.
+func (c *Config) PostgresDSN() string {
+	dsn := c.DbDSN
+	if dsn == "" {
+		var params []string
+		for _, param := range [][2]string{
+			{"host", c.DbHost},
+			{"port", c.DbPort},
+			{"user", c.DbUser},
+			{"password", c.DbPassword},
+			{"dbname", c.DbName},
+			{"sslmode", c.DbSSLMode},
+			{"sslrootcert", c.DbSSLRootCert},
+			{"sslcert", c.DbSSLCert},
+			{"sslkey", c.DbSSLKey},
+			{"connect_timeout", strconv.Itoa(int(math.Ceil(c.DbConnectTimeout.Seconds())))},
+		} {
+			if param[1] != "" {
+				params = append(params, param[0]+"="+quoteDSNValue(param[1]))
+			}
+		}
+
+		dsn = strings.Join(params, " ")
+	}
+
+	return c.withStatementTimeout(dsn)
+}
+
+// PostgresReplicaDSN returns the connection string of the read replica in key=value form with the statement timeout,
+// or an empty string if there is no replica.
+func (c *Config) PostgresReplicaDSN() string {
+	if c.DbReplicaDSN == "" {
+		return ""
+	}
+
+	return c.withStatementTimeout(c.DbReplicaDSN)
+}
+
+// withStatementTimeout appends the statement timeout to dsn as a run-time parameter of the connections.
+// Later parameters override earlier ones, so it takes precedence over a timeout in dsn.
+func (c *Config) withStatementTimeout(dsn string) string {
+	if c.DbStatementTimeout <= 0 {
+		return dsn
+	}
+
+	return dsn + " statement_timeout=" + strconv.FormatInt(c.DbStatementTimeout.Milliseconds(), 10)
+}
+
+// quoteDSNValue quotes a value of a key=value connection string if it is empty or has spaces, quotes or backslashes.
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const redacted = "[REDACTED]"
+
+// Print writes every setting with its value and the layer it was taken from, in the format of a YAML config file.
+// Values of secrets are redacted.
+func (c *Config) Print(w io.Writer) error {
+	for _, s := range settings {
+		value := c.values[s.key]
+		if s.secret && value != "" {
+			value = redacted
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s # %s\n", s.key, strconv.Quote(value), s.origin(c.sources[s.key])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,389 @@
+package config
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"songs-library-go/internal/tracing"
+	"strings"
+)
+
+// setting is a configuration value that can be set in the config file under key, in the environment variable env
+// and with the command line flag named after key. The raw value of the highest layer is parsed into the Config.
+type setting struct {
+	key    string
+	env    string
+	def    string
+	usage  string
+	secret bool
+	// clearable settings can be emptied by an empty environment variable, other settings ignore empty variables.
+	clearable bool
+	parse     func(cfg *Config, value string) error
+}
+
+// flag returns the name of the command line flag of the setting, such as db-host for db.host.
+func (s setting) flag() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(s.key)
+}
+
+// origin describes where a value of the setting from source was set, for error messages.
+func (s setting) origin(source string) string {
+	switch source {
+	case SourceEnv:
+		return "env " + s.env
+	case SourceFlag:
+		return "flag -" + s.flag()
+	default:
+		return source
+	}
+}
+
+// settings lists every setting in the order they are loaded and printed.
+var settings = []setting{
+	{
+		key: "server.port", env: "PORT", def: "8888", usage: "port the HTTP server listens on",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.Port, err = parsePort(value)
+			return err
+		},
+	},
+	{
+		key: "server.read_header_timeout", env: "SERVER_READ_HEADER_TIMEOUT", def: "10s", usage: "time to read request headers, 0 for no timeout",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.ReadHeaderTimeout, err = parseDuration(value, false)
+			return err
+		},
+	},
+	{
+		key: "server.read_timeout", env: "SERVER_READ_TIMEOUT", def: "0s", usage: "time to read whole requests, 0 for no timeout",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.ReadTimeout, err = parseDuration(value, false)
+			return err
+		},
+	},
+	{
+		key: "server.write_timeout", env: "SERVER_WRITE_TIMEOUT", def: "0s", usage: "time to write responses, 0 for no timeout",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.WriteTimeout, err = parseDuration(value, false)
+			return err
+		},
+	},
+	{
+		key: "server.idle_timeout", env: "SERVER_IDLE_TIMEOUT", def: "2m", usage: "time keep-alive connections are kept idle, 0 for the read timeout",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.IdleTimeout, err = parseDuration(value, false)
+			return err
+		},
+	},
+	{
+		key: "server.shutdown_timeout", env: "SHUTDOWN_TIMEOUT", def: "8s", usage: "time to drain requests and background work on shutdown",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.ShutdownTimeout, err = parseDuration(value, true)
+			return err
+		},
+	},
+	{
+		key: "db.host", env: "DB_HOST", def: "localhost", usage: "host of the database",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbHost, err = parseRequired(value)
+			return err
+		},
+	},
+	{
+		key: "db.port", env: "DB_PORT", def: "5432", usage: "port of the database",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbPort, err = parsePort(value)
+			return err
+		},
+	},
+	{
+		key: "db.user", env: "DB_USER", def: "postgres", usage: "user of the database",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbUser, err = parseRequired(value)
+			return err
+		},
+	},
+	{
+		key: "db.password", env: "DB_PASSWORD", usage: "password of the database user", secret: true,
+		parse: func(cfg *Config, value string) error {
+			cfg.DbPassword = value
+			return nil
+		},
+	},
+	{
+		key: "db.name", env: "DB_NAME", def: "postgres", usage: "name of the database",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbName, err = parseRequired(value)
+			return err
+		},
+	},
+	{
+		key: "db.dsn", env: "DB_DSN", secret: true,
+		usage: "connection string of the database as a URL or key=value pairs, overrides the other connection settings of the database",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbDSN, err = parseDSN(value)
+			return err
+		},
+	},
+	{
+		key: "db.sslmode", env: "DB_SSLMODE", def: SSLModeDisable, usage: "TLS mode of the database connection: disable, require, verify-ca or verify-full",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbSSLMode, err = parseOneOf(value, SSLModeDisable, SSLModeRequire, SSLModeVerifyCA, SSLModeVerifyFull)
+			return err
+		},
+	},
+	{
+		key: "db.sslrootcert", env: "DB_SSLROOTCERT", usage: "PEM file of the certificate authorities verifying the database server",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbSSLRootCert, err = parseFile(value)
+			return err
+		},
+	},
+	{
+		key: "db.sslcert", env: "DB_SSLCERT", usage: "PEM file of the client certificate for the database",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbSSLCert, err = parseFile(value)
+			return err
+		},
+	},
+	{
+		key: "db.sslkey", env: "DB_SSLKEY", usage: "PEM file of the key of the client certificate for the database",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbSSLKey, err = parseFile(value)
+			return err
+		},
+	},
+	{
+		key: "db.connect_timeout", env: "DB_CONNECT_TIMEOUT", def: "10s", usage: "time to connect to the database, in whole seconds, 0 for no timeout",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbConnectTimeout, err = parseDuration(value, false)
+			return err
+		},
+	},
+	{
+		key: "db.statement_timeout", env: "DB_STATEMENT_TIMEOUT", def: "0s", usage: "time the database runs a statement before canceling it, 0 for no timeout",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbStatementTimeout, err = parseDuration(value, false)
+			return err
+		},
+	},
+	{
+		key: "db.max_open_conns", env: "DB_MAX_OPEN_CONNS", def: "25", usage: "maximum number of open connections to the database, 0 for no limit",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbMaxOpenConns, err = parseCount(value)
+			return err
+		},
+	},
+	{
+		key: "db.max_idle_conns", env: "DB_MAX_IDLE_CONNS", def: "10", usage: "maximum number of idle connections to the database",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbMaxIdleConns, err = parseCount(value)
+			return err
+		},
+	},
+	{
+		key: "db.conn_max_lifetime", env: "DB_CONN_MAX_LIFETIME", def: "30m", usage: "time a database connection is reused before it is closed, 0 for no limit",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbConnMaxLifetime, err = parseDuration(value, false)
+			return err
+		},
+	},
+	{
+		key: "db.replica_dsn", env: "DB_REPLICA_DSN", secret: true,
+		usage: "connection string of a read replica of the database serving song lists and texts, as a URL or key=value pairs",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.DbReplicaDSN, err = parseDSN(value)
+			return err
+		},
+	},
+	{
+		key: "music_info_api.url", env: "MUSIC_INFO_API_URL", usage: "base URL of the music info API, songs aren't enriched without it",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.MusicInfoAPIURL, err = parseURL(value)
+			return err
+		},
+	},
+	{
+		key: "idempotency.ttl", env: "IDEMPOTENCY_TTL", def: "24h", usage: "time responses are kept for idempotency keys",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.IdempotencyTTL, err = parseDuration(value, true)
+			return err
+		},
+	},
+	{
+		key: "plays.flush_interval", env: "PLAYS_FLUSH_INTERVAL", def: "5s", usage: "interval of flushing buffered plays",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.PlaysFlushInterval, err = parseDuration(value, true)
+			return err
+		},
+	},
+	{
+		key: "similarity.weight_text", env: "SIMILARITY_WEIGHT_TEXT", def: "0.5", usage: "weight of the text similarity",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.SimilarityWeights.Text, err = parseWeight(value)
+			return err
+		},
+	},
+	{
+		key: "similarity.weight_artist", env: "SIMILARITY_WEIGHT_ARTIST", def: "0.2", usage: "weight of the artist similarity",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.SimilarityWeights.Artist, err = parseWeight(value)
+			return err
+		},
+	},
+	{
+		key: "similarity.weight_tags", env: "SIMILARITY_WEIGHT_TAGS", def: "0.2", usage: "weight of the shared tags similarity",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.SimilarityWeights.Tags, err = parseWeight(value)
+			return err
+		},
+	},
+	{
+		key: "similarity.weight_year", env: "SIMILARITY_WEIGHT_YEAR", def: "0.1", usage: "weight of the release year similarity",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.SimilarityWeights.Year, err = parseWeight(value)
+			return err
+		},
+	},
+	{
+		key: "tracing.exporter", env: "OTEL_TRACES_EXPORTER", def: tracing.ExporterNone, usage: "traces exporter: none, otlp or stdout",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.TracesExporter, err = parseOneOf(value, tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout)
+			return err
+		},
+	},
+	{
+		key: "log.level", env: "LOG_LEVEL", def: log.InfoLevel.String(), usage: "log level: trace, debug, info, warn, error, fatal or panic",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.LogLevel, err = log.ParseLevel(value)
+			return err
+		},
+	},
+	{
+		key: "log.format", env: "LOG_FORMAT", def: logging.FormatText, usage: "log format: text or json",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.LogFormat, err = parseOneOf(value, logging.FormatText, logging.FormatJSON)
+			return err
+		},
+	},
+	{
+		key: "rate_limit.backend", env: "RATE_LIMIT_BACKEND", def: RateLimitBackendMemory, usage: "rate limiter backend: none, memory or postgres",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.RateLimitBackend, err = parseOneOf(value, RateLimitBackendNone, RateLimitBackendMemory, RateLimitBackendPostgres)
+			return err
+		},
+	},
+	{
+		key: "rate_limit.default", env: "RATE_LIMIT_DEFAULT", def: "600/1m", usage: "rate limit of routes without their own limit, as requests/period",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.RateLimitDefault, err = domain.ParseRateLimit(value)
+			return err
+		},
+	},
+	{
+		key: "rate_limit.routes", env: "RATE_LIMIT_ROUTES", def: "GET /songs=120/1m", clearable: true,
+		usage: "rate limits of routes, as comma-separated METHOD /pattern=requests/period",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.RateLimitRoutes, err = parseRouteRateLimits(value)
+			return err
+		},
+	},
+	{
+		key: "rate_limit.ip", env: "RATE_LIMIT_IP", def: "1200/1m", usage: "rate limit of all requests of an IP address before authentication, as requests/period",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.RateLimitIP, err = domain.ParseRateLimit(value)
+			return err
+		},
+	},
+	{
+		key: "cors.allowed_origins", env: "CORS_ALLOWED_ORIGINS", clearable: true,
+		usage: "comma-separated origins allowed to call the API from browsers, CORS is disabled without them",
+		parse: func(cfg *Config, value string) error {
+			cfg.CORSOrigins = parseList(value)
+			return nil
+		},
+	},
+	{
+		key: "cors.allowed_methods", env: "CORS_ALLOWED_METHODS", def: "GET,POST,PUT,PATCH,DELETE", usage: "comma-separated methods allowed for CORS requests",
+		parse: func(cfg *Config, value string) error {
+			cfg.CORSMethods = parseList(strings.ToUpper(value))
+			return nil
+		},
+	},
+	{
+		key: "cors.allowed_headers", env: "CORS_ALLOWED_HEADERS", def: "Accept,Authorization,Content-Type,X-API-Key,X-Request-ID,Idempotency-Key",
+		usage: "comma-separated headers allowed for CORS requests",
+		parse: func(cfg *Config, value string) error {
+			cfg.CORSHeaders = parseList(value)
+			return nil
+		},
+	},
+	{
+		key: "cors.allow_credentials", env: "CORS_ALLOW_CREDENTIALS", def: "false", usage: "allow CORS requests with credentials",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.CORSCredentials, err = parseBool(value)
+			return err
+		},
+	},
+	{
+		key: "cors.max_age", env: "CORS_MAX_AGE", def: "10m", usage: "time browsers cache preflight responses",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.CORSMaxAge, err = parseDuration(value, false)
+			return err
+		},
+	},
+	{
+		key: "body_limit.default", env: "BODY_LIMIT_DEFAULT", def: "1MiB", usage: "size limit of request bodies of routes without their own limit",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.BodyLimitDefault, err = parseByteSize(value)
+			return err
+		},
+	},
+	{
+		key: "body_limit.routes", env: "BODY_LIMIT_ROUTES", def: "POST /songs/import=64MiB", clearable: true,
+		usage: "size limits of request bodies of routes, as comma-separated METHOD /pattern=size",
+		parse: func(cfg *Config, value string) (err error) {
+			cfg.BodyLimitRoutes, err = parseRouteBodyLimits(value)
+			return err
+		},
+	},
+	{
+		key: "jwt.hmac_secret", env: "JWT_HMAC_SECRET", usage: "secret of JWTs signed with HMAC", secret: true,
+		parse: func(cfg *Config, value string) error {
+			cfg.JWTHMACSecret = []byte(value)
+			return nil
+		},
+	},
+	{
+		key: "jwt.rsa_public_key_file", env: "JWT_RSA_PUBLIC_KEY_FILE", usage: "PEM file of the public key of JWTs signed with RSA",
+		parse: func(cfg *Config, value string) error {
+			if value == "" {
+				return nil
+			}
+
+			pemBytes, err := os.ReadFile(value)
+			if err != nil {
+				return err
+			}
+
+			cfg.JWTRSAPublicKey, err = jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+			return err
+		},
+	},
+	{
+		key: "jwt.issuer", env: "JWT_ISSUER", usage: "required issuer of JWTs",
+		parse: func(cfg *Config, value string) error {
+			cfg.JWTIssuer = value
+			return nil
+		},
+	},
+	{
+		key: "jwt.audience", env: "JWT_AUDIENCE", usage: "required audience of JWTs",
+		parse: func(cfg *Config, value string) error {
+			cfg.JWTAudience = value
+			return nil
+		},
+	},
+}
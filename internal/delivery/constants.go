@@ -1,20 +1,93 @@
 package delivery
 
+import "time"
+
 // Default constants for pagination.
 const (
-	DefaultPage       = 1
-	DefaultSongsLimit = 10
-	DefaultVerseLimit = 2
+	DefaultPage         = 1
+	DefaultSongsLimit   = 10
+	DefaultVerseLimit   = 2
+	DefaultOnConflict   = "skip"
+	DefaultExportFormat = "ndjson"
+	DefaultPeriod       = "7d"
+	DefaultChartLimit   = 10
+	DefaultSimilarLimit = 10
+	DefaultRandomCount  = 1
+)
+
+// MaxPlayClockSkew is how far in the future the time of a recorded play may be, to allow for client clock skew.
+const MaxPlayClockSkew = time.Minute
+
+// MaxBatchOperations is the maximum number of operations accepted in a single batch request.
+const MaxBatchOperations = 1000
+
+// Media types accepted for patching songs and used for error responses.
+const (
+	ContentTypeProblem    = "application/problem+json"
+	ContentTypeMergePatch = "application/merge-patch+json"
+	ContentTypeJSONPatch  = "application/json-patch+json"
+)
+
+// Constants for idempotent requests.
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	MaxIdempotencyKeyLength  = 255
+	MaxIdempotentBodySize    = 1 << 20
+)
+
+// Constants for request correlation.
+const (
+	HeaderRequestID    = "X-Request-ID"
+	MaxRequestIDLength = 128
+	MesAccessLog       = "request served"
+)
+
+// Constants for request body size limits.
+const (
+	MesBodyTooLarge = "request body of this route can be at most %d bytes"
+)
+
+// Constants for rate limiting.
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+	HeaderRetryAfter         = "Retry-After"
+	MesRateLimited           = "too many requests, retry after the number of seconds in the Retry-After header"
+)
+
+// Constants for authentication.
+const (
+	HeaderAPIKey          = "X-API-Key"
+	AuthSchemeBearer      = "Bearer"
+	SwaggerPathPrefix     = "/swagger/"
+	LivenessPath          = "/healthz"
+	ReadinessPath         = "/readyz"
+	MetricsPath           = "/metrics"
+	MesAuthenticationHint = "send an API key in the X-API-Key header or a bearer token in the Authorization header"
 )
 
 // Clarifying messages for input validation errors.
 const (
-	MesInvalidFilterName      = "filters can be only group, song, release_date, text or link"
-	MesEmptyFilter            = "valid filter name with empty value"
-	MesInvalidGetSongsParam   = "page must be a positive integer, limit must be a positive integer and can't be greater than 100, group and song must have at least 1 character and can have at most 100 characters, field release_date must be a valid date in the format `dd.mm.yyyy`, field text must have at least 1 character and can have at most 100 characters, field link must be a valid URL"
-	MesInvalidIDInput         = "id must be a positive integer"
-	MesInvalidPaginationParam = "page must be a positive integer, limit must be a positive integer and can't be greater than 100"
-	MesEmptyUpdateSongInput   = "at least one field must be provided for update"
-	MesInvalidUpdateSongInput = "fields group and song must have at least 1 character and can have at most 100 characters, field release_date must be a valid date in the format `dd.mm.yyyy`, field text must have at least 1 character and can have at most 10,000 characters, field link must be a valid URL"
-	MesInvalidCreateSongInput = "fields group and song are required and must have at least 1 character and can have at most 100 characters"
+	MesInvalidFilterName           = "filters can be only group, song, release_date, text or link"
+	MesEmptyFilter                 = "valid filter name with empty value"
+	MesInvalidIDInput              = "id must be a positive integer"
+	MesEmptyUpdateSongInput        = "at least one field must be provided for update"
+	MesInvalidMergePatch           = "merge patch can contain only fields group, song, release_date, text and link, only release_date, text and link can be cleared with null"
+	MesUnsupportedPatchType        = "Content-Type must be application/merge-patch+json or application/json-patch+json"
+	MesInvalidBatchSize            = "operations must contain at least 1 and at most 1000 operations"
+	MesInvalidBatchOperation       = "op must be create, update or delete"
+	MesInvalidBatchOperations      = "atomic batch contains invalid operations"
+	MesNotInteger                  = "must be an integer"
+	MesNotBoolean                  = "must be true or false"
+	MesNotNumber                   = "must be a number"
+	MesUnknownFilter               = "is not a known filter"
+	MesEmptyFilterValue            = "can't be empty"
+	MesInvalidIdempotencyKey       = "Idempotency-Key header can have at most 255 characters"
+	MesIdempotentBodyTooLarge      = "requests with Idempotency-Key header can have a body of at most 1 MiB"
+	MesIdempotencyKeyReused        = "Idempotency-Key has already been used for a different request"
+	MesIdempotentRequestInProgress = "request with this Idempotency-Key is still being processed, retry later"
+	MesPlayedAtInFuture            = "must not be in the future"
 )
@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// CreateAnnotationDto represents the data transfer object for annotating a range of lines or characters of a verse.
+// Verses are counted from 0 across the whole song text, ranges are half-open and counted from 0 within the verse.
+type CreateAnnotationDto struct {
+	Body       string `json:"body" validate:"required,max=5000" example:"The narrator watches from outside the window"`
+	VerseIndex int    `json:"verse_index" validate:"gte=0" example:"0"`
+	Unit       string `json:"unit" validate:"required,oneof=line char" example:"line"`
+	Start      int    `json:"start" validate:"gte=0" example:"0"`
+	End        int    `json:"end" validate:"gtfield=Start" example:"2"`
+}
+
+// VoteDto represents the data transfer object for voting on an annotation. A value of 0 withdraws the vote.
+type VoteDto struct {
+	Value int `json:"value" validate:"oneof=-1 0 1" example:"1"`
+}
+
+// AnnotationDto represents the data transfer object for an annotation of a song.
+type AnnotationDto struct {
+	ID         int32     `json:"id" example:"1"`
+	SongID     int32     `json:"song_id" example:"1"`
+	AuthorID   int32     `json:"author_id" example:"7"`
+	IsAuthor   bool      `json:"is_author" example:"true"`
+	Body       string    `json:"body" example:"The narrator watches from outside the window"`
+	VerseIndex int       `json:"verse_index" example:"0"`
+	Unit       string    `json:"unit" example:"line"`
+	Start      int       `json:"start" example:"0"`
+	End        int       `json:"end" example:"2"`
+	AnchorText string    `json:"anchor_text" example:"Niemand kann das Bild beschreiben\nGegen seine Fensterscheibe"`
+	Stale      bool      `json:"stale" example:"false"`
+	Score      int       `json:"score" example:"12"`
+	MyVote     int       `json:"my_vote" example:"1"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-12-08T12:00:00Z"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2024-12-08T12:00:00Z"`
+}
+
+// AnnotationsDto represents the data transfer object for the annotations of a page of verses and total page count.
+type AnnotationsDto struct {
+	Annotations []AnnotationDto `json:"annotations"`
+	TotalPages  int             `json:"total_pages" example:"3"`
+}
+
+// ScoreDto represents the data transfer object for the vote score of an annotation.
+type ScoreDto struct {
+	Score int `json:"score" example:"12"`
+}
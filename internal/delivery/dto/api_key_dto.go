@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// IssueAPIKeyDto represents the data transfer object for issuing a new API key.
+type IssueAPIKeyDto struct {
+	Name string `json:"name" validate:"required,min=1,max=100" example:"ci"`
+	Role string `json:"role" validate:"required,oneof=viewer editor admin" example:"editor"`
+}
+
+// APIKeyDto represents the data transfer object for an issued API key without its secret.
+type APIKeyDto struct {
+	ID        int32      `json:"id" example:"1"`
+	Name      string     `json:"name" example:"ci"`
+	Role      string     `json:"role" example:"editor"`
+	Prefix    string     `json:"prefix" example:"sl_Zm9vYmFy"`
+	CreatedAt time.Time  `json:"created_at" example:"2024-11-03T12:00:00Z"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" example:"2024-11-04T12:00:00Z"`
+}
+
+// IssuedAPIKeyDto represents the data transfer object for a newly issued API key together with its secret, which is shown only once.
+type IssuedAPIKeyDto struct {
+	APIKeyDto
+	Key string `json:"key" example:"sl_Zm9vYmFyYmF6cXV4..."`
+}
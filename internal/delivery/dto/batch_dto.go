@@ -0,0 +1,49 @@
+package dto
+
+import "encoding/json"
+
+// BatchInputDto represents the request body for applying a list of mixed song operations.
+type BatchInputDto struct {
+	Atomic     bool                     `json:"atomic" example:"true"`
+	Operations []BatchOperationInputDto `json:"operations"`
+}
+
+// BatchOperationInputDto represents a single create, update or delete operation in a batch request.
+type BatchOperationInputDto struct {
+	Op   string          `json:"op" example:"update"`
+	ID   int             `json:"id,omitempty" example:"1"`
+	Data json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+// BatchDto represents the data transfer object for a validated batch of song operations.
+type BatchDto struct {
+	Atomic     bool
+	Operations []BatchOperationDto
+}
+
+// BatchOperationDto represents a validated batch operation. Operations with a non-empty Error failed validation and are not applied.
+type BatchOperationDto struct {
+	Op     string
+	SongID int
+	Create CreateSongDto
+	Update SongParamsDto
+	Error  string
+}
+
+// BatchResultDto represents the data transfer object for the results of a batch of song operations.
+type BatchResultDto struct {
+	Atomic    bool                      `json:"atomic" example:"true"`
+	Succeeded int                       `json:"succeeded" example:"2"`
+	Failed    int                       `json:"failed" example:"0"`
+	Results   []BatchOperationResultDto `json:"results"`
+}
+
+// BatchOperationResultDto represents the data transfer object for the result of a single batch operation.
+type BatchOperationResultDto struct {
+	Index  int      `json:"index" example:"0"`
+	Op     string   `json:"op" example:"update"`
+	Status int      `json:"status" example:"200"`
+	Song   *SongDto `json:"song,omitempty"`
+	Code   string   `json:"code,omitempty" example:"song_not_found"`
+	Error  string   `json:"error,omitempty" example:"song with this id not found"`
+}
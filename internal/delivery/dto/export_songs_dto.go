@@ -0,0 +1,7 @@
+package dto
+
+// ExportSongsDto represents the data transfer object for exporting songs with filters.
+type ExportSongsDto struct {
+	Filters SongParamsDto `validate:"required" example:"{\"group\":\"Rammstein\"}"`
+	Format  string        `json:"format" validate:"required,oneof=csv ndjson json" example:"csv"`
+}
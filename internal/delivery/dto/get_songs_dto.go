@@ -4,4 +4,8 @@ package dto
 type GetSongsDto struct {
 	Filters          SongParamsDto       `validate:"required" example:"{\"release_date\":\"2024-10-04\"}"`
 	PaginationParams PaginationParamsDto `validate:"required" example:"{\"page\":1, \"limit\":10}"`
+	Favorited        bool                `json:"favorited" example:"true"`
+	MinRating        float64             `json:"min_rating" validate:"omitempty,gte=1,lte=5" example:"4"`
+	Sort             string              `json:"sort" validate:"omitempty,oneof=popular rating" example:"popular"`
+	Period           string              `json:"period" validate:"period" example:"7d"`
 }
@@ -0,0 +1,16 @@
+package dto
+
+// HealthCheckDto represents the data transfer object for the result of checking a dependency.
+type HealthCheckDto struct {
+	Name      string  `json:"name" example:"database"`
+	Status    string  `json:"status" example:"up"`
+	Critical  bool    `json:"critical" example:"true"`
+	LatencyMs float64 `json:"latency_ms" example:"1.25"`
+	Error     string  `json:"error,omitempty" example:"dial tcp: connection refused"`
+}
+
+// HealthDto represents the data transfer object for the health of the server and, for readiness, of its dependencies.
+type HealthDto struct {
+	Status string           `json:"status" example:"up"`
+	Checks []HealthCheckDto `json:"checks,omitempty"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// ImportParamsDto represents the data transfer object for songs import parameters.
+type ImportParamsDto struct {
+	Format     string `json:"format" validate:"required,oneof=csv ndjson" example:"csv"`
+	OnConflict string `json:"on_conflict" validate:"required,oneof=skip update fail" example:"skip"`
+}
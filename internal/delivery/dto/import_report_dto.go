@@ -0,0 +1,21 @@
+package dto
+
+// ImportReportDto represents the data transfer object for the result of a songs import.
+type ImportReportDto struct {
+	Total   int            `json:"total" example:"2"`
+	Created int            `json:"created" example:"1"`
+	Updated int            `json:"updated" example:"0"`
+	Skipped int            `json:"skipped" example:"1"`
+	Failed  int            `json:"failed" example:"0"`
+	Rows    []ImportRowDto `json:"rows"`
+}
+
+// ImportRowDto represents the data transfer object for the result of importing a single row.
+type ImportRowDto struct {
+	Row    int    `json:"row" example:"1"`
+	Status string `json:"status" example:"created"`
+	ID     int32  `json:"id,omitempty" example:"1"`
+	Group  string `json:"group,omitempty" example:"Rammstein"`
+	Song   string `json:"song,omitempty" example:"Weit Weg"`
+	Error  string `json:"error,omitempty" example:"song with this name by this group already exist"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// ImportSongDto represents a single row of a songs import file.
+type ImportSongDto struct {
+	Group       string  `json:"group" example:"Rammstein"`
+	Song        string  `json:"song" example:"Weit Weg"`
+	ReleaseDate *string `json:"release_date,omitempty" example:"17.05.2019"`
+	Text        *string `json:"text,omitempty" example:"Niemand kann das Bild beschreiben"`
+	Link        *string `json:"link,omitempty" example:"https://www.youtube.com/watch?v=N9AalJuwLyQ&ab_channel=Rammstein-Topic"`
+}
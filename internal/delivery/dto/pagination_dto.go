@@ -2,6 +2,6 @@ package dto
 
 // PaginationParamsDto represents the data transfer object for pagination parameters.
 type PaginationParamsDto struct {
-	Page  int `validate:"required,gte=1" example:"1"`
-	Limit int `validate:"required,gte=1,lte=100" example:"3"`
+	Page  int `json:"page" validate:"required,gte=1" example:"1"`
+	Limit int `json:"limit" validate:"required,gte=1,lte=100" example:"3"`
 }
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// RecordPlayDto represents the data transfer object for recording a listen of a song.
+// Without played_at the play is recorded at the time of the request.
+type RecordPlayDto struct {
+	Source   string    `json:"source" validate:"max=32" example:"mobile"`
+	PlayedAt time.Time `json:"played_at" example:"2024-11-24T12:00:00Z"`
+}
+
+// ChartParamsDto represents the data transfer object for the period and size of a chart.
+type ChartParamsDto struct {
+	Period string `json:"period" validate:"period" example:"7d"`
+	Limit  int    `json:"limit" validate:"required,gte=1,lte=100" example:"10"`
+}
+
+// ChartEntryDto represents the data transfer object for a song at a rank of a chart.
+type ChartEntryDto struct {
+	Rank  int     `json:"rank" example:"1"`
+	Plays int64   `json:"plays" example:"1024"`
+	Song  SongDto `json:"song"`
+}
+
+// ChartDto represents the data transfer object for the most played songs in a period.
+type ChartDto struct {
+	Period  string          `json:"period" example:"7d"`
+	Entries []ChartEntryDto `json:"entries"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+// PlaylistInputDto represents the data transfer object for creating or replacing a playlist.
+type PlaylistInputDto struct {
+	Name        string `json:"name" validate:"required,min=1,max=100" example:"Road trip"`
+	Description string `json:"description" validate:"max=1000" example:"Songs for long drives"`
+	IsPublic    bool   `json:"is_public" example:"false"`
+}
+
+// AddPlaylistEntryDto represents the data transfer object for adding a song to a playlist.
+// A zero position appends the song to the end of the playlist.
+type AddPlaylistEntryDto struct {
+	SongID   int32 `json:"song_id" validate:"required,gte=1" example:"1"`
+	Position int   `json:"position,omitempty" validate:"gte=0" example:"2"`
+}
+
+// ReorderPlaylistDto represents the data transfer object for setting the order of all entries of a playlist.
+type ReorderPlaylistDto struct {
+	EntryIDs []int32 `json:"entry_ids" validate:"required,unique,dive,gte=1" example:"3,1,2"`
+}
+
+// PlaylistDto represents the data transfer object for a playlist without its songs.
+type PlaylistDto struct {
+	ID          int32     `json:"id" example:"1"`
+	Name        string    `json:"name" example:"Road trip"`
+	Description string    `json:"description,omitempty" example:"Songs for long drives"`
+	IsPublic    bool      `json:"is_public" example:"false"`
+	IsOwner     bool      `json:"is_owner" example:"true"`
+	SongsCount  int       `json:"songs_count" example:"12"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-11-17T12:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2024-11-17T12:00:00Z"`
+}
+
+// PlaylistsDto represents the data transfer object for a collection of playlists and total page count.
+type PlaylistsDto struct {
+	Playlists  []PlaylistDto `json:"playlists"`
+	TotalPages int           `json:"total_pages" example:"1"`
+}
+
+// PlaylistEntryDto represents the data transfer object for a song at a position in a playlist.
+type PlaylistEntryDto struct {
+	ID       int32     `json:"id" example:"3"`
+	Position int       `json:"position" example:"1"`
+	AddedAt  time.Time `json:"added_at" example:"2024-11-17T12:00:00Z"`
+	Song     SongDto   `json:"song"`
+}
+
+// PlaylistWithEntriesDto represents the data transfer object for a playlist with a page of its entries.
+type PlaylistWithEntriesDto struct {
+	PlaylistDto
+	Entries    []PlaylistEntryDto `json:"entries"`
+	TotalPages int                `json:"total_pages" example:"1"`
+}
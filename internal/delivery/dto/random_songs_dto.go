@@ -0,0 +1,14 @@
+package dto
+
+// RandomSongsParamsDto represents the data transfer object for retrieving random songs with the filters of GetSongsDto.
+type RandomSongsParamsDto struct {
+	Filters   SongParamsDto `validate:"required" example:"{\"group\":\"Muse\"}"`
+	Count     int           `json:"count" validate:"required,gte=1,lte=100" example:"5"`
+	Favorited bool          `json:"favorited" example:"true"`
+	MinRating float64       `json:"min_rating" validate:"omitempty,gte=1,lte=5" example:"4"`
+}
+
+// RandomSongsDto represents the data transfer object for a collection of random songs.
+type RandomSongsDto struct {
+	Songs []SongDto `json:"songs"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// RateSongDto represents the data transfer object for rating a song with an optional review.
+type RateSongDto struct {
+	Rating int    `json:"rating" validate:"required,gte=1,lte=5" example:"5"`
+	Review string `json:"review" validate:"max=2000" example:"A classic"`
+}
+
+// ModerateReviewDto represents the data transfer object for setting the moderation status of a review.
+type ModerateReviewDto struct {
+	Status string `json:"status" validate:"required,oneof=pending approved rejected" example:"approved"`
+}
+
+// GetReviewsDto represents the data transfer object for retrieving reviews by their moderation status.
+type GetReviewsDto struct {
+	Status           string              `json:"status" validate:"required,oneof=pending approved rejected" example:"pending"`
+	PaginationParams PaginationParamsDto `validate:"required"`
+}
+
+// RatingDto represents the data transfer object for the rating of a song by the authenticated user.
+type RatingDto struct {
+	SongID       int32     `json:"song_id" example:"1"`
+	Rating       int       `json:"rating" example:"5"`
+	Review       string    `json:"review,omitempty" example:"A classic"`
+	ReviewStatus string    `json:"review_status,omitempty" example:"pending"`
+	CreatedAt    time.Time `json:"created_at" example:"2024-12-01T12:00:00Z"`
+	UpdatedAt    time.Time `json:"updated_at" example:"2024-12-01T12:00:00Z"`
+}
+
+// ReviewDto represents the data transfer object for a review of a song.
+type ReviewDto struct {
+	ID        int32     `json:"id" example:"1"`
+	SongID    int32     `json:"song_id" example:"1"`
+	Rating    int       `json:"rating" example:"5"`
+	Review    string    `json:"review" example:"A classic"`
+	Status    string    `json:"status" example:"approved"`
+	CreatedAt time.Time `json:"created_at" example:"2024-12-01T12:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-12-01T12:00:00Z"`
+}
+
+// ReviewsDto represents the data transfer object for a collection of reviews and total page count.
+type ReviewsDto struct {
+	Reviews    []ReviewDto `json:"reviews"`
+	TotalPages int         `json:"total_pages" example:"1"`
+}
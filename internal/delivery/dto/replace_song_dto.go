@@ -0,0 +1,10 @@
+package dto
+
+// ReplaceSongDto represents the data transfer object for replacing all fields of a song. Omitted optional fields are cleared.
+type ReplaceSongDto struct {
+	Group       string  `json:"group" validate:"required,max=100" example:"Rammstein"`
+	Song        string  `json:"song" validate:"required,max=100" example:"Weit Weg"`
+	ReleaseDate *string `json:"release_date,omitempty" validate:"omitempty,customDate" example:"17.05.2019"`
+	Text        *string `json:"text,omitempty" validate:"omitempty,min=1,max=10000" example:"Niemand kann das Bild beschreiben"`
+	Link        *string `json:"link,omitempty" validate:"omitempty,url" example:"https://www.youtube.com/watch?v=N9AalJuwLyQ&ab_channel=Rammstein-Topic"`
+}
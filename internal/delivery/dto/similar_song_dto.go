@@ -0,0 +1,18 @@
+package dto
+
+// SimilarSongsParamsDto represents the data transfer object for the number of similar songs to retrieve.
+type SimilarSongsParamsDto struct {
+	Limit int `json:"limit" validate:"required,gte=1,lte=50" example:"10"`
+}
+
+// SimilarSongDto represents the data transfer object for a song similar to another song.
+// Score is the weighted similarity of the songs, higher is more similar.
+type SimilarSongDto struct {
+	Score float64 `json:"score" example:"0.72"`
+	Song  SongDto `json:"song"`
+}
+
+// SimilarSongsDto represents the data transfer object for the songs most similar to a song, most similar first.
+type SimilarSongsDto struct {
+	Songs []SimilarSongDto `json:"songs"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "encoding/json"
+
+// SongPatchDto represents the data transfer object for a validated PATCH request. A JSON Merge Patch is
+// described by the fields to set and the fields to clear, while a JSON Patch is described by its operations.
+type SongPatchDto struct {
+	Set        SongParamsDto
+	Clear      []string
+	Operations []JSONPatchOperationDto
+}
+
+// JSONPatchOperationDto represents a single RFC 6902 JSON Patch operation on a song.
+type JSONPatchOperationDto struct {
+	Op    string          `json:"op" validate:"required,oneof=add remove replace move copy test" example:"replace"`
+	Path  string          `json:"path" validate:"required,oneof=/group /song /release_date /text /link" example:"/text"`
+	From  string          `json:"from,omitempty" validate:"required_if=Op move,required_if=Op copy,omitempty,oneof=/group /song /release_date /text /link" example:"/link"`
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"string" example:"Niemand kann das Bild beschreiben"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// SongTagsDto represents the data transfer object for the tags of a song, such as genres and moods.
+// Tags are lowercase, and duplicates are dropped.
+type SongTagsDto struct {
+	Tags []string `json:"tags" validate:"max=20,dive,required,max=50" example:"rock,ballad"`
+}
@@ -1,22 +1,82 @@
 package delivery
 
-// JSONError represents the structure for error responses in JSON format.
-type JSONError struct {
-	Error   string `json:"error" example:"invalid input"`
-	Message string `json:"message,omitempty" example:"invalid JSON body"`
+// Problem represents an RFC 7807 problem details response with a stable, machine-readable error code.
+type Problem struct {
+	Type              string       `json:"type" example:"urn:songs-library:problem:validation_failed"`
+	Title             string       `json:"title" example:"invalid create song input body"`
+	Status            int          `json:"status" example:"400"`
+	Detail            string       `json:"detail,omitempty" example:"group is required"`
+	Instance          string       `json:"instance,omitempty" example:"/songs"`
+	Code              string       `json:"code" example:"validation_failed"`
+	RequestID         string       `json:"request_id,omitempty" example:"host/abcdef-000001"`
+	Errors            []FieldError `json:"errors,omitempty"`
+	MissingPermission string       `json:"missing_permission,omitempty" example:"songs:delete"`
 }
 
+// FieldError describes why a single input field failed validation.
+type FieldError struct {
+	Field   string `json:"field" example:"group"`
+	Code    string `json:"code" example:"required"`
+	Message string `json:"message" example:"is required"`
+}
+
+// Machine-readable problem codes.
+const (
+	CodeValidationFailed      = "validation_failed"
+	CodeInvalidJSON           = "invalid_json"
+	CodeInvalidID             = "invalid_id"
+	CodeInvalidFilter         = "invalid_filter"
+	CodeInvalidImportFile     = "invalid_import_file"
+	CodeInvalidPatch          = "invalid_patch"
+	CodePatchTestFailed       = "patch_test_failed"
+	CodeInvalidBatchOperation = "invalid_batch_operation"
+	CodeBatchAborted          = "batch_aborted"
+	CodeUnsupportedMediaType  = "unsupported_media_type"
+	CodeBodyTooLarge          = "body_too_large"
+	CodeIdempotencyKeyReused  = "idempotency_key_reused"
+	CodeRequestInProgress     = "request_in_progress"
+	CodeSongNotFound          = "song_not_found"
+	CodeSongAlreadyExists     = "song_already_exists"
+	CodeImportConflict        = "import_conflict"
+	CodeUnauthorized          = "unauthorized"
+	CodeForbidden             = "forbidden"
+	CodeAPIKeyNotFound        = "api_key_not_found"
+	CodePlaylistNotFound      = "playlist_not_found"
+	CodeEntryNotFound         = "playlist_entry_not_found"
+	CodeInvalidPlaylistOrder  = "invalid_playlist_order"
+	CodePlaysBufferFull       = "plays_buffer_full"
+	CodeRatingNotFound        = "rating_not_found"
+	CodeReviewNotFound        = "review_not_found"
+	CodeAnnotationNotFound    = "annotation_not_found"
+	CodeInvalidAnchor         = "invalid_anchor"
+	CodeRateLimited           = "rate_limited"
+	CodeInternalError         = "internal_error"
+)
+
 // Error constants for various input validation and parsing issues.
 const (
-	ErrInvalidPaginationParam = "invalid pagination param"
-	ErrParsingParam           = "error parsing pagination param from string to int"
-	ErrInvalidFilters         = "invalid filters param"
-	ErrInvalidFilter          = "invalid filter param"
-	ErrInvalidGetSongsParam   = "invalid get songs param"
-	ErrInvalidIDInput         = "invalid song id input"
-	ErrInvalidUpdateSongInput = "invalid update song input body"
-	ErrInvalidJSON            = "invalid JSON body"
-	ErrInvalidCreateSongInput = "invalid create song input body"
+	ErrInvalidPaginationParam  = "invalid pagination param"
+	ErrParsingParam            = "error parsing pagination param from string to int"
+	ErrParsingBoolParam        = "error parsing param from string to bool"
+	ErrParsingFloatParam       = "error parsing param from string to float"
+	ErrInvalidFilters          = "invalid filters param"
+	ErrInvalidFilter           = "invalid filter param"
+	ErrInvalidGetSongsParam    = "invalid get songs param"
+	ErrInvalidIDInput          = "invalid song id input"
+	ErrInvalidUpdateSongInput  = "invalid update song input body"
+	ErrInvalidJSON             = "invalid JSON body"
+	ErrInvalidCreateSongInput  = "invalid create song input body"
+	ErrInvalidImportParam      = "invalid import param"
+	ErrInvalidBatchInput       = "invalid batch input body"
+	ErrInvalidReplaceSongInput = "invalid replace song input body"
+	ErrInvalidPatchSongInput   = "invalid patch song input body"
+	ErrUnsupportedPatchType    = "unsupported patch media type"
+	ErrInvalidIdempotencyKey   = "invalid Idempotency-Key header"
+	ErrReadingBody             = "error reading request body"
+	ErrIdempotentBodyTooLarge  = "request body too large"
+	ErrBodyTooLarge            = "request body too large"
+	ErrInvalidExportParam      = "invalid export param"
+	ErrInvalidRandomParam      = "invalid random songs param"
 )
 
 // Error constants for song-related operations.
@@ -24,6 +84,116 @@ const (
 	ErrGettingSongs    = "error getting songs"
 	ErrGettingSongText = "error getting song text"
 	ErrDeletingSong    = "error deleting song"
-	ErrUpdatingSong    = "error updating song"
+	ErrReplacingSong   = "error replacing song"
+	ErrPatchingSong    = "error patching song"
 	ErrCreatingSong    = "error create new song"
+	ErrImportingSongs  = "error importing songs"
+	ErrApplyingBatch   = "error applying batch"
+	ErrExportingSongs  = "error exporting songs"
+	ErrGettingRandom   = "error getting random songs"
+	ErrGettingDaily    = "error getting song of the day"
+)
+
+// Error constants for favorites.
+const (
+	ErrGettingFavorites = "error getting favorite songs"
+	ErrAddingFavorite   = "error adding song to favorites"
+	ErrRemovingFavorite = "error removing song from favorites"
+)
+
+// Error constants for authentication.
+const (
+	ErrAuthenticating = "error authenticating request"
+	ErrUnauthorized   = "unauthorized"
+	ErrForbidden      = "forbidden"
+)
+
+// Error constants for API key management.
+const (
+	ErrInvalidIssueKeyInput = "invalid issue API key input body"
+	ErrInvalidKeyIDInput    = "invalid API key id input"
+	ErrIssuingKey           = "error issuing API key"
+	ErrRevokingKey          = "error revoking API key"
+	ErrListingKeys          = "error listing API keys"
+)
+
+// Error constants for playlists.
+const (
+	ErrInvalidPlaylistInput      = "invalid playlist input body"
+	ErrInvalidPlaylistIDInput    = "invalid playlist id input"
+	ErrInvalidEntryIDInput       = "invalid playlist entry id input"
+	ErrInvalidAddEntryInput      = "invalid add playlist entry input body"
+	ErrInvalidReorderInput       = "invalid reorder playlist input body"
+	ErrGettingPlaylists          = "error getting playlists"
+	ErrGettingPlaylist           = "error getting playlist"
+	ErrCreatingPlaylist          = "error creating playlist"
+	ErrReplacingPlaylist         = "error replacing playlist"
+	ErrDeletingPlaylist          = "error deleting playlist"
+	ErrAddingPlaylistEntry       = "error adding song to playlist"
+	ErrRemovingPlaylistEntry     = "error removing song from playlist"
+	ErrReorderingPlaylistEntries = "error reordering playlist"
+)
+
+// Error constants for plays and charts.
+const (
+	ErrInvalidRecordPlayInput = "invalid record play input body"
+	ErrInvalidChartParam      = "invalid chart param"
+	ErrRecordingPlay          = "error recording play"
+	ErrGettingChart           = "error getting chart"
+)
+
+// Error constants for ratings and reviews.
+const (
+	ErrInvalidRateSongInput   = "invalid rate song input body"
+	ErrInvalidGetReviewsParam = "invalid get reviews param"
+	ErrInvalidReviewIDInput   = "invalid review id input"
+	ErrInvalidModerateInput   = "invalid moderate review input body"
+	ErrRatingSong             = "error rating song"
+	ErrGettingRating          = "error getting rating"
+	ErrDeletingRating         = "error deleting rating"
+	ErrGettingReviews         = "error getting reviews"
+	ErrModeratingReview       = "error moderating review"
+)
+
+// Error constants for annotations.
+const (
+	ErrInvalidAnnotationInput   = "invalid create annotation input body"
+	ErrInvalidAnnotationIDInput = "invalid annotation id input"
+	ErrInvalidVoteInput         = "invalid vote input body"
+	ErrCreatingAnnotation       = "error creating annotation"
+	ErrGettingAnnotations       = "error getting annotations"
+	ErrVotingAnnotation         = "error voting on annotation"
+	ErrDeletingAnnotation       = "error deleting annotation"
+)
+
+// Error constants for similar songs.
+const (
+	ErrInvalidSimilarSongsParam = "invalid similar songs param"
+	ErrGettingSimilarSongs      = "error getting similar songs"
+)
+
+// Error constants for tags.
+const (
+	ErrInvalidTagsInput = "invalid tags input body"
+	ErrGettingTags      = "error getting tags"
+	ErrReplacingTags    = "error replacing tags"
+)
+
+// Error constants for health checks.
+const (
+	ErrNotReady = "server not ready"
+)
+
+// Error constants for rate limiting.
+const (
+	ErrRateLimited       = "rate limit exceeded"
+	ErrCheckingRateLimit = "error checking rate limit"
+)
+
+// Error constants for idempotent requests.
+const (
+	ErrCheckingIdempotencyKey      = "error checking Idempotency-Key"
+	ErrStoringIdempotencyKey       = "error storing Idempotency-Key response"
+	ErrIdempotencyKeyReused        = "Idempotency-Key reused"
+	ErrIdempotentRequestInProgress = "request with this Idempotency-Key in progress"
 )
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/delivery/middleware"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// AnnotationsService defines the methods for annotating song lyrics and voting on annotations.
+type AnnotationsService interface {
+	Create(ctx context.Context, userID, songID int32, annotationInput dto.CreateAnnotationDto) (domain.Annotation, error)
+	GetAnnotations(ctx context.Context, userID, songID int32, params dto.PaginationParamsDto) ([]domain.Annotation, int, error)
+	Vote(ctx context.Context, userID, annotationID int32, voteInput dto.VoteDto) (int, error)
+	Delete(ctx context.Context, userID, annotationID int32) error
+}
+
+// AnnotationsHandler manages HTTP requests for annotations of song lyrics.
+type AnnotationsHandler struct {
+	validator          *validator.Validate
+	annotationsService AnnotationsService
+}
+
+// NewAnnotationsHandler initializes and returns a new instance of AnnotationsHandler with the provided validator and annotations service.
+func NewAnnotationsHandler(validator *validator.Validate, annotationsService AnnotationsService) *AnnotationsHandler {
+	return &AnnotationsHandler{
+		validator:          validator,
+		annotationsService: annotationsService,
+	}
+}
+
+// RegisterRoutes sets up the HTTP routes for annotations using the Chi router.
+func (h AnnotationsHandler) RegisterRoutes(r *chi.Mux) {
+	read := middleware.RequirePermission(domain.PermissionReadSongs)
+	annotate := middleware.RequirePermission(domain.PermissionAnnotate)
+
+	r.With(read).Get("/songs/{id}/annotations", middleware.ValidateGetSongParam(h.validator, h.getAnnotations))
+	r.With(annotate).Post("/songs/{id}/annotations", middleware.ValidateCreateAnnotationInput(h.validator, h.createAnnotation))
+
+	r.Route("/annotations", func(r chi.Router) {
+		r.Use(annotate)
+
+		r.Put("/{id}/vote", middleware.ValidateVoteInput(h.validator, h.vote))
+		r.Delete("/{id}", middleware.ValidateAnnotationIDInput(h.deleteAnnotation))
+	})
+}
+
+// @Summary Get annotations of song lyrics
+// @Description Retrieve the annotations of the verses on a page of the song text, paginated like GET /songs/{songID}.
+// @Description Annotations whose fragment was removed from the edited lyrics are flagged as stale and keep their last verse.
+// @Tags annotations
+// @Produce  json
+// @Param songID path int true "Song ID"
+// @Param page query int false "Page number of the song text"
+// @Param limit query int false "Number of verses per page"
+// @Success 200 {object} dto.AnnotationsDto "Annotations"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID}/annotations [get]
+func (h AnnotationsHandler) getAnnotations(w http.ResponseWriter, r *http.Request, songID int, params dto.PaginationParamsDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	annotations, totalPages, err := h.annotationsService.GetAnnotations(r.Context(), principal.UserID, int32(songID), params)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingAnnotations)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingAnnotations, err))
+		return
+	}
+
+	annotationsDto := make([]dto.AnnotationDto, 0, len(annotations))
+	for _, annotation := range annotations {
+		annotationsDto = append(annotationsDto, h.toAnnotationDto(annotation, principal.UserID))
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, dto.AnnotationsDto{
+		Annotations: annotationsDto,
+		TotalPages:  totalPages,
+	})
+}
+
+// @Summary Annotate song lyrics
+// @Description Annotate a range of lines or characters of a verse. Verses are counted from 0 across the whole song text,
+// @Description ranges are half-open and counted from 0 within the verse.
+// @Tags annotations
+// @Accept  json
+// @Produce  json
+// @Param songID path int true "Song ID"
+// @Param body body dto.CreateAnnotationDto true "Annotation and its anchor"
+// @Success 201 {object} dto.AnnotationDto "Created annotation"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID}/annotations [post]
+func (h AnnotationsHandler) createAnnotation(w http.ResponseWriter, r *http.Request, songID int, annotationInput dto.CreateAnnotationDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	annotation, err := h.annotationsService.Create(r.Context(), principal.UserID, int32(songID), annotationInput)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrCreatingAnnotation)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrCreatingAnnotation, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusCreated, h.toAnnotationDto(annotation, principal.UserID))
+}
+
+// @Summary Vote on an annotation
+// @Description Upvote (1) or downvote (-1) an annotation, or withdraw the vote (0). Every user has one vote per annotation.
+// @Tags annotations
+// @Accept  json
+// @Produce  json
+// @Param annotationID path int true "Annotation ID"
+// @Param body body dto.VoteDto true "Vote"
+// @Success 200 {object} dto.ScoreDto "New score of the annotation"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /annotations/{annotationID}/vote [put]
+func (h AnnotationsHandler) vote(w http.ResponseWriter, r *http.Request, annotationID int, voteInput dto.VoteDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	score, err := h.annotationsService.Vote(r.Context(), principal.UserID, int32(annotationID), voteInput)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrVotingAnnotation)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrVotingAnnotation, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, dto.ScoreDto{Score: score})
+}
+
+// @Summary Delete an annotation
+// @Description Delete an annotation written by the authenticated user.
+// @Tags annotations
+// @Param annotationID path int true "Annotation ID"
+// @Success 200 "Annotation successfully deleted"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /annotations/{annotationID} [delete]
+func (h AnnotationsHandler) deleteAnnotation(w http.ResponseWriter, r *http.Request, annotationID int) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	if err := h.annotationsService.Delete(r.Context(), principal.UserID, int32(annotationID)); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrDeletingAnnotation)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrDeletingAnnotation, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+func (h AnnotationsHandler) toAnnotationDto(annotation domain.Annotation, userID int32) dto.AnnotationDto {
+	return dto.AnnotationDto{
+		ID:         annotation.ID,
+		SongID:     annotation.SongID,
+		AuthorID:   annotation.UserID,
+		IsAuthor:   annotation.UserID == userID,
+		Body:       annotation.Body,
+		VerseIndex: annotation.VerseIndex,
+		Unit:       string(annotation.Unit),
+		Start:      annotation.Start,
+		End:        annotation.End,
+		AnchorText: annotation.Text,
+		Stale:      annotation.Stale,
+		Score:      annotation.Score,
+		MyVote:     annotation.MyVote,
+		CreatedAt:  annotation.CreatedAt,
+		UpdatedAt:  annotation.UpdatedAt,
+	}
+}
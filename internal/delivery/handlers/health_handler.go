@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"songs-library-go/internal/metrics"
+	"time"
+)
+
+// HealthService defines the method for checking the dependencies of the server.
+type HealthService interface {
+	Readiness(ctx context.Context) domain.HealthReport
+}
+
+// HealthHandler manages the liveness and readiness probes and the metrics. All of them are public, see middleware.Authenticate.
+type HealthHandler struct {
+	healthService HealthService
+}
+
+// NewHealthHandler initializes and returns a new instance of HealthHandler with the provided health service.
+func NewHealthHandler(healthService HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// RegisterRoutes sets up the HTTP routes for the probes and the Prometheus metrics using the Chi router.
+func (h HealthHandler) RegisterRoutes(r *chi.Mux) {
+	r.Get(delivery.LivenessPath, h.liveness)
+	r.Get(delivery.ReadinessPath, h.readiness)
+	r.Method(http.MethodGet, delivery.MetricsPath, metrics.Handler())
+}
+
+// @Summary Liveness probe
+// @Description Report that the process is running. Dependencies are not checked.
+// @Tags health
+// @Produce  json
+// @Success 200 {object} dto.HealthDto "Alive"
+// @Router /healthz [get]
+func (h HealthHandler) liveness(w http.ResponseWriter, r *http.Request) {
+	delivery.RespondWithJSON(w, http.StatusOK, dto.HealthDto{Status: string(domain.HealthUp)})
+}
+
+// @Summary Readiness probe
+// @Description Check the database, the applied migrations and, as an optional dependency, the music info API. The server is not ready only if a critical check fails.
+// @Tags health
+// @Produce  json
+// @Success 200 {object} dto.HealthDto "Ready, possibly degraded"
+// @Failure 503 {object} dto.HealthDto "Not ready"
+// @Router /readyz [get]
+func (h HealthHandler) readiness(w http.ResponseWriter, r *http.Request) {
+	report := h.healthService.Readiness(r.Context())
+
+	healthDto := dto.HealthDto{
+		Status: string(report.Status),
+		Checks: make([]dto.HealthCheckDto, 0, len(report.Checks)),
+	}
+
+	for _, check := range report.Checks {
+		healthDto.Checks = append(healthDto.Checks, dto.HealthCheckDto{
+			Name:      check.Name,
+			Status:    string(check.Status),
+			Critical:  check.Critical,
+			LatencyMs: float64(check.Latency) / float64(time.Millisecond),
+			Error:     check.Error,
+		})
+	}
+
+	code := http.StatusOK
+	if report.Status == domain.HealthDown {
+		logging.FromContext(r.Context()).Errorf("%s (checks: %+v)", delivery.ErrNotReady, report.Checks)
+		code = http.StatusServiceUnavailable
+	}
+
+	delivery.RespondWithJSON(w, code, healthDto)
+}
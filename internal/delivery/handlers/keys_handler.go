@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/delivery/middleware"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// KeysService defines the methods for issuing, listing and revoking API keys.
+type KeysService interface {
+	IssueKey(ctx context.Context, name string, role domain.Role) (domain.APIKey, string, error)
+	RevokeKey(ctx context.Context, keyID int32) error
+	ListKeys(ctx context.Context) ([]domain.APIKey, error)
+}
+
+// KeysHandler manages HTTP requests for API key management, available to admins only.
+type KeysHandler struct {
+	validator   *validator.Validate
+	keysService KeysService
+}
+
+// NewKeysHandler initializes and returns a new instance of KeysHandler with the provided validator and keys service.
+func NewKeysHandler(validator *validator.Validate, keysService KeysService) *KeysHandler {
+	return &KeysHandler{
+		validator:   validator,
+		keysService: keysService,
+	}
+}
+
+// RegisterRoutes sets up the HTTP routes for API key management using the Chi router.
+func (h KeysHandler) RegisterRoutes(r *chi.Mux) {
+	r.Route("/keys", func(r chi.Router) {
+		r.Use(middleware.RequirePermission(domain.PermissionManageKeys))
+
+		r.Get("/", h.listKeys)
+		r.Post("/", middleware.ValidateIssueKeyInput(h.validator, h.issueKey))
+		r.Delete("/{id}", middleware.ValidateKeyIDInput(h.revokeKey))
+	})
+}
+
+// @Summary List API keys
+// @Description List all issued API keys without their secrets.
+// @Tags keys
+// @Produce  json
+// @Success 200 {array} dto.APIKeyDto "API keys"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /keys [get]
+func (h KeysHandler) listKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.keysService.ListKeys(r.Context())
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrListingKeys)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrListingKeys, err))
+		return
+	}
+
+	keysDto := make([]dto.APIKeyDto, 0, len(keys))
+	for _, key := range keys {
+		keysDto = append(keysDto, h.toAPIKeyDto(key))
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, keysDto)
+}
+
+// @Summary Issue an API key
+// @Description Issue a new API key with the given role. The key is returned only once.
+// @Tags keys
+// @Accept  json
+// @Produce  json
+// @Param body body dto.IssueAPIKeyDto true "Name and role of the key"
+// @Success 201 {object} dto.IssuedAPIKeyDto "Issued key"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /keys [post]
+func (h KeysHandler) issueKey(w http.ResponseWriter, r *http.Request, issueKeyInput dto.IssueAPIKeyDto) {
+	key, secret, err := h.keysService.IssueKey(r.Context(), issueKeyInput.Name, domain.Role(issueKeyInput.Role))
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrIssuingKey)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrIssuingKey, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusCreated, dto.IssuedAPIKeyDto{
+		APIKeyDto: h.toAPIKeyDto(key),
+		Key:       secret,
+	})
+}
+
+// @Summary Revoke an API key
+// @Description Revoke an API key by its ID. Requests with a revoked key are rejected with 401.
+// @Tags keys
+// @Param keyID path int true "API key ID"
+// @Success 200 "Key successfully revoked"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /keys/{keyID} [delete]
+func (h KeysHandler) revokeKey(w http.ResponseWriter, r *http.Request, keyID int) {
+	if err := h.keysService.RevokeKey(r.Context(), int32(keyID)); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrRevokingKey)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrRevokingKey, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+func (h KeysHandler) toAPIKeyDto(key domain.APIKey) dto.APIKeyDto {
+	return dto.APIKeyDto{
+		ID:        key.ID,
+		Name:      key.Name,
+		Role:      string(key.Role),
+		Prefix:    key.Prefix,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
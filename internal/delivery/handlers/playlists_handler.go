@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/delivery/middleware"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// PlaylistsService defines the methods for managing the playlists of the authenticated user.
+type PlaylistsService interface {
+	GetPlaylists(ctx context.Context, userID int32, params dto.PaginationParamsDto) ([]domain.Playlist, int, error)
+	GetPlaylist(ctx context.Context, userID, playlistID int32, params dto.PaginationParamsDto) (domain.Playlist, []domain.PlaylistEntry, int, error)
+	Create(ctx context.Context, userID int32, playlistInput dto.PlaylistInputDto) (domain.Playlist, error)
+	Replace(ctx context.Context, userID, playlistID int32, playlistInput dto.PlaylistInputDto) (domain.Playlist, error)
+	Delete(ctx context.Context, userID, playlistID int32) error
+	AddEntry(ctx context.Context, userID, playlistID int32, entryInput dto.AddPlaylistEntryDto) (domain.PlaylistEntry, error)
+	RemoveEntry(ctx context.Context, userID, playlistID, entryID int32) error
+	Reorder(ctx context.Context, userID, playlistID int32, reorderInput dto.ReorderPlaylistDto) error
+}
+
+// PlaylistsHandler manages HTTP requests for playlists of the authenticated user.
+type PlaylistsHandler struct {
+	validator        *validator.Validate
+	playlistsService PlaylistsService
+}
+
+// NewPlaylistsHandler initializes and returns a new instance of PlaylistsHandler with the provided validator and playlists service.
+func NewPlaylistsHandler(validator *validator.Validate, playlistsService PlaylistsService) *PlaylistsHandler {
+	return &PlaylistsHandler{
+		validator:        validator,
+		playlistsService: playlistsService,
+	}
+}
+
+// RegisterRoutes sets up the HTTP routes for playlists using the Chi router.
+func (h PlaylistsHandler) RegisterRoutes(r *chi.Mux) {
+	r.Route("/playlists", func(r chi.Router) {
+		r.Use(middleware.RequirePermission(domain.PermissionPlaylists))
+
+		r.Get("/", middleware.ValidatePaginationParam(h.validator, h.getPlaylists))
+		r.Post("/", middleware.ValidatePlaylistInput(h.validator, h.createPlaylist))
+		r.Get("/{id}", middleware.ValidateGetPlaylistParam(h.validator, h.getPlaylist))
+		r.Put("/{id}", middleware.ValidateReplacePlaylistInput(h.validator, h.replacePlaylist))
+		r.Delete("/{id}", middleware.ValidatePlaylistIDInput(h.deletePlaylist))
+		r.Post("/{id}/entries", middleware.ValidateAddPlaylistEntryInput(h.validator, h.addEntry))
+		r.Delete("/{id}/entries/{entryID}", middleware.ValidatePlaylistEntryIDInput(h.removeEntry))
+		r.Put("/{id}/entries/order", middleware.ValidateReorderPlaylistInput(h.validator, h.reorderEntries))
+	})
+}
+
+// @Summary Get own playlists
+// @Description Retrieve a paginated list of the playlists owned by the authenticated user, most recently updated first.
+// @Tags playlists
+// @Produce  json
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Number of playlists per page"
+// @Success 200 {object} dto.PlaylistsDto "List of playlists"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /playlists [get]
+func (h PlaylistsHandler) getPlaylists(w http.ResponseWriter, r *http.Request, params dto.PaginationParamsDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	playlists, totalPages, err := h.playlistsService.GetPlaylists(r.Context(), principal.UserID, params)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingPlaylists)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingPlaylists, err))
+		return
+	}
+
+	playlistsDto := make([]dto.PlaylistDto, 0, len(playlists))
+	for _, playlist := range playlists {
+		playlistsDto = append(playlistsDto, h.toPlaylistDto(playlist, principal.UserID))
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, dto.PlaylistsDto{
+		Playlists:  playlistsDto,
+		TotalPages: totalPages,
+	})
+}
+
+// @Summary Get a playlist
+// @Description Retrieve a playlist owned by the authenticated user or shared publicly, with a paginated list of its songs in order.
+// @Tags playlists
+// @Produce  json
+// @Param playlistID path int true "Playlist ID"
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Number of entries per page"
+// @Success 200 {object} dto.PlaylistWithEntriesDto "Playlist with entries"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /playlists/{playlistID} [get]
+func (h PlaylistsHandler) getPlaylist(w http.ResponseWriter, r *http.Request, playlistID int, params dto.PaginationParamsDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	playlist, entries, totalPages, err := h.playlistsService.GetPlaylist(r.Context(), principal.UserID, int32(playlistID), params)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingPlaylist)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingPlaylist, err))
+		return
+	}
+
+	entriesDto := make([]dto.PlaylistEntryDto, 0, len(entries))
+	for _, entry := range entries {
+		entriesDto = append(entriesDto, h.toPlaylistEntryDto(entry))
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, dto.PlaylistWithEntriesDto{
+		PlaylistDto: h.toPlaylistDto(playlist, principal.UserID),
+		Entries:     entriesDto,
+		TotalPages:  totalPages,
+	})
+}
+
+// @Summary Create a playlist
+// @Description Create a new playlist owned by the authenticated user. Public playlists can be read by all users.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param body body dto.PlaylistInputDto true "Name, description and visibility of the playlist"
+// @Success 201 {object} dto.PlaylistDto "Created playlist"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /playlists [post]
+func (h PlaylistsHandler) createPlaylist(w http.ResponseWriter, r *http.Request, playlistInput dto.PlaylistInputDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	playlist, err := h.playlistsService.Create(r.Context(), principal.UserID, playlistInput)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrCreatingPlaylist)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrCreatingPlaylist, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusCreated, h.toPlaylistDto(playlist, principal.UserID))
+}
+
+// @Summary Replace a playlist
+// @Description Replace the name, description and visibility of a playlist owned by the authenticated user.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param playlistID path int true "Playlist ID"
+// @Param body body dto.PlaylistInputDto true "Name, description and visibility of the playlist"
+// @Success 200 {object} dto.PlaylistDto "Replaced playlist"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /playlists/{playlistID} [put]
+func (h PlaylistsHandler) replacePlaylist(w http.ResponseWriter, r *http.Request, playlistID int, playlistInput dto.PlaylistInputDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	playlist, err := h.playlistsService.Replace(r.Context(), principal.UserID, int32(playlistID), playlistInput)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrReplacingPlaylist)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrReplacingPlaylist, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, h.toPlaylistDto(playlist, principal.UserID))
+}
+
+// @Summary Delete a playlist
+// @Description Delete a playlist owned by the authenticated user together with its entries.
+// @Tags playlists
+// @Param playlistID path int true "Playlist ID"
+// @Success 200 "Playlist successfully deleted"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /playlists/{playlistID} [delete]
+func (h PlaylistsHandler) deletePlaylist(w http.ResponseWriter, r *http.Request, playlistID int) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	if err := h.playlistsService.Delete(r.Context(), principal.UserID, int32(playlistID)); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrDeletingPlaylist)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrDeletingPlaylist, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+// @Summary Add a song to a playlist
+// @Description Insert a song into a playlist owned by the authenticated user at the given position, shifting the following entries down.
+// @Description Without a position, or with a position after the last entry, the song is appended. A song may appear in a playlist more than once.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param playlistID path int true "Playlist ID"
+// @Param body body dto.AddPlaylistEntryDto true "Song and position"
+// @Success 201 {object} dto.PlaylistEntryDto "Added entry"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /playlists/{playlistID}/entries [post]
+func (h PlaylistsHandler) addEntry(w http.ResponseWriter, r *http.Request, playlistID int, entryInput dto.AddPlaylistEntryDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	entry, err := h.playlistsService.AddEntry(r.Context(), principal.UserID, int32(playlistID), entryInput)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrAddingPlaylistEntry)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrAddingPlaylistEntry, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusCreated, h.toPlaylistEntryDto(entry))
+}
+
+// @Summary Remove a song from a playlist
+// @Description Remove an entry from a playlist owned by the authenticated user. The following entries move up.
+// @Tags playlists
+// @Param playlistID path int true "Playlist ID"
+// @Param entryID path int true "Entry ID"
+// @Success 200 "Entry successfully removed"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /playlists/{playlistID}/entries/{entryID} [delete]
+func (h PlaylistsHandler) removeEntry(w http.ResponseWriter, r *http.Request, playlistID, entryID int) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	if err := h.playlistsService.RemoveEntry(r.Context(), principal.UserID, int32(playlistID), int32(entryID)); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrRemovingPlaylistEntry)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrRemovingPlaylistEntry, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+// @Summary Reorder a playlist
+// @Description Set the order of the entries of a playlist owned by the authenticated user. The IDs of all entries must be listed exactly once.
+// @Tags playlists
+// @Accept  json
+// @Param playlistID path int true "Playlist ID"
+// @Param body body dto.ReorderPlaylistDto true "Entry IDs in the new order"
+// @Success 200 "Playlist successfully reordered"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 409 {object} delivery.Problem "Conflict"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /playlists/{playlistID}/entries/order [put]
+func (h PlaylistsHandler) reorderEntries(w http.ResponseWriter, r *http.Request, playlistID int, reorderInput dto.ReorderPlaylistDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	if err := h.playlistsService.Reorder(r.Context(), principal.UserID, int32(playlistID), reorderInput); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrReorderingPlaylistEntries)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrReorderingPlaylistEntries, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+func (h PlaylistsHandler) toPlaylistDto(playlist domain.Playlist, userID int32) dto.PlaylistDto {
+	return dto.PlaylistDto{
+		ID:          playlist.ID,
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		IsPublic:    playlist.IsPublic,
+		IsOwner:     playlist.UserID == userID,
+		SongsCount:  playlist.SongsCount,
+		CreatedAt:   playlist.CreatedAt,
+		UpdatedAt:   playlist.UpdatedAt,
+	}
+}
+
+func (h PlaylistsHandler) toPlaylistEntryDto(entry domain.PlaylistEntry) dto.PlaylistEntryDto {
+	return dto.PlaylistEntryDto{
+		ID:       entry.ID,
+		Position: entry.Position,
+		AddedAt:  entry.AddedAt,
+		Song:     toSongDto(entry.Song),
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/delivery/middleware"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// PlaysService defines the methods for recording plays and ranking songs by their plays.
+type PlaysService interface {
+	Record(ctx context.Context, userID, songID int32, playInput dto.RecordPlayDto) error
+	GetChart(ctx context.Context, params dto.ChartParamsDto) ([]domain.ChartEntry, error)
+}
+
+// PlaysHandler manages HTTP requests for recording plays of songs and for charts.
+type PlaysHandler struct {
+	validator    *validator.Validate
+	playsService PlaysService
+}
+
+// NewPlaysHandler initializes and returns a new instance of PlaysHandler with the provided validator and plays service.
+func NewPlaysHandler(validator *validator.Validate, playsService PlaysService) *PlaysHandler {
+	return &PlaysHandler{
+		validator:    validator,
+		playsService: playsService,
+	}
+}
+
+// RegisterRoutes sets up the HTTP routes for plays and charts using the Chi router.
+func (h PlaysHandler) RegisterRoutes(r *chi.Mux) {
+	record := middleware.RequirePermission(domain.PermissionRecordPlays)
+	read := middleware.RequirePermission(domain.PermissionReadSongs)
+
+	r.With(record).Post("/songs/{id}/plays", middleware.ValidateRecordPlayInput(h.validator, h.recordPlay))
+	r.With(read).Get("/charts", middleware.ValidateChartParam(h.validator, h.getChart))
+}
+
+// @Summary Record a play
+// @Description Record a listen of a song by the authenticated user. Plays are counted asynchronously, so they show up in rankings and charts after a few seconds.
+// @Tags plays
+// @Accept  json
+// @Param songID path int true "Song ID"
+// @Param body body dto.RecordPlayDto false "Source and time of the play"
+// @Success 202 "Play accepted"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Failure 503 {object} delivery.Problem "Service Unavailable"
+// @Router /songs/{songID}/plays [post]
+func (h PlaysHandler) recordPlay(w http.ResponseWriter, r *http.Request, songID int, playInput dto.RecordPlayDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	if err := h.playsService.Record(r.Context(), principal.UserID, int32(songID), playInput); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrRecordingPlay)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrRecordingPlay, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusAccepted, nil)
+}
+
+// @Summary Get a chart
+// @Description Retrieve the most played songs in a period of days ending today (UTC).
+// @Tags plays
+// @Produce  json
+// @Param period query string false "Period in days, from 1d to 365d" default(7d)
+// @Param limit query int false "Number of songs" default(10)
+// @Success 200 {object} dto.ChartDto "Chart"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /charts [get]
+func (h PlaysHandler) getChart(w http.ResponseWriter, r *http.Request, params dto.ChartParamsDto) {
+	chart, err := h.playsService.GetChart(r.Context(), params)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingChart)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingChart, err))
+		return
+	}
+
+	entriesDto := make([]dto.ChartEntryDto, 0, len(chart))
+	for _, entry := range chart {
+		entriesDto = append(entriesDto, dto.ChartEntryDto{
+			Rank:  entry.Rank,
+			Plays: entry.Plays,
+			Song:  toSongDto(entry.Song),
+		})
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, dto.ChartDto{
+		Period:  params.Period,
+		Entries: entriesDto,
+	})
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/delivery/middleware"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// RatingsService defines the methods for rating songs, reading reviews and moderating them.
+type RatingsService interface {
+	Rate(ctx context.Context, userID, songID int32, rateInput dto.RateSongDto) (domain.Rating, error)
+	GetRating(ctx context.Context, userID, songID int32) (domain.Rating, error)
+	DeleteRating(ctx context.Context, userID, songID int32) error
+	GetSongReviews(ctx context.Context, songID int32, params dto.PaginationParamsDto) ([]domain.Rating, int, error)
+	GetReviews(ctx context.Context, params dto.GetReviewsDto) ([]domain.Rating, int, error)
+	Moderate(ctx context.Context, reviewID int32, moderateInput dto.ModerateReviewDto) (domain.Rating, error)
+}
+
+// RatingsHandler manages HTTP requests for ratings and reviews of songs.
+type RatingsHandler struct {
+	validator      *validator.Validate
+	ratingsService RatingsService
+}
+
+// NewRatingsHandler initializes and returns a new instance of RatingsHandler with the provided validator and ratings service.
+func NewRatingsHandler(validator *validator.Validate, ratingsService RatingsService) *RatingsHandler {
+	return &RatingsHandler{
+		validator:      validator,
+		ratingsService: ratingsService,
+	}
+}
+
+// RegisterRoutes sets up the HTTP routes for ratings and reviews using the Chi router.
+func (h RatingsHandler) RegisterRoutes(r *chi.Mux) {
+	rate := middleware.RequirePermission(domain.PermissionRateSongs)
+	read := middleware.RequirePermission(domain.PermissionReadSongs)
+
+	r.With(rate).Get("/songs/{id}/rating", middleware.ValidateIDInput(h.getRating))
+	r.With(rate).Put("/songs/{id}/rating", middleware.ValidateRateSongInput(h.validator, h.rateSong))
+	r.With(rate).Delete("/songs/{id}/rating", middleware.ValidateIDInput(h.deleteRating))
+	r.With(read).Get("/songs/{id}/reviews", middleware.ValidateSongReviewsParam(h.validator, h.getSongReviews))
+
+	r.Route("/reviews", func(r chi.Router) {
+		r.Use(middleware.RequirePermission(domain.PermissionModerate))
+
+		r.Get("/", middleware.ValidateGetReviewsParam(h.validator, h.getReviews))
+		r.Put("/{id}/status", middleware.ValidateModerateReviewInput(h.validator, h.moderateReview))
+	})
+}
+
+// @Summary Get own rating
+// @Description Retrieve the rating and review of a song by the authenticated user.
+// @Tags ratings
+// @Produce  json
+// @Param songID path int true "Song ID"
+// @Success 200 {object} dto.RatingDto "Rating"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID}/rating [get]
+func (h RatingsHandler) getRating(w http.ResponseWriter, r *http.Request, songID int) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	rating, err := h.ratingsService.GetRating(r.Context(), principal.UserID, int32(songID))
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingRating)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingRating, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, h.toRatingDto(rating))
+}
+
+// @Summary Rate a song
+// @Description Rate a song with 1 to 5 stars and an optional review, replacing an earlier rating by the authenticated user.
+// @Description New and changed reviews are shown to other users only after a moderator approves them.
+// @Tags ratings
+// @Accept  json
+// @Produce  json
+// @Param songID path int true "Song ID"
+// @Param body body dto.RateSongDto true "Rating and review"
+// @Success 200 {object} dto.RatingDto "Rating"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID}/rating [put]
+func (h RatingsHandler) rateSong(w http.ResponseWriter, r *http.Request, songID int, rateInput dto.RateSongDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	rating, err := h.ratingsService.Rate(r.Context(), principal.UserID, int32(songID), rateInput)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrRatingSong)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrRatingSong, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, h.toRatingDto(rating))
+}
+
+// @Summary Delete own rating
+// @Description Delete the rating and review of a song by the authenticated user.
+// @Tags ratings
+// @Param songID path int true "Song ID"
+// @Success 200 "Rating successfully deleted"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID}/rating [delete]
+func (h RatingsHandler) deleteRating(w http.ResponseWriter, r *http.Request, songID int) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	if err := h.ratingsService.DeleteRating(r.Context(), principal.UserID, int32(songID)); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrDeletingRating)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrDeletingRating, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+// @Summary Get reviews of a song
+// @Description Retrieve a paginated list of the approved reviews of a song, newest first.
+// @Tags ratings
+// @Produce  json
+// @Param songID path int true "Song ID"
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Number of reviews per page"
+// @Success 200 {object} dto.ReviewsDto "List of reviews"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID}/reviews [get]
+func (h RatingsHandler) getSongReviews(w http.ResponseWriter, r *http.Request, songID int, params dto.PaginationParamsDto) {
+	reviews, totalPages, err := h.ratingsService.GetSongReviews(r.Context(), int32(songID), params)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingReviews)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingReviews, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, h.toReviewsDto(reviews, totalPages))
+}
+
+// @Summary Get reviews for moderation
+// @Description Retrieve a paginated list of the reviews of all songs with the given moderation status, newest first.
+// @Tags reviews
+// @Produce  json
+// @Param status query string false "Moderation status" Enums(pending, approved, rejected) default(pending)
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Number of reviews per page"
+// @Success 200 {object} dto.ReviewsDto "List of reviews"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /reviews [get]
+func (h RatingsHandler) getReviews(w http.ResponseWriter, r *http.Request, params dto.GetReviewsDto) {
+	reviews, totalPages, err := h.ratingsService.GetReviews(r.Context(), params)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingReviews)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingReviews, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, h.toReviewsDto(reviews, totalPages))
+}
+
+// @Summary Moderate a review
+// @Description Approve or reject a review. Only approved reviews are shown to other users.
+// @Tags reviews
+// @Accept  json
+// @Produce  json
+// @Param reviewID path int true "Review ID"
+// @Param body body dto.ModerateReviewDto true "Moderation status"
+// @Success 200 {object} dto.ReviewDto "Moderated review"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /reviews/{reviewID}/status [put]
+func (h RatingsHandler) moderateReview(w http.ResponseWriter, r *http.Request, reviewID int, moderateInput dto.ModerateReviewDto) {
+	review, err := h.ratingsService.Moderate(r.Context(), int32(reviewID), moderateInput)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrModeratingReview)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrModeratingReview, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, h.toReviewDto(review))
+}
+
+func (h RatingsHandler) toRatingDto(rating domain.Rating) dto.RatingDto {
+	ratingDto := dto.RatingDto{
+		SongID:    rating.SongID,
+		Rating:    rating.Rating,
+		Review:    rating.Review,
+		CreatedAt: rating.CreatedAt,
+		UpdatedAt: rating.UpdatedAt,
+	}
+
+	if rating.Review != "" {
+		ratingDto.ReviewStatus = string(rating.ReviewStatus)
+	}
+
+	return ratingDto
+}
+
+func (h RatingsHandler) toReviewsDto(reviews []domain.Rating, totalPages int) dto.ReviewsDto {
+	reviewsDto := make([]dto.ReviewDto, 0, len(reviews))
+	for _, review := range reviews {
+		reviewsDto = append(reviewsDto, h.toReviewDto(review))
+	}
+
+	return dto.ReviewsDto{
+		Reviews:    reviewsDto,
+		TotalPages: totalPages,
+	}
+}
+
+func (h RatingsHandler) toReviewDto(review domain.Rating) dto.ReviewDto {
+	return dto.ReviewDto{
+		ID:        review.ID,
+		SongID:    review.SongID,
+		Rating:    review.Rating,
+		Review:    review.Review,
+		Status:    string(review.ReviewStatus),
+		CreatedAt: review.CreatedAt,
+		UpdatedAt: review.UpdatedAt,
+	}
+}
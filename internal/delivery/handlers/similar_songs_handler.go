@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/delivery/middleware"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// SimilarityService defines the method for retrieving songs similar to a song.
+type SimilarityService interface {
+	GetSimilar(ctx context.Context, songID int32, limit int) ([]domain.SimilarSong, error)
+}
+
+// SimilarSongsHandler manages HTTP requests for songs similar to a song.
+type SimilarSongsHandler struct {
+	validator         *validator.Validate
+	similarityService SimilarityService
+}
+
+// NewSimilarSongsHandler initializes and returns a new instance of SimilarSongsHandler with the provided validator and similarity service.
+func NewSimilarSongsHandler(validator *validator.Validate, similarityService SimilarityService) *SimilarSongsHandler {
+	return &SimilarSongsHandler{
+		validator:         validator,
+		similarityService: similarityService,
+	}
+}
+
+// RegisterRoutes sets up the HTTP routes for similar songs using the Chi router.
+func (h SimilarSongsHandler) RegisterRoutes(r *chi.Mux) {
+	read := middleware.RequirePermission(domain.PermissionReadSongs)
+
+	r.With(read).Get("/songs/{id}/similar", middleware.ValidateSimilarSongsParam(h.validator, h.getSimilarSongs))
+}
+
+// @Summary Get similar songs
+// @Description Retrieve the songs most similar to a song by lyrics, artist, tags and release year. Similarities are computed offline by the similar command, so new songs show up after the next computation.
+// @Tags songs
+// @Produce  json
+// @Param songID path int true "Song ID"
+// @Param limit query int false "Number of songs" default(10)
+// @Success 200 {object} dto.SimilarSongsDto "Similar songs"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID}/similar [get]
+func (h SimilarSongsHandler) getSimilarSongs(w http.ResponseWriter, r *http.Request, songID int, params dto.SimilarSongsParamsDto) {
+	similarSongs, err := h.similarityService.GetSimilar(r.Context(), int32(songID), params.Limit)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingSimilarSongs)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingSimilarSongs, err))
+		return
+	}
+
+	songsDto := make([]dto.SimilarSongDto, 0, len(similarSongs))
+	for _, similarSong := range similarSongs {
+		songsDto = append(songsDto, dto.SimilarSongDto{
+			Score: similarSong.Score,
+			Song:  toSongDto(similarSong.Song),
+		})
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, dto.SimilarSongsDto{Songs: songsDto})
+}
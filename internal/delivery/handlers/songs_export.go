@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"time"
+)
+
+const exportFlushInterval = 1000
+
+var exportContentTypes = map[string]string{
+	domain.FormatCSV:    "text/csv; charset=utf-8",
+	domain.FormatNDJSON: "application/x-ndjson",
+	domain.FormatJSON:   "application/json",
+}
+
+// @Summary Export songs
+// @Description Stream the whole catalog, or the subset matching the same filters as GET /songs, as a CSV, NDJSON or JSON file.
+// @Tags songs
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  json
+// @Param format query string false "File format: csv, ndjson or json (default ndjson)"
+// @Param group query string false "Filter by group"
+// @Param song query string false "Filter by song"
+// @Param release_date query string false "Filter by release date in the format dd.mm.yyyy"
+// @Param text query string false "Filter by words in the text"
+// @Param link query string false "Filter by link"
+// @Success 200 {array} dto.SongDto "Songs file"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/export [get]
+func (h SongsHandler) exportSongs(w http.ResponseWriter, r *http.Request, params dto.ExportSongsDto) {
+	encoder := newSongsEncoder(w, params.Format)
+
+	err := h.songsService.Export(r.Context(), params, func(song domain.Song) error {
+		return encoder.encode(toSongDto(song))
+	})
+	if err == nil {
+		err = encoder.close()
+	}
+
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrExportingSongs)
+
+		if !encoder.started {
+			delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrExportingSongs, err))
+			return
+		}
+
+		// The status has already been sent, so abort the response to let the client know it is incomplete.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+type songsEncoder struct {
+	w       http.ResponseWriter
+	format  string
+	csv     *csv.Writer
+	started bool
+	count   int
+}
+
+func newSongsEncoder(w http.ResponseWriter, format string) *songsEncoder {
+	return &songsEncoder{
+		w:      w,
+		format: format,
+		csv:    csv.NewWriter(w),
+	}
+}
+
+func (e *songsEncoder) encode(song dto.SongDto) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	var err error
+	switch e.format {
+	case domain.FormatCSV:
+		err = e.csv.Write([]string{fmt.Sprint(song.ID), song.Group, song.Song, song.ReleaseDate, song.Text, song.Link})
+	case domain.FormatNDJSON:
+		err = e.writeJSON(song, "", "\n")
+	case domain.FormatJSON:
+		separator := ","
+		if e.count == 0 {
+			separator = ""
+		}
+		err = e.writeJSON(song, separator, "")
+	}
+	if err != nil {
+		return err
+	}
+
+	e.count++
+	if e.count%exportFlushInterval == 0 {
+		return e.flush()
+	}
+
+	return nil
+}
+
+func (e *songsEncoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	e.w.Header().Set("Content-Type", exportContentTypes[e.format])
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="songs-%s.%s"`, time.Now().Format("20060102"), e.format))
+	e.w.WriteHeader(http.StatusOK)
+
+	switch e.format {
+	case domain.FormatCSV:
+		return e.csv.Write([]string{"id", "group", "song", "release_date", "text", "link"})
+	case domain.FormatJSON:
+		_, err := e.w.Write([]byte("["))
+		return err
+	}
+
+	return nil
+}
+
+func (e *songsEncoder) close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	if e.format == domain.FormatJSON {
+		if _, err := e.w.Write([]byte("]")); err != nil {
+			return err
+		}
+	}
+
+	return e.flush()
+}
+
+func (e *songsEncoder) writeJSON(song dto.SongDto, prefix, suffix string) error {
+	data, err := json.Marshal(song)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write([]byte(prefix + string(data) + suffix))
+	return err
+}
+
+func (e *songsEncoder) flush() error {
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
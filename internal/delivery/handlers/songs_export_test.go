@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exportSongsService is a SongsService exporting fixed songs and then failing with err, if set.
+type exportSongsService struct {
+	SongsService
+	songs []domain.Song
+	err   error
+}
+
+func (s exportSongsService) Export(_ context.Context, _ dto.ExportSongsDto, fn func(domain.Song) error) error {
+	for _, song := range s.songs {
+		if err := fn(song); err != nil {
+			return err
+		}
+	}
+
+	return s.err
+}
+
+var exportedSongs = []domain.Song{
+	{ID: 1, Group: "Muse", Song: "Hysteria", ReleaseDate: time.Date(2003, 12, 1, 0, 0, 0, 0, time.UTC), Text: "Line 1\n\nLine 2", Link: "https://example.com"},
+	{ID: 2, Group: "Queen, the band", Song: `"39`},
+}
+
+func TestExportSongs(t *testing.T) {
+	tests := []struct {
+		name            string
+		format          string
+		songs           []domain.Song
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "csv",
+			format:          domain.FormatCSV,
+			songs:           exportedSongs,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody: "id,group,song,release_date,text,link\n" +
+				"1,Muse,Hysteria,01.12.2003,\"Line 1\n\nLine 2\",https://example.com\n" +
+				"2,\"Queen, the band\",\"\"\"39\",,,\n",
+		},
+		{
+			name:            "empty csv has the header",
+			format:          domain.FormatCSV,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody:        "id,group,song,release_date,text,link\n",
+		},
+		{
+			name:            "ndjson",
+			format:          domain.FormatNDJSON,
+			songs:           exportedSongs,
+			wantContentType: "application/x-ndjson",
+			wantBody: `{"id":1,"group":"Muse","song":"Hysteria","release_date":"01.12.2003","text":"Line 1\n\nLine 2","link":"https://example.com"}` + "\n" +
+				`{"id":2,"group":"Queen, the band","song":"\"39"}` + "\n",
+		},
+		{
+			name:            "empty ndjson",
+			format:          domain.FormatNDJSON,
+			wantContentType: "application/x-ndjson",
+			wantBody:        "",
+		},
+		{
+			name:            "json",
+			format:          domain.FormatJSON,
+			songs:           exportedSongs,
+			wantContentType: "application/json",
+			wantBody: `[{"id":1,"group":"Muse","song":"Hysteria","release_date":"01.12.2003","text":"Line 1\n\nLine 2","link":"https://example.com"},` +
+				`{"id":2,"group":"Queen, the band","song":"\"39"}]`,
+		},
+		{
+			name:            "empty json is an empty array",
+			format:          domain.FormatJSON,
+			wantContentType: "application/json",
+			wantBody:        "[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewSongsHandler(nil, exportSongsService{songs: tt.songs})
+			w := httptest.NewRecorder()
+
+			h.exportSongs(w, httptest.NewRequest(http.MethodGet, "/songs/export", nil), dto.ExportSongsDto{Format: tt.format})
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+
+			if got := w.Header().Get("Content-Disposition"); !strings.HasSuffix(got, "."+tt.format+`"`) {
+				t.Errorf("Content-Disposition = %q, want a .%s attachment", got, tt.format)
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestExportSongsFailure(t *testing.T) {
+	errExport := errors.New("connection reset")
+
+	t.Run("before the first song responds with a problem", func(t *testing.T) {
+		h := NewSongsHandler(nil, exportSongsService{err: errExport})
+		w := httptest.NewRecorder()
+
+		h.exportSongs(w, httptest.NewRequest(http.MethodGet, "/songs/export", nil), dto.ExportSongsDto{Format: domain.FormatCSV})
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+		}
+
+		if got := w.Header().Get("Content-Type"); got != delivery.ContentTypeProblem {
+			t.Errorf("Content-Type = %q, want %q", got, delivery.ContentTypeProblem)
+		}
+	})
+
+	t.Run("after the first song aborts the response", func(t *testing.T) {
+		h := NewSongsHandler(nil, exportSongsService{songs: exportedSongs, err: errExport})
+		w := httptest.NewRecorder()
+
+		defer func() {
+			if recovered := recover(); recovered != http.ErrAbortHandler {
+				t.Errorf("recovered %v, want %v", recovered, http.ErrAbortHandler)
+			}
+		}()
+
+		h.exportSongs(w, httptest.NewRequest(http.MethodGet, "/songs/export", nil), dto.ExportSongsDto{Format: domain.FormatNDJSON})
+	})
+}
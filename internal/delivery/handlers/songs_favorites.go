@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// @Summary Get favorite songs
+// @Description Retrieve a paginated list of songs favorited by the authenticated user.
+// @Tags favorites
+// @Produce  json
+// @Param page query int false "Page number for pagination"
+// @Param limit query int false "Number of songs per page"
+// @Success 200 {object} dto.SongsDto "List of favorite songs"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /me/favorites [get]
+func (h SongsHandler) getFavorites(w http.ResponseWriter, r *http.Request, params dto.PaginationParamsDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	songs, totalPages, err := h.songsService.GetSongs(r.Context(), principal.UserID, dto.GetSongsDto{
+		PaginationParams: params,
+		Favorited:        true,
+	})
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingFavorites)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingFavorites, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, h.toSongsDto(songs, totalPages))
+}
+
+// @Summary Add a song to favorites
+// @Description Add a song to the favorites of the authenticated user. Adding a song twice has no effect.
+// @Tags favorites
+// @Param songID path int true "Song ID"
+// @Success 200 "Song added to favorites"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /me/favorites/{songID} [put]
+func (h SongsHandler) addFavorite(w http.ResponseWriter, r *http.Request, songID int) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	if err := h.songsService.AddFavorite(r.Context(), principal.UserID, int32(songID)); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrAddingFavorite)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrAddingFavorite, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+// @Summary Remove a song from favorites
+// @Description Remove a song from the favorites of the authenticated user. Removing a song that is not a favorite has no effect.
+// @Tags favorites
+// @Param songID path int true "Song ID"
+// @Success 200 "Song removed from favorites"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /me/favorites/{songID} [delete]
+func (h SongsHandler) removeFavorite(w http.ResponseWriter, r *http.Request, songID int) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	if err := h.songsService.RemoveFavorite(r.Context(), principal.UserID, int32(songID)); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrRemovingFavorite)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrRemovingFavorite, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, nil)
+}
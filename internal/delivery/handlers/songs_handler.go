@@ -1,26 +1,36 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
-	log "github.com/sirupsen/logrus"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"io"
 	"net/http"
 	_ "songs-library-go/docs"
 	"songs-library-go/internal/delivery"
 	"songs-library-go/internal/delivery/dto"
 	"songs-library-go/internal/delivery/middleware"
 	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
 )
 
 // SongsService defines the methods for managing songs, including retrieval, creation, updating, and deletion.
 type SongsService interface {
-	GetSongs(params dto.GetSongsDto) ([]domain.Song, int, error)
-	GetSongText(songID int32, params dto.PaginationParamsDto) ([]string, int, error)
-	Delete(songID int32) error
-	Update(songID int32, updateSongInput dto.SongParamsDto) (domain.Song, error)
-	Create(createSongInput dto.CreateSongDto) (domain.Song, error)
+	GetSongs(ctx context.Context, userID int32, params dto.GetSongsDto) ([]domain.Song, int, error)
+	GetRandomSongs(ctx context.Context, userID int32, params dto.RandomSongsParamsDto) ([]domain.Song, error)
+	GetDailySong(ctx context.Context, userID int32) (domain.Song, error)
+	GetSongText(ctx context.Context, songID int32, params dto.PaginationParamsDto) ([]string, int, error)
+	Delete(ctx context.Context, songID int32) error
+	Replace(ctx context.Context, songID int32, replaceSongInput dto.ReplaceSongDto) (domain.Song, error)
+	Patch(ctx context.Context, songID int32, patch dto.SongPatchDto) (domain.Song, error)
+	Create(ctx context.Context, createSongInput dto.CreateSongDto) (domain.Song, error)
+	Batch(ctx context.Context, batch dto.BatchDto) ([]domain.SongOperationResult, error)
+	Export(ctx context.Context, params dto.ExportSongsDto, fn func(domain.Song) error) error
+	Import(ctx context.Context, r io.Reader, params dto.ImportParamsDto) (domain.ImportReport, error)
+	AddFavorite(ctx context.Context, userID, songID int32) error
+	RemoveFavorite(ctx context.Context, userID, songID int32) error
 }
 
 // SongsHandler manages HTTP requests related to songs and validates input using the provided validator.
@@ -38,15 +48,35 @@ func NewSongsHandler(validator *validator.Validate, songsService SongsService) *
 }
 
 // RegisterRoutes sets up the HTTP routes for song-related operations using the Chi router.
+// Every route requires the permission of the operation it performs, see domain.RolePermissions.
 func (h SongsHandler) RegisterRoutes(r *chi.Mux) {
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
 
+	read := middleware.RequirePermission(domain.PermissionReadSongs)
+	write := middleware.RequirePermission(domain.PermissionWriteSongs)
+	remove := middleware.RequirePermission(domain.PermissionDeleteSongs)
+	favorites := middleware.RequirePermission(domain.PermissionFavorites)
+
 	r.Route("/songs", func(r chi.Router) {
-		r.Get("/", middleware.ValidateGetSongsParam(h.validator, h.getSongs))
-		r.Get("/{id}", middleware.ValidateGetSongParam(h.validator, h.getSongText))
-		r.Delete("/{id}", middleware.ValidateIDInput(h.deleteSong))
-		r.Put("/{id}", middleware.ValidateUpdateSongInput(h.validator, h.updateSong))
-		r.Post("/", middleware.ValidateCreateSongInput(h.validator, h.createSong))
+		r.With(read).Get("/", middleware.ValidateGetSongsParam(h.validator, h.getSongs))
+		r.With(read).Get("/export", middleware.ValidateExportSongsParam(h.validator, h.exportSongs))
+		r.With(read).Get("/random", middleware.ValidateRandomSongsParam(h.validator, h.getRandomSongs))
+		r.With(read).Get("/daily", h.getDailySong)
+		r.With(read).Get("/{id}", middleware.ValidateGetSongParam(h.validator, h.getSongText))
+		r.With(remove).Delete("/{id}", middleware.ValidateIDInput(h.deleteSong))
+		r.With(write).Put("/{id}", middleware.ValidateReplaceSongInput(h.validator, h.replaceSong))
+		r.With(write).Patch("/{id}", middleware.ValidatePatchSongInput(h.validator, h.patchSong))
+		r.With(write).Post("/", middleware.ValidateCreateSongInput(h.validator, h.createSong))
+		r.With(write).Post("/import", middleware.ValidateImportSongsParam(h.validator, h.importSongs))
+		r.With(write).Post("/batch", middleware.ValidateBatchInput(h.validator, h.batchSongs))
+	})
+
+	r.Route("/me/favorites", func(r chi.Router) {
+		r.Use(favorites)
+
+		r.Get("/", middleware.ValidatePaginationParam(h.validator, h.getFavorites))
+		r.Put("/{id}", middleware.ValidateIDInput(h.addFavorite))
+		r.Delete("/{id}", middleware.ValidateIDInput(h.removeFavorite))
 	})
 }
 
@@ -58,14 +88,21 @@ func (h SongsHandler) RegisterRoutes(r *chi.Mux) {
 // @Param body body dto.SongParamsDto true "Filters"
 // @Param page query int false "Page number for pagination"
 // @Param limit query int false "Number of songs per page"
+// @Param favorited query bool false "Only songs favorited by the authenticated user"
+// @Param min_rating query number false "Only songs with at least this average rating, from 1 to 5"
+// @Param sort query string false "Sort order: popular orders songs by their plays in period, rating by their average rating" Enums(popular, rating)
+// @Param period query string false "Period in days for sort=popular, from 1d to 365d" default(7d)
 // @Success 200 {object} dto.SongsDto "List of songs"
-// @Failure 500 {object} delivery.JSONError "Internal Server Error"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
 // @Router /songs [get]
 func (h SongsHandler) getSongs(w http.ResponseWriter, r *http.Request, params dto.GetSongsDto) {
-	songs, totalPages, err := h.songsService.GetSongs(params)
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	songs, totalPages, err := h.songsService.GetSongs(r.Context(), principal.UserID, params)
 	if err != nil {
-		log.WithError(err).Error(delivery.ErrGettingSongs)
-		delivery.RespondWithJSON(w, http.StatusInternalServerError, delivery.JSONError{Error: delivery.ErrGettingSongs})
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingSongs)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingSongs, err))
 		return
 	}
 
@@ -81,20 +118,15 @@ func (h SongsHandler) getSongs(w http.ResponseWriter, r *http.Request, params dt
 // @Param page query int false "Page number for pagination"
 // @Param limit query int false "Number of verses per page"
 // @Success 200 {object} dto.VersesDto "List of song verses"
-// @Failure 404 {object} delivery.JSONError "Not Found"
-// @Failure 500 {object} delivery.JSONError "Internal Server Error"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
 // @Router /songs/{songID} [get]
 func (h SongsHandler) getSongText(w http.ResponseWriter, r *http.Request, songID int, params dto.PaginationParamsDto) {
-	verses, totalPages, err := h.songsService.GetSongText(int32(songID), params)
+	verses, totalPages, err := h.songsService.GetSongText(r.Context(), int32(songID), params)
 	if err != nil {
-		log.WithError(err).Error(delivery.ErrGettingSongText)
-
-		if errors.Is(err, domain.ErrSongNotFound) {
-			delivery.RespondWithJSON(w, http.StatusNotFound, delivery.JSONError{Error: delivery.ErrGettingSongText, Message: domain.ErrSongNotFound.Error()})
-			return
-		}
-
-		delivery.RespondWithJSON(w, http.StatusInternalServerError, delivery.JSONError{Error: delivery.ErrGettingSongText})
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingSongText)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingSongText, err))
 		return
 	}
 
@@ -111,57 +143,69 @@ func (h SongsHandler) getSongText(w http.ResponseWriter, r *http.Request, songID
 // @Produce  json
 // @Param songID path int true "Song ID"
 // @Success 200 "Song successfully deleted"
-// @Failure 404 {object} delivery.JSONError "Not Found"
-// @Failure 500 {object} delivery.JSONError "Internal Server Error"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
 // @Router /songs/{songID} [delete]
 func (h SongsHandler) deleteSong(w http.ResponseWriter, r *http.Request, songID int) {
-	if err := h.songsService.Delete(int32(songID)); err != nil {
-		log.WithError(err).Error(delivery.ErrDeletingSong)
-
-		if errors.Is(err, domain.ErrSongNotFound) {
-			delivery.RespondWithJSON(w, http.StatusNotFound, delivery.JSONError{Error: delivery.ErrDeletingSong, Message: domain.ErrSongNotFound.Error()})
-			return
-		}
-
-		delivery.RespondWithJSON(w, http.StatusInternalServerError, delivery.JSONError{Error: delivery.ErrDeletingSong})
+	if err := h.songsService.Delete(r.Context(), int32(songID)); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrDeletingSong)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrDeletingSong, err))
 		return
 	}
 
 	delivery.RespondWithJSON(w, http.StatusOK, nil)
 }
 
-// @Summary Update a song by song ID
-// @Description Update the details of an existing song based on its ID.
+// @Summary Replace a song by song ID
+// @Description Replace all fields of an existing song based on its ID. Omitted release_date, text and link are cleared.
 // @Tags songs
 // @Accept  json
 // @Produce  json
 // @Param songID path int true "Song ID"
-// @Param body body dto.SongParamsDto true "Song details to update"
-// @Success 200 {object} dto.SongDto "Updated song"
-// @Failure 400 {object} delivery.JSONError "Bad Request"
-// @Failure 404 {object} delivery.JSONError "Not Found"
-// @Failure 500 {object} delivery.JSONError "Internal Server Error"
+// @Param body body dto.ReplaceSongDto true "Full song representation"
+// @Success 200 {object} dto.SongDto "Replaced song"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
 // @Router /songs/{songID} [put]
-func (h SongsHandler) updateSong(w http.ResponseWriter, r *http.Request, songID int, updateSongInput dto.SongParamsDto) {
-	song, err := h.songsService.Update(int32(songID), updateSongInput)
+func (h SongsHandler) replaceSong(w http.ResponseWriter, r *http.Request, songID int, replaceSongInput dto.ReplaceSongDto) {
+	song, err := h.songsService.Replace(r.Context(), int32(songID), replaceSongInput)
 	if err != nil {
-		log.WithError(err).Error(delivery.ErrUpdatingSong)
-
-		if errors.Is(err, domain.ErrSongNotFound) {
-			delivery.RespondWithJSON(w, http.StatusNotFound, delivery.JSONError{Error: delivery.ErrUpdatingSong, Message: domain.ErrSongNotFound.Error()})
-			return
-		}
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrReplacingSong)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrReplacingSong, err))
+		return
+	}
 
-		if errors.Is(err, domain.ErrSongAlreadyExist) {
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrUpdatingSong, Message: domain.ErrSongAlreadyExist.Error()})
-			return
-		}
+	delivery.RespondWithJSON(w, http.StatusOK, toSongDto(song))
+}
 
-		delivery.RespondWithJSON(w, http.StatusInternalServerError, delivery.JSONError{Error: delivery.ErrUpdatingSong})
+// @Summary Patch a song by song ID
+// @Description Partially update a song with a JSON Merge Patch (null clears release_date, text or link) or a JSON Patch.
+// @Tags songs
+// @Accept  application/merge-patch+json
+// @Accept  application/json-patch+json
+// @Produce  json
+// @Param songID path int true "Song ID"
+// @Param body body dto.SongParamsDto true "Merge patch, or an array of dto.JSONPatchOperationDto for JSON Patch"
+// @Success 200 {object} dto.SongDto "Patched song"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 409 {object} delivery.Problem "JSON Patch test failed"
+// @Failure 415 {object} delivery.Problem "Unsupported Media Type"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID} [patch]
+func (h SongsHandler) patchSong(w http.ResponseWriter, r *http.Request, songID int, patch dto.SongPatchDto) {
+	song, err := h.songsService.Patch(r.Context(), int32(songID), patch)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrPatchingSong)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrPatchingSong, err))
 		return
 	}
 
-	delivery.RespondWithJSON(w, http.StatusOK, h.toSongDto(song))
+	delivery.RespondWithJSON(w, http.StatusOK, toSongDto(song))
 }
 
 // @Summary Create a new song
@@ -171,53 +215,190 @@ func (h SongsHandler) updateSong(w http.ResponseWriter, r *http.Request, songID
 // @Produce  json
 // @Param body body dto.CreateSongDto true "Song details to create"
 // @Success 201 {object} dto.SongDto "Created song"
-// @Failure 400 {object} delivery.JSONError "Bad Request"
-// @Failure 500 {object} delivery.JSONError "Internal Server Error"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
 // @Router /songs [post]
 func (h SongsHandler) createSong(w http.ResponseWriter, r *http.Request, createSongInput dto.CreateSongDto) {
-	song, err := h.songsService.Create(createSongInput)
+	song, err := h.songsService.Create(r.Context(), createSongInput)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrCreatingSong)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrCreatingSong, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusCreated, toSongDto(song))
+}
+
+// @Summary Apply a batch of song operations
+// @Description Apply a list of mixed create, update and delete operations. Atomic batches run in one transaction and are rolled back on the first failure, otherwise every operation is applied independently.
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param body body dto.BatchInputDto true "Batch of operations"
+// @Success 200 {object} dto.BatchResultDto "Per-operation results"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 409 {object} dto.BatchResultDto "Atomic batch rolled back"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/batch [post]
+func (h SongsHandler) batchSongs(w http.ResponseWriter, r *http.Request, batch dto.BatchDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	for _, operation := range batch.Operations {
+		if operation.Op == string(domain.OpDelete) && !principal.Can(domain.PermissionDeleteSongs) {
+			logging.FromContext(r.Context()).WithError(domain.ErrPermissionDenied).Error(delivery.ErrApplyingBatch)
+			delivery.RespondWithProblem(w, r, delivery.ForbiddenProblem(principal, domain.PermissionDeleteSongs))
+			return
+		}
+	}
+
+	results, err := h.songsService.Batch(r.Context(), batch)
+	if err != nil && !errors.Is(err, domain.ErrBatchAborted) {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrApplyingBatch)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrApplyingBatch, err))
+		return
+	}
+
+	resultDto := dto.BatchResultDto{
+		Atomic:  batch.Atomic,
+		Results: make([]dto.BatchOperationResultDto, len(results)),
+	}
+
+	for i, result := range results {
+		operationResult := dto.BatchOperationResultDto{
+			Index:  i,
+			Op:     batch.Operations[i].Op,
+			Status: http.StatusOK,
+		}
+
+		switch {
+		case result.Err != nil:
+			logging.FromContext(r.Context()).WithError(result.Err).Errorf("%s (operation: %d)", delivery.ErrApplyingBatch, i)
+			problem := delivery.ErrorProblem(delivery.ErrApplyingBatch, result.Err)
+			operationResult.Status, operationResult.Code, operationResult.Error = problem.Status, problem.Code, problem.Detail
+			resultDto.Failed++
+		case batch.Operations[i].Op == string(domain.OpCreate):
+			operationResult.Status = http.StatusCreated
+			fallthrough
+		default:
+			if batch.Operations[i].Op != string(domain.OpDelete) {
+				songDto := toSongDto(result.Song)
+				operationResult.Song = &songDto
+			}
+			resultDto.Succeeded++
+		}
+
+		resultDto.Results[i] = operationResult
+	}
+
 	if err != nil {
-		log.WithError(err).Error(delivery.ErrCreatingSong)
+		delivery.RespondWithJSON(w, http.StatusConflict, resultDto)
+		return
+	}
 
-		if errors.Is(err, domain.ErrSongAlreadyExist) {
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrCreatingSong, Message: domain.ErrSongAlreadyExist.Error()})
+	delivery.RespondWithJSON(w, http.StatusOK, resultDto)
+}
+
+// @Summary Import songs
+// @Description Stream a CSV (with a header row) or NDJSON file of songs and insert them in batches. Conflicts with existing songs are skipped, updated or abort the whole import depending on on_conflict.
+// @Tags songs
+// @Accept  text/csv
+// @Accept  application/x-ndjson
+// @Produce  json
+// @Param format query string false "File format: csv or ndjson (defaults to the Content-Type)"
+// @Param on_conflict query string false "Conflict policy: skip, update or fail (default skip)"
+// @Success 200 {object} dto.ImportReportDto "Per-row import report"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 409 {object} dto.ImportReportDto "Import aborted on conflict"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/import [post]
+func (h SongsHandler) importSongs(w http.ResponseWriter, r *http.Request, params dto.ImportParamsDto) {
+	report, err := h.songsService.Import(r.Context(), r.Body, params)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrImportingSongs)
+
+		if errors.Is(err, domain.ErrImportConflict) {
+			delivery.RespondWithJSON(w, http.StatusConflict, h.toImportReportDto(report))
 			return
 		}
 
-		delivery.RespondWithJSON(w, http.StatusInternalServerError, delivery.JSONError{Error: delivery.ErrCreatingSong})
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrImportingSongs, err))
 		return
 	}
 
-	delivery.RespondWithJSON(w, http.StatusCreated, h.toSongDto(song))
+	delivery.RespondWithJSON(w, http.StatusOK, h.toImportReportDto(report))
+}
+
+func (h SongsHandler) toImportReportDto(report domain.ImportReport) dto.ImportReportDto {
+	reportDto := dto.ImportReportDto{
+		Total: len(report.Rows),
+		Rows:  make([]dto.ImportRowDto, 0, len(report.Rows)),
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case domain.ImportCreated:
+			reportDto.Created++
+		case domain.ImportUpdated:
+			reportDto.Updated++
+		case domain.ImportSkipped:
+			reportDto.Skipped++
+		default:
+			reportDto.Failed++
+		}
+
+		reportDto.Rows = append(reportDto.Rows, dto.ImportRowDto{
+			Row:    row.Row,
+			Status: string(row.Status),
+			ID:     row.SongID,
+			Group:  row.Group,
+			Song:   row.Song,
+			Error:  row.Error,
+		})
+	}
+
+	return reportDto
 }
 
 func (h SongsHandler) toSongsDto(songs []domain.Song, totalPages int) dto.SongsDto {
+	return dto.SongsDto{
+		Songs:      h.toListSongDtos(songs),
+		TotalPages: totalPages,
+	}
+}
+
+// toListSongDtos converts songs listed for the authenticated user, which carry the favorite flag.
+func (h SongsHandler) toListSongDtos(songs []domain.Song) []dto.SongDto {
 	songsDto := make([]dto.SongDto, 0)
 
 	for _, song := range songs {
-		songDto := h.toSongDto(song)
+		songDto := toSongDto(song)
+		songDto.IsFavorite = &song.IsFavorite
 		songsDto = append(songsDto, songDto)
 	}
 
-	return dto.SongsDto{
-		Songs:      songsDto,
-		TotalPages: totalPages,
-	}
+	return songsDto
 }
 
-func (h SongsHandler) toSongDto(song domain.Song) dto.SongDto {
+// toSongDto converts a song to its representation in responses of all handlers.
+func toSongDto(song domain.Song) dto.SongDto {
 	var releaseDate string
 	if !song.ReleaseDate.IsZero() {
 		releaseDate = song.ReleaseDate.Format(domain.DateFormat)
 	}
 
 	songDto := dto.SongDto{
-		ID:          song.ID,
-		Group:       song.Group,
-		Song:        song.Song,
-		ReleaseDate: releaseDate,
-		Text:        song.Text,
-		Link:        song.Link,
+		ID:            song.ID,
+		Group:         song.Group,
+		Song:          song.Song,
+		ReleaseDate:   releaseDate,
+		Text:          song.Text,
+		Link:          song.Link,
+		Plays:         song.Plays,
+		AverageRating: song.AverageRating,
+		RatingsCount:  song.RatingsCount,
 	}
 
 	return songDto
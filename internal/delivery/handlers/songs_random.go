@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// @Summary Get random songs
+// @Description Retrieve distinct random songs matching the same filters as the list of songs. Fewer songs are returned if fewer songs match.
+// @Tags songs
+// @Produce  json
+// @Param count query int false "Number of songs" default(1)
+// @Param group query string false "Filter by group name"
+// @Param song query string false "Filter by song name"
+// @Param release_date query string false "Filter by release date"
+// @Param text query string false "Filter by song text"
+// @Param link query string false "Filter by song link"
+// @Param favorited query bool false "Only songs favorited by the authenticated user"
+// @Param min_rating query number false "Minimal average rating"
+// @Success 200 {object} dto.RandomSongsDto "Random songs"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/random [get]
+func (h SongsHandler) getRandomSongs(w http.ResponseWriter, r *http.Request, params dto.RandomSongsParamsDto) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	songs, err := h.songsService.GetRandomSongs(r.Context(), principal.UserID, params)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingRandom)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingRandom, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, dto.RandomSongsDto{Songs: h.toListSongDtos(songs)})
+}
+
+// @Summary Get the song of the day
+// @Description Retrieve the song of the current UTC day, which is the same for everyone.
+// @Tags songs
+// @Produce  json
+// @Success 200 {object} dto.SongDto "Song of the day"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/daily [get]
+func (h SongsHandler) getDailySong(w http.ResponseWriter, r *http.Request) {
+	principal, _ := domain.PrincipalFromContext(r.Context())
+
+	song, err := h.songsService.GetDailySong(r.Context(), principal.UserID)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingDaily)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingDaily, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, h.toListSongDtos([]domain.Song{song})[0])
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/delivery/middleware"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// TagsService defines the methods for reading and replacing the tags of songs.
+type TagsService interface {
+	GetTags(ctx context.Context, songID int32) ([]string, error)
+	ReplaceTags(ctx context.Context, songID int32, tagsInput dto.SongTagsDto) ([]string, error)
+}
+
+// TagsHandler manages HTTP requests for the tags of songs.
+type TagsHandler struct {
+	validator   *validator.Validate
+	tagsService TagsService
+}
+
+// NewTagsHandler initializes and returns a new instance of TagsHandler with the provided validator and tags service.
+func NewTagsHandler(validator *validator.Validate, tagsService TagsService) *TagsHandler {
+	return &TagsHandler{
+		validator:   validator,
+		tagsService: tagsService,
+	}
+}
+
+// RegisterRoutes sets up the HTTP routes for tags using the Chi router.
+func (h TagsHandler) RegisterRoutes(r *chi.Mux) {
+	read := middleware.RequirePermission(domain.PermissionReadSongs)
+	write := middleware.RequirePermission(domain.PermissionWriteSongs)
+
+	r.With(read).Get("/songs/{id}/tags", middleware.ValidateIDInput(h.getTags))
+	r.With(write).Put("/songs/{id}/tags", middleware.ValidateReplaceTagsInput(h.validator, h.replaceTags))
+}
+
+// @Summary Get tags of a song
+// @Description Retrieve the tags of a song, such as genres and moods, in alphabetical order.
+// @Tags songs
+// @Produce  json
+// @Param songID path int true "Song ID"
+// @Success 200 {object} dto.SongTagsDto "Tags"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID}/tags [get]
+func (h TagsHandler) getTags(w http.ResponseWriter, r *http.Request, songID int) {
+	tags, err := h.tagsService.GetTags(r.Context(), int32(songID))
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrGettingTags)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrGettingTags, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, dto.SongTagsDto{Tags: tags})
+}
+
+// @Summary Replace tags of a song
+// @Description Replace the tags of a song with up to 20 tags of up to 50 characters. Tags are lowercased and duplicates are dropped.
+// @Description Shared tags make songs more similar after the next computation of similar songs.
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param songID path int true "Song ID"
+// @Param body body dto.SongTagsDto true "Tags"
+// @Success 200 {object} dto.SongTagsDto "Tags"
+// @Failure 400 {object} delivery.Problem "Bad Request"
+// @Failure 403 {object} delivery.Problem "Forbidden"
+// @Failure 404 {object} delivery.Problem "Not Found"
+// @Failure 500 {object} delivery.Problem "Internal Server Error"
+// @Router /songs/{songID}/tags [put]
+func (h TagsHandler) replaceTags(w http.ResponseWriter, r *http.Request, songID int, tagsInput dto.SongTagsDto) {
+	tags, err := h.tagsService.ReplaceTags(r.Context(), int32(songID), tagsInput)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrReplacingTags)
+		delivery.RespondWithProblem(w, r, delivery.ErrorProblem(delivery.ErrReplacingTags, err))
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, dto.SongTagsDto{Tags: tags})
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/logging"
+	"strings"
+)
+
+// ValidateCreateAnnotationInput validates the song ID and the body and anchor of a new annotation.
+func ValidateCreateAnnotationInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.CreateAnnotationDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		songID, err := extractAndValidateID(w, r)
+		if err != nil {
+			return
+		}
+
+		var annotationInput dto.CreateAnnotationDto
+
+		if err := json.NewDecoder(r.Body).Decode(&annotationInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidAnnotationInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidAnnotationInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		annotationInput.Body = strings.TrimSpace(annotationInput.Body)
+
+		if err := v.Struct(annotationInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidAnnotationInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidAnnotationInput, err))
+			return
+		}
+
+		next(w, r, songID, annotationInput)
+	}
+}
+
+// ValidateVoteInput validates the annotation ID and the vote on it.
+func ValidateVoteInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.VoteDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		annotationID, ok := extractPositiveID(w, r, "id", delivery.ErrInvalidAnnotationIDInput)
+		if !ok {
+			return
+		}
+
+		var voteInput dto.VoteDto
+
+		if err := json.NewDecoder(r.Body).Decode(&voteInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidVoteInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidVoteInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		if err := v.Struct(voteInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidVoteInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidVoteInput, err))
+			return
+		}
+
+		next(w, r, annotationID, voteInput)
+	}
+}
+
+// ValidateAnnotationIDInput validates the annotation ID in the URL.
+func ValidateAnnotationIDInput(next func(http.ResponseWriter, *http.Request, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		annotationID, ok := extractPositiveID(w, r, "id", delivery.ErrInvalidAnnotationIDInput)
+		if !ok {
+			return
+		}
+
+		next(w, r, annotationID)
+	}
+}
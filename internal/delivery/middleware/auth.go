@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"strings"
+)
+
+const mesAudit = "audit"
+
+// Authenticator resolves the principal for an API key or a bearer token.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (domain.Principal, error)
+}
+
+// Authenticate rejects requests without a valid API key (X-API-Key header or Authorization: Bearer) or JWT bearer token
+// with 401 and stores the authenticated principal in the request context. The Swagger UI, the probes and the metrics stay public.
+func Authenticate(authenticator Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, delivery.SwaggerPathPrefix) || isPublicPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var principal domain.Principal
+			var err error
+
+			if credential := credentialFromRequest(r); credential != "" {
+				principal, err = authenticator.Authenticate(r.Context(), credential)
+			} else {
+				err = domain.ErrMissingCredentials
+			}
+
+			if err != nil {
+				logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrAuthenticating)
+
+				problem := delivery.ErrorProblem(delivery.ErrAuthenticating, err)
+				if problem.Status == http.StatusUnauthorized {
+					problem.Title = delivery.ErrUnauthorized
+					problem.Detail += ", " + delivery.MesAuthenticationHint
+					w.Header().Set("WWW-Authenticate", delivery.AuthSchemeBearer)
+				}
+
+				delivery.RespondWithProblem(w, r, problem)
+				return
+			}
+
+			logging.AddFields(r.Context(), log.Fields{"principal": principal.String()})
+
+			next.ServeHTTP(w, r.WithContext(domain.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// isPublicPath reports whether the path is one of the endpoints for orchestrators and monitoring, which can't authenticate.
+func isPublicPath(path string) bool {
+	return path == delivery.LivenessPath || path == delivery.ReadinessPath || path == delivery.MetricsPath
+}
+
+// RequirePermission rejects requests whose principal role does not grant the permission with 403.
+func RequirePermission(permission domain.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := domain.PrincipalFromContext(r.Context())
+
+			if !principal.Can(permission) {
+				logging.FromContext(r.Context()).WithError(domain.ErrPermissionDenied).Errorf("%s (principal: %s, permission: %s)", delivery.ErrForbidden, principal, permission)
+				delivery.RespondWithProblem(w, r, delivery.ForbiddenProblem(principal, permission))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Audit writes an audit log entry with the authenticated principal for every request that is not read-only.
+func Audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		principal, _ := domain.PrincipalFromContext(r.Context())
+
+		logging.FromContext(r.Context()).WithFields(log.Fields{
+			"principal": principal.String(),
+			"key_id":    principal.KeyID,
+			"role":      principal.Role,
+			"method":    r.Method,
+			"path":      r.URL.RequestURI(),
+			"status":    recorder.statusCode,
+		}).Info(mesAudit)
+	})
+}
+
+func credentialFromRequest(r *http.Request) string {
+	if apiKey := r.Header.Get(delivery.HeaderAPIKey); apiKey != "" {
+		return apiKey
+	}
+
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, delivery.AuthSchemeBearer) {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(data)
+}
@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/domain"
+	"strings"
+	"testing"
+)
+
+// stubAuthenticator accepts a single credential and records the last credential it has been given.
+type stubAuthenticator struct {
+	credential string
+	principal  domain.Principal
+	err        error
+	got        string
+}
+
+func (a *stubAuthenticator) Authenticate(_ context.Context, credential string) (domain.Principal, error) {
+	a.got = credential
+	if a.err != nil {
+		return domain.Principal{}, a.err
+	}
+
+	if credential != a.credential {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+
+	return a.principal, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	editor := domain.Principal{Type: domain.PrincipalAPIKey, Subject: "ci", KeyID: 1, Role: domain.RoleEditor}
+
+	tests := []struct {
+		name               string
+		path               string
+		headers            map[string]string
+		err                error
+		wantStatus         int
+		wantCredential     string
+		wantPrincipal      bool
+		wantAuthenticate   string
+		wantDetailContains string
+	}{
+		{
+			name:           "API key header",
+			path:           "/songs",
+			headers:        map[string]string{delivery.HeaderAPIKey: "sl_secret"},
+			wantStatus:     http.StatusOK,
+			wantCredential: "sl_secret",
+			wantPrincipal:  true,
+		},
+		{
+			name:           "bearer token",
+			path:           "/songs",
+			headers:        map[string]string{"Authorization": "bearer  sl_secret "},
+			wantStatus:     http.StatusOK,
+			wantCredential: "sl_secret",
+			wantPrincipal:  true,
+		},
+		{
+			name:             "missing credentials",
+			path:             "/songs",
+			wantStatus:       http.StatusUnauthorized,
+			wantAuthenticate: delivery.AuthSchemeBearer,
+		},
+		{
+			name:             "another authorization scheme",
+			path:             "/songs",
+			headers:          map[string]string{"Authorization": "Basic c2w6c2VjcmV0"},
+			wantStatus:       http.StatusUnauthorized,
+			wantAuthenticate: delivery.AuthSchemeBearer,
+		},
+		{
+			name:               "invalid credentials",
+			path:               "/songs",
+			headers:            map[string]string{delivery.HeaderAPIKey: "sl_other"},
+			wantStatus:         http.StatusUnauthorized,
+			wantCredential:     "sl_other",
+			wantAuthenticate:   delivery.AuthSchemeBearer,
+			wantDetailContains: delivery.MesAuthenticationHint,
+		},
+		{
+			name:           "failing authenticator",
+			path:           "/songs",
+			headers:        map[string]string{delivery.HeaderAPIKey: "sl_secret"},
+			err:            errors.New("connection refused"),
+			wantStatus:     http.StatusInternalServerError,
+			wantCredential: "sl_secret",
+		},
+		{name: "liveness probe", path: delivery.LivenessPath, wantStatus: http.StatusOK},
+		{name: "swagger", path: delivery.SwaggerPathPrefix + "index.html", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := &stubAuthenticator{credential: "sl_secret", principal: editor, err: tt.err}
+
+			var principal domain.Principal
+			var authenticated bool
+			handler := Authenticate(authenticator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal, authenticated = domain.PrincipalFromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if authenticator.got != tt.wantCredential {
+				t.Errorf("authenticated credential = %q, want %q", authenticator.got, tt.wantCredential)
+			}
+
+			if authenticated != tt.wantPrincipal || (tt.wantPrincipal && principal != editor) {
+				t.Errorf("principal = %+v (%t), want %+v (%t)", principal, authenticated, editor, tt.wantPrincipal)
+			}
+
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.wantAuthenticate {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantAuthenticate)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.wantDetailContains) {
+				t.Errorf("body = %s, want it to contain %q", w.Body.String(), tt.wantDetailContains)
+			}
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  *domain.Principal
+		permission domain.Permission
+		wantStatus int
+	}{
+		{
+			name:       "granted",
+			principal:  &domain.Principal{Type: domain.PrincipalJWT, Subject: "alice", Role: domain.RoleEditor},
+			permission: domain.PermissionWriteSongs,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "not granted to the role",
+			principal:  &domain.Principal{Type: domain.PrincipalJWT, Subject: "alice", Role: domain.RoleEditor},
+			permission: domain.PermissionDeleteSongs,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "viewer writing songs",
+			principal:  &domain.Principal{Type: domain.PrincipalAPIKey, Subject: "ci", KeyID: 1, Role: domain.RoleViewer},
+			permission: domain.PermissionWriteSongs,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "without a principal",
+			permission: domain.PermissionReadSongs,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			handler := RequirePermission(tt.permission)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/songs", nil)
+			if tt.principal != nil {
+				r = r.WithContext(domain.WithPrincipal(r.Context(), *tt.principal))
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if called != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("handler called = %t, want %t", called, tt.wantStatus == http.StatusOK)
+			}
+
+			if tt.wantStatus != http.StatusForbidden {
+				return
+			}
+
+			var problem delivery.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("decoding problem %s: %v", w.Body.String(), err)
+			}
+
+			if problem.Code != delivery.CodeForbidden || problem.MissingPermission != string(tt.permission) {
+				t.Errorf("problem = %+v, want code %q naming the missing permission %q", problem, delivery.CodeForbidden, tt.permission)
+			}
+
+			if !strings.Contains(problem.Detail, string(tt.permission)) {
+				t.Errorf("detail = %q, want it to name the permission %q", problem.Detail, tt.permission)
+			}
+		})
+	}
+}
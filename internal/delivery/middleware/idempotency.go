@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"time"
+)
+
+// IdempotencyStore defines methods for reserving Idempotency-Key records and storing the responses they replay.
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, record domain.IdempotencyRecord, ttl time.Duration) (domain.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, record domain.IdempotencyRecord) error
+	Release(ctx context.Context, record domain.IdempotencyRecord) error
+}
+
+// Idempotency replays the stored response of mutating requests retried with the same Idempotency-Key header.
+// Reusing a key with a different request returns 422, and retrying while the original request is still running returns 409.
+func Idempotency(store IdempotencyStore, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(delivery.HeaderIdempotencyKey)
+			if key == "" || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > delivery.MaxIdempotencyKeyLength {
+				logging.FromContext(r.Context()).Error(delivery.ErrInvalidIdempotencyKey)
+				delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidIdempotencyKey, delivery.MesInvalidIdempotencyKey))
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, delivery.MaxIdempotentBodySize+1))
+			if err != nil {
+				logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrReadingBody)
+				delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrReadingBody, "", err))
+				return
+			}
+
+			if len(body) > delivery.MaxIdempotentBodySize {
+				logging.FromContext(r.Context()).Error(delivery.ErrIdempotentBodyTooLarge)
+				delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusRequestEntityTooLarge, delivery.CodeBodyTooLarge, delivery.ErrIdempotentBodyTooLarge, delivery.MesIdempotentBodyTooLarge))
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// Keys are scoped to the principal, so a key reused by another caller is a different key.
+			var identity string
+			if principal, ok := domain.PrincipalFromContext(r.Context()); ok {
+				identity = principal.Identity()
+			}
+
+			record := domain.IdempotencyRecord{
+				Principal:   identity,
+				Key:         key,
+				Method:      r.Method,
+				Path:        r.URL.RequestURI(),
+				Fingerprint: fingerprint(identity, r.Method, r.URL.RequestURI(), body),
+			}
+
+			existing, reserved, err := store.Reserve(r.Context(), record, ttl)
+			if err != nil {
+				logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrCheckingIdempotencyKey)
+				delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusInternalServerError, delivery.CodeInternalError, delivery.ErrCheckingIdempotencyKey, ""))
+				return
+			}
+
+			if !reserved {
+				replay(w, r, record, existing)
+				return
+			}
+
+			// A handler that panics would leave the record in progress, so the key is released before the panic goes on.
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					release(r, store, record)
+					panic(recovered)
+				}
+			}()
+
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			if recorder.statusCode >= http.StatusInternalServerError {
+				release(r, store, record)
+				return
+			}
+
+			record.StatusCode = recorder.statusCode
+			record.ContentType = recorder.Header().Get("Content-Type")
+			record.Body = recorder.body.String()
+
+			// A record left in progress would answer every retry with 409 until it expires, so the key is released instead.
+			if err := store.Complete(context.WithoutCancel(r.Context()), record); err != nil {
+				logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrStoringIdempotencyKey)
+				release(r, store, record)
+			}
+		})
+	}
+}
+
+// release removes the record, so the request can be retried with the same key.
+// It isn't canceled with the request, since a client that went away is the one most likely to retry.
+func release(r *http.Request, store IdempotencyStore, record domain.IdempotencyRecord) {
+	if err := store.Release(context.WithoutCancel(r.Context()), record); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrStoringIdempotencyKey)
+	}
+}
+
+func replay(w http.ResponseWriter, r *http.Request, record, existing domain.IdempotencyRecord) {
+	if existing.Fingerprint != record.Fingerprint {
+		logging.FromContext(r.Context()).Error(delivery.ErrIdempotencyKeyReused)
+		delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusUnprocessableEntity, delivery.CodeIdempotencyKeyReused, delivery.ErrIdempotencyKeyReused, delivery.MesIdempotencyKeyReused))
+		return
+	}
+
+	if !existing.Completed {
+		logging.FromContext(r.Context()).Error(delivery.ErrIdempotentRequestInProgress)
+		delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusConflict, delivery.CodeRequestInProgress, delivery.ErrIdempotentRequestInProgress, delivery.MesIdempotentRequestInProgress))
+		return
+	}
+
+	if existing.ContentType != "" {
+		w.Header().Set("Content-Type", existing.ContentType)
+	}
+	w.Header().Set(delivery.HeaderIdempotentReplayed, "true")
+	w.WriteHeader(existing.StatusCode)
+	w.Write([]byte(existing.Body))
+}
+
+func fingerprint(principal, method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(principal + "\n" + method + " " + path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(data)
+
+	return r.ResponseWriter.Write(data)
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryIdempotencyStore keeps the records in memory, keyed by principal and key like the repository.
+type memoryIdempotencyStore struct {
+	records map[string]domain.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]domain.IdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Reserve(_ context.Context, record domain.IdempotencyRecord, _ time.Duration) (domain.IdempotencyRecord, bool, error) {
+	if existing, ok := s.records[record.Principal+"|"+record.Key]; ok {
+		return existing, false, nil
+	}
+
+	s.records[record.Principal+"|"+record.Key] = record
+	return domain.IdempotencyRecord{}, true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, record domain.IdempotencyRecord) error {
+	record.Completed = true
+	s.records[record.Principal+"|"+record.Key] = record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, record domain.IdempotencyRecord) error {
+	delete(s.records, record.Principal+"|"+record.Key)
+	return nil
+}
+
+func idempotentRequest(body string, principal *domain.Principal) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/songs", strings.NewReader(body))
+	r.Header.Set(delivery.HeaderIdempotencyKey, "key-1")
+	if principal != nil {
+		r = r.WithContext(domain.WithPrincipal(r.Context(), *principal))
+	}
+
+	return r
+}
+
+// countingHandler creates a song and counts how many times it has been called.
+func countingHandler(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	})
+}
+
+func TestIdempotency(t *testing.T) {
+	alice := &domain.Principal{Type: domain.PrincipalAPIKey, KeyID: 1}
+	bob := &domain.Principal{Type: domain.PrincipalAPIKey, KeyID: 2}
+
+	tests := []struct {
+		name          string
+		first         *http.Request
+		retry         *http.Request
+		wantStatus    int
+		wantCode      string
+		wantCalls     int
+		wantReplayed  string
+		wantRetryBody string
+		inProgress    bool
+	}{
+		{
+			name:          "retry replays the stored response",
+			first:         idempotentRequest(`{"group":"Muse"}`, alice),
+			retry:         idempotentRequest(`{"group":"Muse"}`, alice),
+			wantStatus:    http.StatusCreated,
+			wantCalls:     1,
+			wantReplayed:  "true",
+			wantRetryBody: `{"id":1}`,
+		},
+		{
+			name:       "retry with a different payload",
+			first:      idempotentRequest(`{"group":"Muse"}`, alice),
+			retry:      idempotentRequest(`{"group":"Queen"}`, alice),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   delivery.CodeIdempotencyKeyReused,
+			wantCalls:  1,
+		},
+		{
+			name:       "retry while the request is in progress",
+			first:      idempotentRequest(`{"group":"Muse"}`, alice),
+			retry:      idempotentRequest(`{"group":"Muse"}`, alice),
+			wantStatus: http.StatusConflict,
+			wantCode:   delivery.CodeRequestInProgress,
+			wantCalls:  0,
+			inProgress: true,
+		},
+		{
+			name:          "same key of another principal",
+			first:         idempotentRequest(`{"group":"Muse"}`, alice),
+			retry:         idempotentRequest(`{"group":"Muse"}`, bob),
+			wantStatus:    http.StatusCreated,
+			wantCalls:     2,
+			wantRetryBody: `{"id":1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryIdempotencyStore()
+			var calls int
+			handler := Idempotency(store, time.Hour)(countingHandler(&calls))
+
+			if tt.inProgress {
+				store.records[alice.Identity()+"|key-1"] = domain.IdempotencyRecord{
+					Principal:   alice.Identity(),
+					Key:         "key-1",
+					Fingerprint: fingerprint(alice.Identity(), http.MethodPost, "/songs", []byte(`{"group":"Muse"}`)),
+				}
+			} else {
+				handler.ServeHTTP(httptest.NewRecorder(), tt.first)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tt.retry)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if tt.wantCode != "" && !strings.Contains(w.Body.String(), `"code":"`+tt.wantCode+`"`) {
+				t.Errorf("body = %s, want code %q", w.Body.String(), tt.wantCode)
+			}
+
+			if tt.wantRetryBody != "" && w.Body.String() != tt.wantRetryBody {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.wantRetryBody)
+			}
+
+			if got := w.Header().Get(delivery.HeaderIdempotentReplayed); got != tt.wantReplayed {
+				t.Errorf("%s = %q, want %q", delivery.HeaderIdempotentReplayed, got, tt.wantReplayed)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyReleasesKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		wantPanic bool
+	}{
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+		},
+		{
+			name: "panic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				panic("boom")
+			},
+			wantPanic: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryIdempotencyStore()
+
+			func() {
+				defer func() {
+					if recovered := recover(); tt.wantPanic && recovered == nil {
+						t.Error("panic was swallowed, want it to go on")
+					}
+				}()
+				Idempotency(store, time.Hour)(tt.handler).ServeHTTP(httptest.NewRecorder(), idempotentRequest(`{}`, nil))
+			}()
+
+			if len(store.records) != 0 {
+				t.Errorf("records = %v, want the key released", store.records)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/logging"
+	"strconv"
+	"strings"
+)
+
+// ValidateIssueKeyInput validates the name and role of a new API key.
+func ValidateIssueKeyInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.IssueAPIKeyDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var issueKeyInput dto.IssueAPIKeyDto
+
+		if err := json.NewDecoder(r.Body).Decode(&issueKeyInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidIssueKeyInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidIssueKeyInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		issueKeyInput.Name = strings.TrimSpace(issueKeyInput.Name)
+
+		if err := v.Struct(issueKeyInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidIssueKeyInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidIssueKeyInput, err))
+			return
+		}
+
+		next(w, r, issueKeyInput)
+	}
+}
+
+// ValidateKeyIDInput validates the API key ID in the URL.
+func ValidateKeyIDInput(next func(http.ResponseWriter, *http.Request, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keyID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+		if err != nil || keyID <= 0 {
+			if err == nil {
+				err = errors.New(delivery.MesInvalidIDInput)
+			}
+
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidKeyIDInput)
+			delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusBadRequest, delivery.CodeInvalidID, delivery.ErrInvalidKeyIDInput, delivery.MesInvalidIDInput))
+			return
+		}
+
+		next(w, r, int(keyID))
+	}
+}
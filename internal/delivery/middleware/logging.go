@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/logging"
+	"songs-library-go/internal/metrics"
+	"time"
+)
+
+// RequestID takes the request ID from the X-Request-ID header, or generates one if it is missing or invalid,
+// stores it in the request context and echoes it in the X-Request-ID response header.
+func RequestID(next http.Handler) http.Handler {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(delivery.HeaderRequestID, chimiddleware.GetReqID(r.Context()))
+		next.ServeHTTP(w, r)
+	})
+	withRequestID := chimiddleware.RequestID(echo)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validRequestID(r.Header.Get(delivery.HeaderRequestID)) {
+			r.Header.Del(delivery.HeaderRequestID)
+		}
+
+		withRequestID.ServeHTTP(w, r)
+	})
+}
+
+// Logger stores a logger carrying the request ID and the trace ID in the request context and writes one access log
+// line per request. Requests of the probes and the metrics are logged at the debug level, so they don't flood the logs.
+// It must be used after RequestID and Tracing.
+func Logger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		fields := log.Fields{"request_id": chimiddleware.GetReqID(r.Context())}
+		if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.IsValid() {
+			fields["trace_id"] = spanContext.TraceID().String()
+		}
+
+		ctx := logging.NewContext(r.Context(), log.WithFields(fields))
+
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		route, ok := routePattern(r)
+		if !ok {
+			route = metrics.UnmatchedRoute
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		entry := logging.FromContext(ctx).WithFields(log.Fields{
+			"method":     r.Method,
+			"route":      route,
+			"path":       r.URL.RequestURI(),
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      ww.BytesWritten(),
+			"remote":     r.RemoteAddr,
+		})
+
+		switch {
+		case isPublicPath(r.URL.Path):
+			entry.Debug(delivery.MesAccessLog)
+		case status >= http.StatusInternalServerError:
+			entry.Error(delivery.MesAccessLog)
+		default:
+			entry.Info(delivery.MesAccessLog)
+		}
+	})
+}
+
+// validRequestID reports whether a request ID sent by the client is short printable ASCII, safe to log and echo.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > delivery.MaxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < '!' || requestID[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"net/http"
+	"songs-library-go/internal/metrics"
+	"strconv"
+)
+
+// Metrics records the number, latency and in-flight count of requests by method, chi route pattern and status.
+// It must be used on the root router, which resolves the route pattern before routing the request.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, ok := routePattern(r)
+		if !ok {
+			route = metrics.UnmatchedRoute
+		}
+
+		done := metrics.StartRequest(r.Method, route)
+
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			done(strconv.Itoa(status))
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// routePattern resolves the chi route pattern of the request, such as /songs/{id}, before the request is routed.
+// It reports false if no route matches the request.
+func routePattern(r *http.Request) (string, bool) {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return "", false
+	}
+
+	matchCtx := chi.NewRouteContext()
+	if !rctx.Routes.Match(matchCtx, r.Method, r.URL.Path) {
+		return "", false
+	}
+
+	return matchCtx.RoutePattern(), true
+}
@@ -1,16 +1,21 @@
 package middleware
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
-	log "github.com/sirupsen/logrus"
+	"io"
+	"mime"
 	"net/http"
 	"reflect"
+	"slices"
 	"songs-library-go/internal/delivery"
 	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
 	"strconv"
 	"strings"
 )
@@ -28,7 +33,17 @@ func ValidateGetSongsParam(v *validator.Validate, next func(http.ResponseWriter,
 			return
 		}
 
-		filters, err := getFilters(w, r)
+		favorited, err := getBoolParam(w, r, "favorited")
+		if err != nil {
+			return
+		}
+
+		minRating, err := getFloatParam(w, r, "min_rating")
+		if err != nil {
+			return
+		}
+
+		filters, err := getFilters(w, r, "page", "limit", "favorited", "min_rating", "sort", "period")
 		if err != nil {
 			return
 		}
@@ -39,11 +54,19 @@ func ValidateGetSongsParam(v *validator.Validate, next func(http.ResponseWriter,
 				Page:  page,
 				Limit: limit,
 			},
+			Favorited: favorited,
+			MinRating: minRating,
+			Sort:      r.URL.Query().Get("sort"),
+			Period:    r.URL.Query().Get("period"),
+		}
+
+		if getSongsDto.Period == "" {
+			getSongsDto.Period = delivery.DefaultPeriod
 		}
 
 		if err := v.Struct(getSongsDto); err != nil {
-			log.WithError(err).Error(delivery.ErrInvalidGetSongsParam)
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidGetSongsParam, Message: delivery.MesInvalidGetSongsParam})
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidGetSongsParam)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidGetSongsParam, err))
 			return
 		}
 
@@ -51,6 +74,73 @@ func ValidateGetSongsParam(v *validator.Validate, next func(http.ResponseWriter,
 	}
 }
 
+// ValidateExportSongsParam validates the export format and filter parameters for exporting songs.
+func ValidateExportSongsParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.ExportSongsDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filters, err := getFilters(w, r, "format")
+		if err != nil {
+			return
+		}
+
+		exportSongsDto := dto.ExportSongsDto{
+			Filters: filters,
+			Format:  r.URL.Query().Get("format"),
+		}
+
+		if exportSongsDto.Format == "" {
+			exportSongsDto.Format = delivery.DefaultExportFormat
+		}
+
+		if err := v.Struct(exportSongsDto); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidExportParam)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidExportParam, err))
+			return
+		}
+
+		next(w, r, exportSongsDto)
+	}
+}
+
+// ValidateRandomSongsParam validates the number of songs and the filter parameters for getting random songs.
+func ValidateRandomSongsParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.RandomSongsParamsDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		count, err := getPaginationParam(w, r, "count", delivery.DefaultRandomCount)
+		if err != nil {
+			return
+		}
+
+		favorited, err := getBoolParam(w, r, "favorited")
+		if err != nil {
+			return
+		}
+
+		minRating, err := getFloatParam(w, r, "min_rating")
+		if err != nil {
+			return
+		}
+
+		filters, err := getFilters(w, r, "count", "favorited", "min_rating")
+		if err != nil {
+			return
+		}
+
+		randomSongsParams := dto.RandomSongsParamsDto{
+			Filters:   filters,
+			Count:     count,
+			Favorited: favorited,
+			MinRating: minRating,
+		}
+
+		if err := v.Struct(randomSongsParams); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidRandomParam)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidRandomParam, err))
+			return
+		}
+
+		next(w, r, randomSongsParams)
+	}
+}
+
 // ValidateGetSongParam validates the song ID and pagination parameters for retrieving a specific song.
 func ValidateGetSongParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.PaginationParamsDto)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -75,8 +165,8 @@ func ValidateGetSongParam(v *validator.Validate, next func(http.ResponseWriter,
 		}
 
 		if err := v.Struct(paginationParams); err != nil {
-			log.WithError(err).Error(delivery.ErrInvalidPaginationParam)
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidPaginationParam, Message: delivery.MesInvalidPaginationParam})
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidPaginationParam)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidPaginationParam, err))
 			return
 		}
 
@@ -84,6 +174,34 @@ func ValidateGetSongParam(v *validator.Validate, next func(http.ResponseWriter,
 	}
 }
 
+// ValidatePaginationParam validates the pagination parameters for listing songs without filters.
+func ValidatePaginationParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.PaginationParamsDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := getPaginationParam(w, r, "page", delivery.DefaultPage)
+		if err != nil {
+			return
+		}
+
+		limit, err := getPaginationParam(w, r, "limit", delivery.DefaultSongsLimit)
+		if err != nil {
+			return
+		}
+
+		paginationParams := dto.PaginationParamsDto{
+			Page:  page,
+			Limit: limit,
+		}
+
+		if err := v.Struct(paginationParams); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidPaginationParam)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidPaginationParam, err))
+			return
+		}
+
+		next(w, r, paginationParams)
+	}
+}
+
 // ValidateIDInput validates the song ID extracted from the request for further processing.
 func ValidateIDInput(next func(http.ResponseWriter, *http.Request, int)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -96,61 +214,301 @@ func ValidateIDInput(next func(http.ResponseWriter, *http.Request, int)) http.Ha
 	}
 }
 
-// ValidateUpdateSongInput validates the song ID and update input for modifying a song's details.
-func ValidateUpdateSongInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.SongParamsDto)) http.HandlerFunc {
+// ValidateReplaceSongInput validates the song ID and the full song representation for replacing a song.
+func ValidateReplaceSongInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.ReplaceSongDto)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		songID, err := extractAndValidateID(w, r)
 		if err != nil {
 			return
 		}
 
-		var updateSongInput dto.SongParamsDto
+		var replaceSongInput dto.ReplaceSongDto
+
+		if err := json.NewDecoder(r.Body).Decode(&replaceSongInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidReplaceSongInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidReplaceSongInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		trimSpace(&replaceSongInput)
 
-		if err := json.NewDecoder(r.Body).Decode(&updateSongInput); err != nil {
-			log.WithError(err).Error(delivery.ErrInvalidUpdateSongInput)
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidUpdateSongInput, Message: delivery.ErrInvalidJSON})
+		if err := v.Struct(replaceSongInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidReplaceSongInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidReplaceSongInput, err))
 			return
 		}
 
-		if !isAnyFieldProvided(updateSongInput) {
-			log.Error(delivery.ErrInvalidUpdateSongInput)
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidUpdateSongInput, Message: delivery.MesEmptyUpdateSongInput})
+		next(w, r, songID, replaceSongInput)
+	}
+}
+
+// ValidatePatchSongInput validates the song ID and a JSON Merge Patch (application/merge-patch+json) or
+// JSON Patch (application/json-patch+json) document for partially modifying a song.
+func ValidatePatchSongInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.SongPatchDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		songID, err := extractAndValidateID(w, r)
+		if err != nil {
 			return
 		}
 
-		trimSpace(&updateSongInput)
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		var patch dto.SongPatchDto
+		var problem delivery.Problem
 
-		if err := v.Struct(updateSongInput); err != nil {
-			log.WithError(err).Error(delivery.ErrInvalidUpdateSongInput)
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidUpdateSongInput, Message: delivery.MesInvalidUpdateSongInput})
+		switch mediaType {
+		case delivery.ContentTypeMergePatch, "application/json":
+			patch, problem, err = validateMergePatch(v, r.Body)
+		case delivery.ContentTypeJSONPatch:
+			patch, problem, err = validateJSONPatch(v, r.Body)
+		default:
+			logging.FromContext(r.Context()).Error(fmt.Sprintf("%s (content type: %s)", delivery.ErrUnsupportedPatchType, mediaType))
+			delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusUnsupportedMediaType, delivery.CodeUnsupportedMediaType, delivery.ErrUnsupportedPatchType, delivery.MesUnsupportedPatchType))
 			return
 		}
 
-		next(w, r, songID, updateSongInput)
+		if err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(problem.Title)
+			delivery.RespondWithProblem(w, r, problem)
+			return
+		}
+
+		next(w, r, songID, patch)
 	}
 }
 
 // ValidateCreateSongInput validates the input for creating a new song.
 func ValidateCreateSongInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.CreateSongDto)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var createSongInput dto.CreateSongDto
+		createSongInput, problem, err := validateCreateSongInput(v, r.Body)
+		if err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(problem.Title)
+			delivery.RespondWithProblem(w, r, problem)
+			return
+		}
+
+		next(w, r, createSongInput)
+	}
+}
+
+// ValidateBatchInput validates every operation of a batch with the same rules as creating, updating and deleting a single song.
+// Atomic batches with invalid operations are rejected as a whole, while best-effort batches pass invalid operations on with their error.
+func ValidateBatchInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.BatchDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var batchInput dto.BatchInputDto
 
-		if err := json.NewDecoder(r.Body).Decode(&createSongInput); err != nil {
-			log.WithError(err).Error(delivery.ErrInvalidCreateSongInput)
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidCreateSongInput, Message: delivery.ErrInvalidJSON})
+		if err := json.NewDecoder(r.Body).Decode(&batchInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidBatchInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidBatchInput, delivery.ErrInvalidJSON, err))
 			return
 		}
 
-		trimSpace(&createSongInput)
+		if len(batchInput.Operations) == 0 || len(batchInput.Operations) > delivery.MaxBatchOperations {
+			logging.FromContext(r.Context()).Error(delivery.ErrInvalidBatchInput)
+			delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidBatchInput, delivery.MesInvalidBatchSize))
+			return
+		}
+
+		batch := dto.BatchDto{
+			Atomic:     batchInput.Atomic,
+			Operations: make([]dto.BatchOperationDto, len(batchInput.Operations)),
+		}
+
+		var invalidOperations []delivery.FieldError
+
+		for i, operationInput := range batchInput.Operations {
+			operation, problem := validateBatchOperation(v, operationInput)
+			if operation.Error != "" {
+				prefix := fmt.Sprintf("operations[%d]", i)
 
-		if err := v.Struct(createSongInput); err != nil {
-			log.WithError(err).Error(delivery.ErrInvalidCreateSongInput)
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidCreateSongInput, Message: delivery.MesInvalidCreateSongInput})
+				if len(problem.Errors) > 0 {
+					invalidOperations = append(invalidOperations, problem.WithFieldPrefix(prefix+".").Errors...)
+				} else {
+					invalidOperations = append(invalidOperations, delivery.FieldError{Field: prefix, Code: problem.Code, Message: problem.Detail})
+				}
+			}
+
+			batch.Operations[i] = operation
+		}
+
+		if batch.Atomic && len(invalidOperations) > 0 {
+			logging.FromContext(r.Context()).Error(fmt.Sprintf("%s (invalid operations: %d)", delivery.ErrInvalidBatchInput, len(invalidOperations)))
+			problem := delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidBatchInput, delivery.MesInvalidBatchOperations)
+			problem.Errors = invalidOperations
+			delivery.RespondWithProblem(w, r, problem)
 			return
 		}
 
-		next(w, r, createSongInput)
+		next(w, r, batch)
+	}
+}
+
+// ValidateImportSongsParam validates the format and conflict policy for importing songs.
+func ValidateImportSongsParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.ImportParamsDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		importParams := dto.ImportParamsDto{
+			Format:     r.URL.Query().Get("format"),
+			OnConflict: r.URL.Query().Get("on_conflict"),
+		}
+
+		if importParams.Format == "" {
+			importParams.Format = formatFromContentType(r.Header.Get("Content-Type"))
+		}
+
+		if importParams.OnConflict == "" {
+			importParams.OnConflict = delivery.DefaultOnConflict
+		}
+
+		if err := v.Struct(importParams); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidImportParam)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidImportParam, err))
+			return
+		}
+
+		next(w, r, importParams)
+	}
+}
+
+func validateBatchOperation(v *validator.Validate, operationInput dto.BatchOperationInputDto) (dto.BatchOperationDto, delivery.Problem) {
+	operation := dto.BatchOperationDto{
+		Op:     operationInput.Op,
+		SongID: operationInput.ID,
+	}
+
+	opType := domain.SongOperationType(operation.Op)
+
+	// An unknown operation is reported as such whatever its ID is.
+	if opType != domain.OpCreate && opType != domain.OpUpdate && opType != domain.OpDelete {
+		operation.Error = delivery.MesInvalidBatchOperation
+		return operation, delivery.NewProblem(http.StatusBadRequest, delivery.CodeInvalidBatchOperation, delivery.ErrInvalidBatchInput, delivery.MesInvalidBatchOperation)
+	}
+
+	if opType != domain.OpCreate && operation.SongID <= 0 {
+		operation.Error = delivery.MesInvalidIDInput
+		return operation, delivery.NewProblem(http.StatusBadRequest, delivery.CodeInvalidID, delivery.ErrInvalidIDInput, delivery.MesInvalidIDInput)
 	}
+
+	var problem delivery.Problem
+	var err error
+
+	switch opType {
+	case domain.OpCreate:
+		operation.Create, problem, err = validateCreateSongInput(v, bytes.NewReader(operationInput.Data))
+	case domain.OpUpdate:
+		operation.Update, problem, err = validateUpdateSongInput(v, bytes.NewReader(operationInput.Data))
+	}
+
+	if err != nil {
+		operation.Error = problem.Detail
+	}
+
+	return operation, problem
+}
+
+func validateMergePatch(v *validator.Validate, body io.Reader) (dto.SongPatchDto, delivery.Problem, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return dto.SongPatchDto{}, delivery.BodyProblem(delivery.ErrInvalidPatchSongInput, delivery.ErrReadingBody, err), err
+	}
+
+	var fields map[string]json.RawMessage
+	var patch dto.SongPatchDto
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return dto.SongPatchDto{}, delivery.BodyProblem(delivery.ErrInvalidPatchSongInput, delivery.ErrInvalidJSON, err), err
+	}
+
+	if err := json.Unmarshal(data, &patch.Set); err != nil {
+		return dto.SongPatchDto{}, delivery.BodyProblem(delivery.ErrInvalidPatchSongInput, delivery.ErrInvalidJSON, err), err
+	}
+
+	if len(fields) == 0 {
+		return dto.SongPatchDto{}, delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidPatchSongInput, delivery.MesEmptyUpdateSongInput), errors.New(delivery.MesEmptyUpdateSongInput)
+	}
+
+	clearableFields := map[string]bool{
+		"group":        false,
+		"song":         false,
+		"release_date": true,
+		"text":         true,
+		"link":         true,
+	}
+
+	for field, value := range fields {
+		clearable, ok := clearableFields[field]
+		if !ok || (!clearable && string(value) == "null") {
+			return dto.SongPatchDto{}, delivery.NewProblem(http.StatusBadRequest, delivery.CodeInvalidPatch, delivery.ErrInvalidPatchSongInput, delivery.MesInvalidMergePatch), fmt.Errorf("%s (field: %s)", delivery.MesInvalidMergePatch, field)
+		}
+
+		if string(value) == "null" {
+			patch.Clear = append(patch.Clear, field)
+		}
+	}
+
+	slices.Sort(patch.Clear)
+	trimSpace(&patch.Set)
+
+	if err := v.Struct(patch.Set); err != nil {
+		return dto.SongPatchDto{}, delivery.ValidationProblem(delivery.ErrInvalidPatchSongInput, err), err
+	}
+
+	return patch, delivery.Problem{}, nil
+}
+
+func validateJSONPatch(v *validator.Validate, body io.Reader) (dto.SongPatchDto, delivery.Problem, error) {
+	var patch dto.SongPatchDto
+
+	if err := json.NewDecoder(body).Decode(&patch.Operations); err != nil {
+		return dto.SongPatchDto{}, delivery.BodyProblem(delivery.ErrInvalidPatchSongInput, delivery.ErrInvalidJSON, err), err
+	}
+
+	if len(patch.Operations) == 0 {
+		return dto.SongPatchDto{}, delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidPatchSongInput, delivery.MesEmptyUpdateSongInput), errors.New(delivery.MesEmptyUpdateSongInput)
+	}
+
+	for i, operation := range patch.Operations {
+		if err := v.Struct(operation); err != nil {
+			return dto.SongPatchDto{}, delivery.ValidationProblem(delivery.ErrInvalidPatchSongInput, err).WithFieldPrefix(fmt.Sprintf("[%d].", i)), fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return patch, delivery.Problem{}, nil
+}
+
+func validateUpdateSongInput(v *validator.Validate, body io.Reader) (dto.SongParamsDto, delivery.Problem, error) {
+	var updateSongInput dto.SongParamsDto
+
+	if err := json.NewDecoder(body).Decode(&updateSongInput); err != nil {
+		return dto.SongParamsDto{}, delivery.BodyProblem(delivery.ErrInvalidUpdateSongInput, delivery.ErrInvalidJSON, err), err
+	}
+
+	if !isAnyFieldProvided(updateSongInput) {
+		return dto.SongParamsDto{}, delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidUpdateSongInput, delivery.MesEmptyUpdateSongInput), errors.New(delivery.MesEmptyUpdateSongInput)
+	}
+
+	trimSpace(&updateSongInput)
+
+	if err := v.Struct(updateSongInput); err != nil {
+		return dto.SongParamsDto{}, delivery.ValidationProblem(delivery.ErrInvalidUpdateSongInput, err), err
+	}
+
+	return updateSongInput, delivery.Problem{}, nil
+}
+
+func validateCreateSongInput(v *validator.Validate, body io.Reader) (dto.CreateSongDto, delivery.Problem, error) {
+	var createSongInput dto.CreateSongDto
+
+	if err := json.NewDecoder(body).Decode(&createSongInput); err != nil {
+		return dto.CreateSongDto{}, delivery.BodyProblem(delivery.ErrInvalidCreateSongInput, delivery.ErrInvalidJSON, err), err
+	}
+
+	trimSpace(&createSongInput)
+
+	if err := v.Struct(createSongInput); err != nil {
+		return dto.CreateSongDto{}, delivery.ValidationProblem(delivery.ErrInvalidCreateSongInput, err), err
+	}
+
+	return createSongInput, delivery.Problem{}, nil
 }
 
 func getPaginationParam(w http.ResponseWriter, r *http.Request, paramName string, defaultValue int) (int, error) {
@@ -158,8 +516,10 @@ func getPaginationParam(w http.ResponseWriter, r *http.Request, paramName string
 	if paramStr != "" {
 		paramValue, err := strconv.Atoi(paramStr)
 		if err != nil {
-			log.WithError(err).Error(delivery.ErrInvalidPaginationParam)
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidPaginationParam, Message: fmt.Sprintf("%s (param: %s, value: %s)", delivery.ErrParsingParam, paramName, paramStr)})
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidPaginationParam)
+			problem := delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidPaginationParam, fmt.Sprintf("%s (param: %s, value: %s)", delivery.ErrParsingParam, paramName, paramStr))
+			problem.Errors = []delivery.FieldError{{Field: paramName, Code: "integer", Message: delivery.MesNotInteger}}
+			delivery.RespondWithProblem(w, r, problem)
 			return 0, errors.New(delivery.ErrParsingParam)
 		}
 		return paramValue, nil
@@ -168,7 +528,43 @@ func getPaginationParam(w http.ResponseWriter, r *http.Request, paramName string
 	return defaultValue, nil
 }
 
-func getFilters(w http.ResponseWriter, r *http.Request) (dto.SongParamsDto, error) {
+func getBoolParam(w http.ResponseWriter, r *http.Request, paramName string) (bool, error) {
+	paramStr := r.URL.Query().Get(paramName)
+	if paramStr == "" {
+		return false, nil
+	}
+
+	param, err := strconv.ParseBool(paramStr)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidGetSongsParam)
+		problem := delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidGetSongsParam, fmt.Sprintf("%s (param: %s, value: %s)", delivery.ErrParsingBoolParam, paramName, paramStr))
+		problem.Errors = []delivery.FieldError{{Field: paramName, Code: "boolean", Message: delivery.MesNotBoolean}}
+		delivery.RespondWithProblem(w, r, problem)
+		return false, err
+	}
+
+	return param, nil
+}
+
+func getFloatParam(w http.ResponseWriter, r *http.Request, paramName string) (float64, error) {
+	paramStr := r.URL.Query().Get(paramName)
+	if paramStr == "" {
+		return 0, nil
+	}
+
+	param, err := strconv.ParseFloat(paramStr, 64)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidGetSongsParam)
+		problem := delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidGetSongsParam, fmt.Sprintf("%s (param: %s, value: %s)", delivery.ErrParsingFloatParam, paramName, paramStr))
+		problem.Errors = []delivery.FieldError{{Field: paramName, Code: "number", Message: delivery.MesNotNumber}}
+		delivery.RespondWithProblem(w, r, problem)
+		return 0, err
+	}
+
+	return param, nil
+}
+
+func getFilters(w http.ResponseWriter, r *http.Request, reservedParams ...string) (dto.SongParamsDto, error) {
 	validFilters := map[string]bool{
 		"group":        true,
 		"song":         true,
@@ -180,21 +576,25 @@ func getFilters(w http.ResponseWriter, r *http.Request) (dto.SongParamsDto, erro
 	var dtoFilters dto.SongParamsDto
 
 	for filter, values := range r.URL.Query() {
-		if filter == "page" || filter == "limit" {
+		if slices.Contains(reservedParams, filter) {
 			continue
 		}
 
 		if !validFilters[filter] {
-			log.Error(fmt.Sprintf("%s (filter name: %s)", delivery.ErrInvalidFilters, filter))
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidFilters, Message: delivery.MesInvalidFilterName})
+			logging.FromContext(r.Context()).Error(fmt.Sprintf("%s (filter name: %s)", delivery.ErrInvalidFilters, filter))
+			problem := delivery.NewProblem(http.StatusBadRequest, delivery.CodeInvalidFilter, delivery.ErrInvalidFilters, delivery.MesInvalidFilterName)
+			problem.Errors = []delivery.FieldError{{Field: filter, Code: "unknown", Message: delivery.MesUnknownFilter}}
+			delivery.RespondWithProblem(w, r, problem)
 			return dto.SongParamsDto{}, errors.New(delivery.ErrInvalidFilters)
 		}
 
 		value := values[0]
 
 		if value == "" {
-			log.Error(fmt.Sprintf("%s (filter name: %s)", delivery.ErrInvalidFilter, filter))
-			delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidFilter, Message: delivery.MesEmptyFilter})
+			logging.FromContext(r.Context()).Error(fmt.Sprintf("%s (filter name: %s)", delivery.ErrInvalidFilter, filter))
+			problem := delivery.NewProblem(http.StatusBadRequest, delivery.CodeInvalidFilter, delivery.ErrInvalidFilter, delivery.MesEmptyFilter)
+			problem.Errors = []delivery.FieldError{{Field: filter, Code: "required", Message: delivery.MesEmptyFilterValue}}
+			delivery.RespondWithProblem(w, r, problem)
 			return dto.SongParamsDto{}, errors.New(delivery.ErrInvalidFilter)
 		}
 
@@ -214,14 +614,30 @@ func extractAndValidateID(w http.ResponseWriter, r *http.Request) (int, error) {
 	songIDStr := chi.URLParam(r, "id")
 	songID, err := strconv.Atoi(songIDStr)
 	if err != nil || songID <= 0 {
-		log.WithError(err).Error(delivery.ErrInvalidIDInput)
-		delivery.RespondWithJSON(w, http.StatusBadRequest, delivery.JSONError{Error: delivery.ErrInvalidIDInput, Message: delivery.MesInvalidIDInput})
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidIDInput)
+		delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusBadRequest, delivery.CodeInvalidID, delivery.ErrInvalidIDInput, delivery.MesInvalidIDInput))
 		return 0, errors.New(delivery.MesInvalidIDInput)
 	}
 
 	return songID, nil
 }
 
+func formatFromContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return domain.FormatCSV
+	case "application/x-ndjson", "application/jsonl":
+		return domain.FormatNDJSON
+	default:
+		return ""
+	}
+}
+
 func isAnyFieldProvided(input dto.SongParamsDto) bool {
 	return input.Group != nil || input.Song != nil || input.ReleaseDate != nil || input.Text != nil || input.Link != nil
 }
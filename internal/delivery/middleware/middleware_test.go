@@ -0,0 +1,262 @@
+package middleware
+
+import (
+	"net/http"
+	"reflect"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/validator"
+	"strings"
+	"testing"
+)
+
+func ptr(value string) *string {
+	return &value
+}
+
+func TestValidateMergePatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		want       dto.SongPatchDto
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "sets trimmed fields",
+			body: `{"song":" Starlight ","release_date":"01.12.2003","link":"https://example.com"}`,
+			want: dto.SongPatchDto{Set: dto.SongParamsDto{Song: ptr("Starlight"), ReleaseDate: ptr("01.12.2003"), Link: ptr("https://example.com")}},
+		},
+		{
+			name: "null clears optional fields",
+			body: `{"text":null,"link":null,"group":"Muse"}`,
+			want: dto.SongPatchDto{Set: dto.SongParamsDto{Group: ptr("Muse")}, Clear: []string{"link", "text"}},
+		},
+		{
+			name:       "null can't clear the group",
+			body:       `{"group":null}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeInvalidPatch,
+		},
+		{
+			name:       "null can't clear the song",
+			body:       `{"song":null,"text":"Far away"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeInvalidPatch,
+		},
+		{
+			name:       "unknown field",
+			body:       `{"album":"Absolution"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeInvalidPatch,
+		},
+		{
+			name:       "empty patch",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeValidationFailed,
+		},
+		{
+			name:       "invalid date",
+			body:       `{"release_date":"2003-12-01"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeValidationFailed,
+		},
+		{
+			name:       "blank song",
+			body:       `{"song":"   "}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeValidationFailed,
+		},
+		{
+			name:       "value that isn't a string",
+			body:       `{"text":42}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeInvalidJSON,
+		},
+		{
+			name:       "not an object",
+			body:       `["song"]`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeInvalidJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, problem, err := validateMergePatch(validator.Init(), strings.NewReader(tt.body))
+
+			if tt.wantCode != "" {
+				if err == nil {
+					t.Fatalf("validateMergePatch() = %+v, want problem %s", patch, tt.wantCode)
+				}
+
+				if problem.Status != tt.wantStatus || problem.Code != tt.wantCode {
+					t.Errorf("problem = %d %s, want %d %s", problem.Status, problem.Code, tt.wantStatus, tt.wantCode)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("validateMergePatch() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(patch, tt.want) {
+				t.Errorf("validateMergePatch() = %+v, want %+v", patch, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateJSONPatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantOps    int
+		wantStatus int
+		wantCode   string
+		wantField  string
+	}{
+		{
+			name:    "valid operations",
+			body:    `[{"op":"replace","path":"/song","value":"Starlight"},{"op":"move","from":"/link","path":"/text"},{"op":"remove","path":"/release_date"}]`,
+			wantOps: 3,
+		},
+		{
+			name:       "empty patch",
+			body:       `[]`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeValidationFailed,
+		},
+		{
+			name:       "unknown operation",
+			body:       `[{"op":"replace","path":"/song","value":"Starlight"},{"op":"merge","path":"/song"}]`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeValidationFailed,
+			wantField:  "[1].op",
+		},
+		{
+			name:       "unknown path",
+			body:       `[{"op":"add","path":"/album","value":"Absolution"}]`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeValidationFailed,
+			wantField:  "[0].path",
+		},
+		{
+			name:       "move without from",
+			body:       `[{"op":"move","path":"/text"}]`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeValidationFailed,
+			wantField:  "[0].from",
+		},
+		{
+			name:       "not an array",
+			body:       `{"op":"remove","path":"/text"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   delivery.CodeInvalidJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, problem, err := validateJSONPatch(validator.Init(), strings.NewReader(tt.body))
+
+			if tt.wantCode != "" {
+				if err == nil {
+					t.Fatalf("validateJSONPatch() = %+v, want problem %s", patch, tt.wantCode)
+				}
+
+				if problem.Status != tt.wantStatus || problem.Code != tt.wantCode {
+					t.Errorf("problem = %d %s, want %d %s", problem.Status, problem.Code, tt.wantStatus, tt.wantCode)
+				}
+
+				if tt.wantField != "" && (len(problem.Errors) == 0 || problem.Errors[0].Field != tt.wantField) {
+					t.Errorf("problem errors = %+v, want field %s", problem.Errors, tt.wantField)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("validateJSONPatch() error = %v", err)
+			}
+
+			if len(patch.Operations) != tt.wantOps {
+				t.Errorf("validateJSONPatch() returned %d operations, want %d", len(patch.Operations), tt.wantOps)
+			}
+		})
+	}
+}
+
+func TestValidateBatchOperation(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     dto.BatchOperationInputDto
+		want      dto.BatchOperationDto
+		wantCode  string
+		wantError string
+	}{
+		{
+			name:  "create without an ID",
+			input: dto.BatchOperationInputDto{Op: "create", Data: []byte(`{"group":"Muse","song":"Hysteria"}`)},
+			want:  dto.BatchOperationDto{Op: "create", Create: dto.CreateSongDto{Group: "Muse", Song: "Hysteria"}},
+		},
+		{
+			name:  "update",
+			input: dto.BatchOperationInputDto{Op: "update", ID: 7, Data: []byte(`{"text":"Verse"}`)},
+			want:  dto.BatchOperationDto{Op: "update", SongID: 7, Update: dto.SongParamsDto{Text: ptr("Verse")}},
+		},
+		{
+			name:  "delete",
+			input: dto.BatchOperationInputDto{Op: "delete", ID: 7},
+			want:  dto.BatchOperationDto{Op: "delete", SongID: 7},
+		},
+		{
+			name:      "delete without an ID",
+			input:     dto.BatchOperationInputDto{Op: "delete"},
+			wantCode:  delivery.CodeInvalidID,
+			wantError: delivery.MesInvalidIDInput,
+		},
+		{
+			name:      "unknown operation with an ID",
+			input:     dto.BatchOperationInputDto{Op: "upsert", ID: 7},
+			wantCode:  delivery.CodeInvalidBatchOperation,
+			wantError: delivery.MesInvalidBatchOperation,
+		},
+		{
+			name:      "unknown operation without an ID",
+			input:     dto.BatchOperationInputDto{Op: "upsert"},
+			wantCode:  delivery.CodeInvalidBatchOperation,
+			wantError: delivery.MesInvalidBatchOperation,
+		},
+		{
+			name:     "invalid create data",
+			input:    dto.BatchOperationInputDto{Op: "create", Data: []byte(`{"group":"Muse"}`)},
+			wantCode: delivery.CodeValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, problem := validateBatchOperation(validator.Init(), tt.input)
+
+			if problem.Code != tt.wantCode {
+				t.Fatalf("problem code = %q, want %q", problem.Code, tt.wantCode)
+			}
+
+			if tt.wantCode != "" {
+				if operation.Error == "" {
+					t.Error("operation error is empty, want the operation marked as failed")
+				}
+
+				if tt.wantError != "" && operation.Error != tt.wantError {
+					t.Errorf("operation error = %q, want %q", operation.Error, tt.wantError)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(operation, tt.want) {
+				t.Errorf("validateBatchOperation() = %+v, want %+v", operation, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/logging"
+	"strconv"
+	"strings"
+)
+
+// ValidatePlaylistInput validates the name, description and visibility of a new playlist.
+func ValidatePlaylistInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.PlaylistInputDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistInput, ok := decodePlaylistInput(w, r, v)
+		if !ok {
+			return
+		}
+
+		next(w, r, playlistInput)
+	}
+}
+
+// ValidateReplacePlaylistInput validates the playlist ID and the full playlist representation for replacing a playlist.
+func ValidateReplacePlaylistInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.PlaylistInputDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID, ok := extractPositiveID(w, r, "id", delivery.ErrInvalidPlaylistIDInput)
+		if !ok {
+			return
+		}
+
+		playlistInput, ok := decodePlaylistInput(w, r, v)
+		if !ok {
+			return
+		}
+
+		next(w, r, playlistID, playlistInput)
+	}
+}
+
+// ValidateGetPlaylistParam validates the playlist ID and the pagination of its entries.
+func ValidateGetPlaylistParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.PaginationParamsDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID, ok := extractPositiveID(w, r, "id", delivery.ErrInvalidPlaylistIDInput)
+		if !ok {
+			return
+		}
+
+		ValidatePaginationParam(v, func(w http.ResponseWriter, r *http.Request, params dto.PaginationParamsDto) {
+			next(w, r, playlistID, params)
+		})(w, r)
+	}
+}
+
+// ValidatePlaylistIDInput validates the playlist ID in the URL.
+func ValidatePlaylistIDInput(next func(http.ResponseWriter, *http.Request, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID, ok := extractPositiveID(w, r, "id", delivery.ErrInvalidPlaylistIDInput)
+		if !ok {
+			return
+		}
+
+		next(w, r, playlistID)
+	}
+}
+
+// ValidateAddPlaylistEntryInput validates the playlist ID and the song and position of a new entry.
+func ValidateAddPlaylistEntryInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.AddPlaylistEntryDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID, ok := extractPositiveID(w, r, "id", delivery.ErrInvalidPlaylistIDInput)
+		if !ok {
+			return
+		}
+
+		var entryInput dto.AddPlaylistEntryDto
+
+		if err := json.NewDecoder(r.Body).Decode(&entryInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidAddEntryInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidAddEntryInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		if err := v.Struct(entryInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidAddEntryInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidAddEntryInput, err))
+			return
+		}
+
+		next(w, r, playlistID, entryInput)
+	}
+}
+
+// ValidatePlaylistEntryIDInput validates the playlist ID and the entry ID in the URL.
+func ValidatePlaylistEntryIDInput(next func(http.ResponseWriter, *http.Request, int, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID, ok := extractPositiveID(w, r, "id", delivery.ErrInvalidPlaylistIDInput)
+		if !ok {
+			return
+		}
+
+		entryID, ok := extractPositiveID(w, r, "entryID", delivery.ErrInvalidEntryIDInput)
+		if !ok {
+			return
+		}
+
+		next(w, r, playlistID, entryID)
+	}
+}
+
+// ValidateReorderPlaylistInput validates the playlist ID and the new order of its entries.
+func ValidateReorderPlaylistInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.ReorderPlaylistDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID, ok := extractPositiveID(w, r, "id", delivery.ErrInvalidPlaylistIDInput)
+		if !ok {
+			return
+		}
+
+		var reorderInput dto.ReorderPlaylistDto
+
+		if err := json.NewDecoder(r.Body).Decode(&reorderInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidReorderInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidReorderInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		if err := v.Struct(reorderInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidReorderInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidReorderInput, err))
+			return
+		}
+
+		next(w, r, playlistID, reorderInput)
+	}
+}
+
+func decodePlaylistInput(w http.ResponseWriter, r *http.Request, v *validator.Validate) (dto.PlaylistInputDto, bool) {
+	var playlistInput dto.PlaylistInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&playlistInput); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidPlaylistInput)
+		delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidPlaylistInput, delivery.ErrInvalidJSON, err))
+		return dto.PlaylistInputDto{}, false
+	}
+
+	playlistInput.Name = strings.TrimSpace(playlistInput.Name)
+	playlistInput.Description = strings.TrimSpace(playlistInput.Description)
+
+	if err := v.Struct(playlistInput); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidPlaylistInput)
+		delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidPlaylistInput, err))
+		return dto.PlaylistInputDto{}, false
+	}
+
+	return playlistInput, true
+}
+
+func extractPositiveID(w http.ResponseWriter, r *http.Request, paramName, title string) (int, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, paramName), 10, 32)
+	if err != nil || id <= 0 {
+		if err == nil {
+			err = errors.New(delivery.MesInvalidIDInput)
+		}
+
+		logging.FromContext(r.Context()).WithError(err).Error(title)
+		delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusBadRequest, delivery.CodeInvalidID, title, delivery.MesInvalidIDInput))
+		return 0, false
+	}
+
+	return int(id), true
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/logging"
+	"strings"
+	"time"
+)
+
+// ValidateRecordPlayInput validates the song ID and the optional source and time of a play. The body may be empty.
+func ValidateRecordPlayInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.RecordPlayDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		songID, err := extractAndValidateID(w, r)
+		if err != nil {
+			return
+		}
+
+		var playInput dto.RecordPlayDto
+
+		if err := json.NewDecoder(r.Body).Decode(&playInput); err != nil && !errors.Is(err, io.EOF) {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidRecordPlayInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidRecordPlayInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		playInput.Source = strings.TrimSpace(playInput.Source)
+
+		if err := v.Struct(playInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidRecordPlayInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidRecordPlayInput, err))
+			return
+		}
+
+		if playInput.PlayedAt.After(time.Now().Add(delivery.MaxPlayClockSkew)) {
+			logging.FromContext(r.Context()).Errorf("%s (played_at: %s)", delivery.ErrInvalidRecordPlayInput, playInput.PlayedAt)
+			problem := delivery.NewProblem(http.StatusBadRequest, delivery.CodeValidationFailed, delivery.ErrInvalidRecordPlayInput, "played_at "+delivery.MesPlayedAtInFuture)
+			problem.Errors = []delivery.FieldError{{Field: "played_at", Code: "future", Message: delivery.MesPlayedAtInFuture}}
+			delivery.RespondWithProblem(w, r, problem)
+			return
+		}
+
+		next(w, r, songID, playInput)
+	}
+}
+
+// ValidateChartParam validates the period and number of songs of a chart.
+func ValidateChartParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.ChartParamsDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, err := getPaginationParam(w, r, "limit", delivery.DefaultChartLimit)
+		if err != nil {
+			return
+		}
+
+		chartParams := dto.ChartParamsDto{
+			Period: r.URL.Query().Get("period"),
+			Limit:  limit,
+		}
+
+		if chartParams.Period == "" {
+			chartParams.Period = delivery.DefaultPeriod
+		}
+
+		if err := v.Struct(chartParams); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidChartParam)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidChartParam, err))
+			return
+		}
+
+		next(w, r, chartParams)
+	}
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"songs-library-go/internal/metrics"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimiter takes a token of a client for a request of a route, keyed by method and route pattern,
+// or a token of an IP address for a request of any route.
+type RateLimiter interface {
+	Allow(ctx context.Context, route, client string) (domain.RateLimitResult, error)
+	AllowIP(ctx context.Context, ip string) (domain.RateLimitResult, error)
+}
+
+// RateLimitIP limits requests per remote IP address before they are authenticated, so clients guessing credentials
+// are limited too. It must be used before Authenticate. The Swagger UI, the probes and the metrics aren't limited.
+func RateLimitIP(limiter RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, delivery.SwaggerPathPrefix) || isPublicPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip := "ip:" + remoteIP(r)
+
+			result, err := limiter.AllowIP(r.Context(), ip)
+			limitRequest(w, r, next, ip, result, err)
+		})
+	}
+}
+
+// RateLimit limits requests per authenticated principal with the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset
+// and RateLimit-Policy headers, and rejects requests over the limit with 429 and Retry-After.
+// It must be used after Authenticate. If the limiter fails, the request is let through.
+// The Swagger UI, the probes and the metrics aren't limited.
+func RateLimit(limiter RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := domain.PrincipalFromContext(r.Context())
+			if !ok || strings.HasPrefix(r.URL.Path, delivery.SwaggerPathPrefix) || isPublicPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			route, ok := routePattern(r)
+			if !ok {
+				route = metrics.UnmatchedRoute
+			}
+
+			client := principal.Identity()
+
+			result, err := limiter.Allow(r.Context(), r.Method+" "+route, client)
+			limitRequest(w, r, next, client, result, err)
+		})
+	}
+}
+
+// limitRequest sets the rate limit headers of result and passes the request to next if it is allowed or the limiter failed,
+// and rejects it with 429 otherwise.
+func limitRequest(w http.ResponseWriter, r *http.Request, next http.Handler, client string, result domain.RateLimitResult, err error) {
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrCheckingRateLimit)
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set(delivery.HeaderRateLimitLimit, strconv.Itoa(result.Limit.Requests))
+	w.Header().Set(delivery.HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+	w.Header().Set(delivery.HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+	w.Header().Set(delivery.HeaderRateLimitPolicy, strconv.Itoa(result.Limit.Requests)+";w="+strconv.Itoa(ceilSeconds(result.Limit.Period)))
+
+	if !result.Allowed {
+		retryAfter := max(ceilSeconds(result.RetryAfter), 1)
+
+		logging.FromContext(r.Context()).Errorf("%s (client: %s, limit: %s)", delivery.ErrRateLimited, client, result.Limit)
+		w.Header().Set(delivery.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusTooManyRequests, delivery.CodeRateLimited, delivery.ErrRateLimited, delivery.MesRateLimited))
+		return
+	}
+
+	next.ServeHTTP(w, r)
+}
+
+// remoteIP returns the IP address of the client that sent the request.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/domain"
+	"testing"
+	"time"
+)
+
+// stubRateLimiter returns a fixed result and records the key of the last request.
+type stubRateLimiter struct {
+	result domain.RateLimitResult
+	err    error
+	key    string
+}
+
+func (l *stubRateLimiter) Allow(_ context.Context, route, client string) (domain.RateLimitResult, error) {
+	l.key = route + "|" + client
+	return l.result, l.err
+}
+
+func (l *stubRateLimiter) AllowIP(_ context.Context, ip string) (domain.RateLimitResult, error) {
+	l.key = ip
+	return l.result, l.err
+}
+
+func TestRateLimitIP(t *testing.T) {
+	limit := domain.RateLimit{Requests: 10, Period: time.Minute}
+
+	tests := []struct {
+		name           string
+		path           string
+		result         domain.RateLimitResult
+		err            error
+		wantStatus     int
+		wantKey        string
+		wantHeaders    map[string]string
+		wantRetryAfter string
+	}{
+		{
+			name:       "allowed",
+			path:       "/songs",
+			result:     domain.RateLimitResult{Allowed: true, Limit: limit, Remaining: 9, Reset: 6 * time.Second},
+			wantStatus: http.StatusOK,
+			wantKey:    "ip:192.0.2.1",
+			wantHeaders: map[string]string{
+				delivery.HeaderRateLimitLimit:     "10",
+				delivery.HeaderRateLimitRemaining: "9",
+				delivery.HeaderRateLimitReset:     "6",
+				delivery.HeaderRateLimitPolicy:    "10;w=60",
+			},
+		},
+		{
+			name:           "over the limit",
+			path:           "/songs",
+			result:         domain.RateLimitResult{Limit: limit, Reset: time.Minute, RetryAfter: 5500 * time.Millisecond},
+			wantStatus:     http.StatusTooManyRequests,
+			wantKey:        "ip:192.0.2.1",
+			wantHeaders:    map[string]string{delivery.HeaderRateLimitRemaining: "0"},
+			wantRetryAfter: "6",
+		},
+		{
+			name:           "retry after is at least a second",
+			path:           "/songs",
+			result:         domain.RateLimitResult{Limit: limit, RetryAfter: 10 * time.Millisecond},
+			wantStatus:     http.StatusTooManyRequests,
+			wantKey:        "ip:192.0.2.1",
+			wantRetryAfter: "1",
+		},
+		{
+			name:       "limiter failure lets the request through",
+			path:       "/songs",
+			err:        errors.New("connection refused"),
+			wantStatus: http.StatusOK,
+			wantKey:    "ip:192.0.2.1",
+		},
+		{
+			name:       "probes aren't limited",
+			path:       delivery.ReadinessPath,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "swagger UI isn't limited",
+			path:       delivery.SwaggerPathPrefix + "index.html",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &stubRateLimiter{result: tt.result, err: tt.err}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.RemoteAddr = "192.0.2.1:54321"
+			w := httptest.NewRecorder()
+
+			RateLimitIP(limiter)(next).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if limiter.key != tt.wantKey {
+				t.Errorf("limited key = %q, want %q", limiter.key, tt.wantKey)
+			}
+
+			for header, want := range tt.wantHeaders {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+
+			if got := w.Header().Get(delivery.HeaderRetryAfter); got != tt.wantRetryAfter {
+				t.Errorf("%s = %q, want %q", delivery.HeaderRetryAfter, got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestRateLimitKeysPrincipalsOnTheirIdentity(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		wantKey   string
+	}{
+		{
+			name:      "API key by its ID",
+			principal: &domain.Principal{Type: domain.PrincipalAPIKey, Subject: "ci", KeyID: 7},
+			wantKey:   "GET unmatched|api_key#7",
+		},
+		{
+			name:      "unauthenticated request isn't limited per principal",
+			principal: nil,
+			wantKey:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &stubRateLimiter{result: domain.RateLimitResult{Allowed: true}}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+			r := httptest.NewRequest(http.MethodGet, "/songs", nil)
+			if tt.principal != nil {
+				r = r.WithContext(domain.WithPrincipal(r.Context(), *tt.principal))
+			}
+
+			RateLimit(limiter)(next).ServeHTTP(httptest.NewRecorder(), r)
+
+			if limiter.key != tt.wantKey {
+				t.Errorf("limited key = %q, want %q", limiter.key, tt.wantKey)
+			}
+		})
+	}
+}
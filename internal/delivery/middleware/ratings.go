@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"strings"
+)
+
+// ValidateRateSongInput validates the song ID and the rating and review of the song.
+func ValidateRateSongInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.RateSongDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		songID, err := extractAndValidateID(w, r)
+		if err != nil {
+			return
+		}
+
+		var rateInput dto.RateSongDto
+
+		if err := json.NewDecoder(r.Body).Decode(&rateInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidRateSongInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidRateSongInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		rateInput.Review = strings.TrimSpace(rateInput.Review)
+
+		if err := v.Struct(rateInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidRateSongInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidRateSongInput, err))
+			return
+		}
+
+		next(w, r, songID, rateInput)
+	}
+}
+
+// ValidateSongReviewsParam validates the song ID and the pagination of its reviews.
+func ValidateSongReviewsParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.PaginationParamsDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		songID, err := extractAndValidateID(w, r)
+		if err != nil {
+			return
+		}
+
+		ValidatePaginationParam(v, func(w http.ResponseWriter, r *http.Request, params dto.PaginationParamsDto) {
+			next(w, r, songID, params)
+		})(w, r)
+	}
+}
+
+// ValidateGetReviewsParam validates the moderation status and pagination of reviews. The status defaults to pending.
+func ValidateGetReviewsParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, dto.GetReviewsDto)) http.HandlerFunc {
+	return ValidatePaginationParam(v, func(w http.ResponseWriter, r *http.Request, params dto.PaginationParamsDto) {
+		getReviewsDto := dto.GetReviewsDto{
+			Status:           r.URL.Query().Get("status"),
+			PaginationParams: params,
+		}
+
+		if getReviewsDto.Status == "" {
+			getReviewsDto.Status = string(domain.ReviewPending)
+		}
+
+		if err := v.Struct(getReviewsDto); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidGetReviewsParam)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidGetReviewsParam, err))
+			return
+		}
+
+		next(w, r, getReviewsDto)
+	})
+}
+
+// ValidateModerateReviewInput validates the review ID and its new moderation status.
+func ValidateModerateReviewInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.ModerateReviewDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reviewID, ok := extractPositiveID(w, r, "id", delivery.ErrInvalidReviewIDInput)
+		if !ok {
+			return
+		}
+
+		var moderateInput dto.ModerateReviewDto
+
+		if err := json.NewDecoder(r.Body).Decode(&moderateInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidModerateInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidModerateInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		if err := v.Struct(moderateInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidModerateInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidModerateInput, err))
+			return
+		}
+
+		next(w, r, reviewID, moderateInput)
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/go-chi/cors"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/logging"
+	"songs-library-go/internal/metrics"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures which browser origins can call the API.
+type CORSOptions struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORS answers preflight requests and adds the CORS headers to responses for the allowed origins.
+// It must be used before Authenticate, because browsers send preflight requests without credentials.
+// The response headers of the API are exposed to scripts, so a frontend can read request IDs and rate limits.
+func CORS(options CORSOptions) func(http.Handler) http.Handler {
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   options.AllowedOrigins,
+		AllowedMethods:   options.AllowedMethods,
+		AllowedHeaders:   options.AllowedHeaders,
+		AllowCredentials: options.AllowCredentials,
+		MaxAge:           int(options.MaxAge.Seconds()),
+		ExposedHeaders: []string{
+			"Location",
+			delivery.HeaderRequestID,
+			delivery.HeaderIdempotentReplayed,
+			delivery.HeaderRateLimitLimit,
+			delivery.HeaderRateLimitRemaining,
+			delivery.HeaderRateLimitReset,
+			delivery.HeaderRateLimitPolicy,
+			delivery.HeaderRetryAfter,
+		},
+	})
+}
+
+// SecureHeaders adds headers that keep browsers from sniffing content types, framing the API or leaking URLs in referrers,
+// and Strict-Transport-Security to responses to requests made over HTTPS, directly or through a proxy.
+// The Swagger UI runs scripts and styles, so it doesn't get the Content-Security-Policy of the API.
+func SecureHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "no-referrer")
+		header.Set("Cross-Origin-Opener-Policy", "same-origin")
+
+		if !strings.HasPrefix(r.URL.Path, delivery.SwaggerPathPrefix) {
+			header.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+		}
+
+		if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+			header.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// BodyLimit limits the size of request bodies to the limit of the route, keyed by method and route pattern,
+// or to defaultLimit. Bodies declared larger in Content-Length are rejected with 413 up front, and reading past the limit
+// fails with http.MaxBytesError, which the validation middleware and the handlers turn into 413.
+func BodyLimit(defaultLimit int64, routeLimits map[string]int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := defaultLimit
+
+			route, ok := routePattern(r)
+			if !ok {
+				route = metrics.UnmatchedRoute
+			}
+
+			if routeLimit, ok := routeLimits[r.Method+" "+route]; ok {
+				limit = routeLimit
+			}
+
+			if r.ContentLength > limit {
+				logging.FromContext(r.Context()).Errorf("%s (content length: %d, limit: %d)", delivery.ErrBodyTooLarge, r.ContentLength, limit)
+				delivery.RespondWithProblem(w, r, delivery.NewProblem(http.StatusRequestEntityTooLarge, delivery.CodeBodyTooLarge, delivery.ErrBodyTooLarge, fmt.Sprintf(delivery.MesBodyTooLarge, limit)))
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/logging"
+)
+
+// ValidateSimilarSongsParam validates the song ID and the number of similar songs to retrieve.
+func ValidateSimilarSongsParam(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.SimilarSongsParamsDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		songID, err := extractAndValidateID(w, r)
+		if err != nil {
+			return
+		}
+
+		limit, err := getPaginationParam(w, r, "limit", delivery.DefaultSimilarLimit)
+		if err != nil {
+			return
+		}
+
+		similarParams := dto.SimilarSongsParamsDto{
+			Limit: limit,
+		}
+
+		if err := v.Struct(similarParams); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidSimilarSongsParam)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidSimilarSongsParam, err))
+			return
+		}
+
+		next(w, r, songID, similarParams)
+	}
+}
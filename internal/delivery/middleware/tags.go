@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"songs-library-go/internal/delivery"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/logging"
+	"strings"
+)
+
+// ValidateReplaceTagsInput validates the song ID and the tags of the song, which are trimmed and lowercased.
+func ValidateReplaceTagsInput(v *validator.Validate, next func(http.ResponseWriter, *http.Request, int, dto.SongTagsDto)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		songID, err := extractAndValidateID(w, r)
+		if err != nil {
+			return
+		}
+
+		var tagsInput dto.SongTagsDto
+
+		if err := json.NewDecoder(r.Body).Decode(&tagsInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidTagsInput)
+			delivery.RespondWithProblem(w, r, delivery.BodyProblem(delivery.ErrInvalidTagsInput, delivery.ErrInvalidJSON, err))
+			return
+		}
+
+		for i, tag := range tagsInput.Tags {
+			tagsInput.Tags[i] = strings.ToLower(strings.TrimSpace(tag))
+		}
+
+		if err := v.Struct(tagsInput); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Error(delivery.ErrInvalidTagsInput)
+			delivery.RespondWithProblem(w, r, delivery.ValidationProblem(delivery.ErrInvalidTagsInput, err))
+			return
+		}
+
+		next(w, r, songID, tagsInput)
+	}
+}
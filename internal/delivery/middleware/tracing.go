@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// Tracing starts a server span for every request, continuing the trace of the caller propagated in the traceparent header.
+// Spans are named after the method and chi route pattern, so they group by endpoint rather than by URL.
+// It must be used on the root router, which resolves the route pattern before routing the request.
+func Tracing(next http.Handler) http.Handler {
+	withRoute := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route, ok := routePattern(r); ok {
+			trace.SpanFromContext(r.Context()).SetAttributes(semconv.HTTPRoute(route))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+
+	return otelhttp.NewHandler(withRoute, "http.request",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			if route, ok := routePattern(r); ok {
+				return r.Method + " " + route
+			}
+
+			return r.Method
+		}),
+	)
+}
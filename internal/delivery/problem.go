@@ -0,0 +1,177 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"reflect"
+	"songs-library-go/internal/domain"
+	"strings"
+)
+
+const problemTypePrefix = "urn:songs-library:problem:"
+
+// domainProblems maps domain errors to the status and code of the problem returned for them.
+// When withDetail is set the full error is used as the detail, otherwise only the domain error message.
+var domainProblems = []struct {
+	err        error
+	status     int
+	code       string
+	withDetail bool
+}{
+	{domain.ErrSongNotFound, http.StatusNotFound, CodeSongNotFound, false},
+	{domain.ErrSongAlreadyExist, http.StatusBadRequest, CodeSongAlreadyExists, false},
+	{domain.ErrImportConflict, http.StatusConflict, CodeImportConflict, false},
+	{domain.ErrReadingImportFile, http.StatusBadRequest, CodeInvalidImportFile, true},
+	{domain.ErrUnsupportedImportFormat, http.StatusBadRequest, CodeInvalidImportFile, true},
+	{domain.ErrInvalidPatch, http.StatusBadRequest, CodeInvalidPatch, true},
+	{domain.ErrPatchTestFailed, http.StatusConflict, CodePatchTestFailed, true},
+	{domain.ErrInvalidBatchOperation, http.StatusBadRequest, CodeInvalidBatchOperation, true},
+	{domain.ErrBatchRolledBack, http.StatusConflict, CodeBatchAborted, false},
+	{domain.ErrBatchNotApplied, http.StatusConflict, CodeBatchAborted, false},
+	{domain.ErrMissingCredentials, http.StatusUnauthorized, CodeUnauthorized, false},
+	{domain.ErrInvalidCredentials, http.StatusUnauthorized, CodeUnauthorized, false},
+	{domain.ErrAPIKeyNotFound, http.StatusNotFound, CodeAPIKeyNotFound, false},
+	{domain.ErrPlaylistNotFound, http.StatusNotFound, CodePlaylistNotFound, false},
+	{domain.ErrPlaylistEntryNotFound, http.StatusNotFound, CodeEntryNotFound, false},
+	{domain.ErrInvalidPlaylistOrder, http.StatusConflict, CodeInvalidPlaylistOrder, false},
+	{domain.ErrInvalidPeriod, http.StatusBadRequest, CodeValidationFailed, true},
+	{domain.ErrPlaysBufferFull, http.StatusServiceUnavailable, CodePlaysBufferFull, false},
+	{domain.ErrRatingNotFound, http.StatusNotFound, CodeRatingNotFound, false},
+	{domain.ErrReviewNotFound, http.StatusNotFound, CodeReviewNotFound, false},
+	{domain.ErrAnnotationNotFound, http.StatusNotFound, CodeAnnotationNotFound, false},
+	{domain.ErrInvalidAnchor, http.StatusBadRequest, CodeInvalidAnchor, true},
+}
+
+// NewProblem creates a problem with the given status, code, title and detail.
+func NewProblem(status int, code, title, detail string) Problem {
+	return Problem{
+		Type:   problemTypePrefix + code,
+		Title:  title,
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// ErrorProblem creates a problem for an error returned by a service, mapping known domain errors to their status and code.
+// Unknown errors are reported as internal errors without exposing their details.
+func ErrorProblem(title string, err error) Problem {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return bodyTooLargeProblem(title, maxBytesErr)
+	}
+
+	for _, domainProblem := range domainProblems {
+		if errors.Is(err, domainProblem.err) {
+			detail := domainProblem.err.Error()
+			if domainProblem.withDetail {
+				detail = err.Error()
+			}
+
+			return NewProblem(domainProblem.status, domainProblem.code, title, detail)
+		}
+	}
+
+	return NewProblem(http.StatusInternalServerError, CodeInternalError, title, "")
+}
+
+// BodyProblem creates a problem for a request body that can't be read or decoded. A body over the size limit of the route
+// results in 413, and any other error in 400 with the detail.
+func BodyProblem(title, detail string, err error) Problem {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return bodyTooLargeProblem(title, maxBytesErr)
+	}
+
+	return NewProblem(http.StatusBadRequest, CodeInvalidJSON, title, detail)
+}
+
+func bodyTooLargeProblem(title string, err *http.MaxBytesError) Problem {
+	return NewProblem(http.StatusRequestEntityTooLarge, CodeBodyTooLarge, title, fmt.Sprintf(MesBodyTooLarge, err.Limit))
+}
+
+// ForbiddenProblem creates a problem for a principal whose role does not grant the permission.
+func ForbiddenProblem(principal domain.Principal, permission domain.Permission) Problem {
+	detail := fmt.Sprintf("role %q does not grant permission %q", principal.Role, permission)
+
+	problem := NewProblem(http.StatusForbidden, CodeForbidden, ErrForbidden, detail)
+	problem.MissingPermission = string(permission)
+
+	return problem
+}
+
+// ValidationProblem creates a bad request problem with per-field details extracted from validator.ValidationErrors.
+func ValidationProblem(title string, err error) Problem {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return NewProblem(http.StatusBadRequest, CodeValidationFailed, title, err.Error())
+	}
+
+	fieldErrors := make([]FieldError, len(validationErrors))
+	details := make([]string, len(validationErrors))
+
+	for i, fieldErr := range validationErrors {
+		fieldErrors[i] = FieldError{
+			Field:   fieldErr.Field(),
+			Code:    fieldErr.Tag(),
+			Message: fieldErrorMessage(fieldErr),
+		}
+		details[i] = fieldErrors[i].Field + " " + fieldErrors[i].Message
+	}
+
+	problem := NewProblem(http.StatusBadRequest, CodeValidationFailed, title, strings.Join(details, ", "))
+	problem.Errors = fieldErrors
+
+	return problem
+}
+
+// WithFieldPrefix returns a copy of the problem with the prefix prepended to the name of every invalid field.
+func (p Problem) WithFieldPrefix(prefix string) Problem {
+	fieldErrors := make([]FieldError, len(p.Errors))
+	for i, fieldErr := range p.Errors {
+		fieldErr.Field = prefix + fieldErr.Field
+		fieldErrors[i] = fieldErr
+	}
+	p.Errors = fieldErrors
+
+	return p
+}
+
+func fieldErrorMessage(fieldErr validator.FieldError) string {
+	isString := fieldErr.Kind() == reflect.String
+
+	switch fieldErr.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "min":
+		if isString {
+			return fmt.Sprintf("must have at least %s characters", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "max":
+		if isString {
+			return fmt.Sprintf("can have at most %s characters", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fieldErr.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fieldErr.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fieldErr.Param())
+	case "gtfield":
+		return fmt.Sprintf("must be greater than %s", strings.ToLower(fieldErr.Param()))
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.Join(strings.Fields(fieldErr.Param()), ", "))
+	case "url":
+		return "must be a valid URL"
+	case "customDate":
+		return "must be a valid date in the format dd.mm.yyyy"
+	case "period":
+		return "must be a number of days from 1d to 365d"
+	case "unique":
+		return "must not contain duplicates"
+	default:
+		return "is invalid"
+	}
+}
@@ -0,0 +1,165 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/validator"
+	"testing"
+)
+
+func TestErrorProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{
+			name:       "wrapped domain error without detail",
+			err:        fmt.Errorf("%w (id: 7)", domain.ErrSongNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   CodeSongNotFound,
+			wantDetail: domain.ErrSongNotFound.Error(),
+		},
+		{
+			name:       "wrapped domain error with detail",
+			err:        fmt.Errorf("%w: operation 2 (path: /song)", domain.ErrPatchTestFailed),
+			wantStatus: http.StatusConflict,
+			wantCode:   CodePatchTestFailed,
+			wantDetail: domain.ErrPatchTestFailed.Error() + ": operation 2 (path: /song)",
+		},
+		{
+			name:       "unknown error hides its details",
+			err:        errors.New("pq: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternalError,
+		},
+		{
+			name:       "body over the limit",
+			err:        fmt.Errorf("%w: %w", domain.ErrReadingImportFile, &http.MaxBytesError{Limit: 1024}),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   CodeBodyTooLarge,
+			wantDetail: fmt.Sprintf(MesBodyTooLarge, 1024),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := ErrorProblem("error doing it", tt.err)
+
+			want := Problem{
+				Type:   problemTypePrefix + tt.wantCode,
+				Title:  "error doing it",
+				Status: tt.wantStatus,
+				Detail: tt.wantDetail,
+				Code:   tt.wantCode,
+			}
+			if !reflect.DeepEqual(problem, want) {
+				t.Errorf("ErrorProblem() = %+v, want %+v", problem, want)
+			}
+		})
+	}
+}
+
+func TestDomainProblemsAreDistinct(t *testing.T) {
+	for i, domainProblem := range domainProblems {
+		for _, other := range domainProblems[i+1:] {
+			if errors.Is(other.err, domainProblem.err) {
+				t.Errorf("%v is shadowed by %v", other.err, domainProblem.err)
+			}
+		}
+
+		if domainProblem.status < http.StatusBadRequest || domainProblem.code == "" {
+			t.Errorf("%v maps to %d %q, want an error status and a code", domainProblem.err, domainProblem.status, domainProblem.code)
+		}
+	}
+}
+
+func TestBodyProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "invalid JSON", err: errors.New("unexpected EOF"), wantStatus: http.StatusBadRequest, wantCode: CodeInvalidJSON},
+		{name: "body over the limit", err: &http.MaxBytesError{Limit: 10}, wantStatus: http.StatusRequestEntityTooLarge, wantCode: CodeBodyTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := BodyProblem("invalid body", ErrInvalidJSON, tt.err)
+
+			if problem.Status != tt.wantStatus || problem.Code != tt.wantCode {
+				t.Errorf("BodyProblem() = %d %s, want %d %s", problem.Status, problem.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestValidationProblem(t *testing.T) {
+	type input struct {
+		Group string `json:"group" validate:"required,max=5"`
+		Limit int    `json:"limit" validate:"gte=1"`
+		Date  string `json:"release_date" validate:"omitempty,customDate"`
+	}
+
+	err := validator.Init().Struct(input{Group: "Rammstein", Date: "2019-05-17"})
+	problem := ValidationProblem("invalid input", err).WithFieldPrefix("[0].")
+
+	wantErrors := []FieldError{
+		{Field: "[0].group", Code: "max", Message: "can have at most 5 characters"},
+		{Field: "[0].limit", Code: "gte", Message: "must be greater than or equal to 1"},
+		{Field: "[0].release_date", Code: "customDate", Message: "must be a valid date in the format dd.mm.yyyy"},
+	}
+	if !reflect.DeepEqual(problem.Errors, wantErrors) {
+		t.Errorf("errors = %+v, want %+v", problem.Errors, wantErrors)
+	}
+
+	wantDetail := "group can have at most 5 characters, limit must be greater than or equal to 1, release_date must be a valid date in the format dd.mm.yyyy"
+	if problem.Status != http.StatusBadRequest || problem.Code != CodeValidationFailed || problem.Detail != wantDetail {
+		t.Errorf("ValidationProblem() = %d %s %q, want %d %s %q", problem.Status, problem.Code, problem.Detail, http.StatusBadRequest, CodeValidationFailed, wantDetail)
+	}
+
+	if other := ValidationProblem("invalid input", errors.New("period is invalid")); other.Detail != "period is invalid" || other.Errors != nil {
+		t.Errorf("ValidationProblem() of another error = %+v, want its message as the detail", other)
+	}
+}
+
+func TestForbiddenProblem(t *testing.T) {
+	problem := ForbiddenProblem(domain.Principal{Role: domain.RoleViewer}, domain.PermissionDeleteSongs)
+
+	if problem.Status != http.StatusForbidden || problem.Code != CodeForbidden || problem.MissingPermission != string(domain.PermissionDeleteSongs) {
+		t.Errorf("ForbiddenProblem() = %+v", problem)
+	}
+}
+
+func TestRespondWithProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodDelete, "/songs/7", nil)
+
+	RespondWithProblem(w, r, ErrorProblem("error deleting song", domain.ErrSongNotFound))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != ContentTypeProblem {
+		t.Errorf("Content-Type = %q, want %q", got, ContentTypeProblem)
+	}
+
+	var problem Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decoding problem: %v", err)
+	}
+
+	if problem.Instance != "/songs/7" || problem.Type != problemTypePrefix+CodeSongNotFound {
+		t.Errorf("problem = %+v, want instance /songs/7 and type of %s", problem, CodeSongNotFound)
+	}
+}
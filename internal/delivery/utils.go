@@ -2,6 +2,7 @@ package delivery
 
 import (
 	"encoding/json"
+	"github.com/go-chi/chi/v5/middleware"
 	"net/http"
 )
 
@@ -13,3 +14,15 @@ func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.WriteHeader(code)
 	w.Write(data)
 }
+
+// RespondWithProblem sends an RFC 7807 application/problem+json response, filling in the request path and request ID.
+func RespondWithProblem(w http.ResponseWriter, r *http.Request, problem Problem) {
+	problem.Instance = r.URL.Path
+	problem.RequestID = middleware.GetReqID(r.Context())
+
+	data, _ := json.Marshal(problem)
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(problem.Status)
+	w.Write(data)
+}
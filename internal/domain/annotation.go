@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// VerseSeparator separates the verses of a song text.
+const VerseSeparator = "\n\n"
+
+// AnchorUnit is the unit of the range an annotation is anchored to within a verse.
+type AnchorUnit string
+
+// Supported anchor units: lines of a verse or characters of a verse.
+const (
+	AnchorLines AnchorUnit = "line"
+	AnchorChars AnchorUnit = "char"
+)
+
+// Anchor ties an annotation to the half-open range [Start, End) of lines or characters of a verse,
+// counted from 0. Text is the anchored fragment, used to find the range again after the lyrics are edited.
+type Anchor struct {
+	VerseIndex int        `db:"verse_index"`
+	Unit       AnchorUnit `db:"anchor_unit"`
+	Start      int        `db:"anchor_start"`
+	End        int        `db:"anchor_end"`
+	Text       string     `db:"anchor_text"`
+}
+
+// Annotation represents an explanation of a part of the lyrics of a song written by a user.
+// TextHash identifies the song text the anchor was resolved against, and Stale is set when the anchored
+// fragment could not be found after the lyrics were edited.
+type Annotation struct {
+	ID     int32  `db:"id"`
+	SongID int32  `db:"song_id"`
+	UserID int32  `db:"user_id"`
+	Body   string `db:"body"`
+	Anchor
+	TextHash  string    `db:"text_hash"`
+	Stale     bool      `db:"is_stale"`
+	Score     int       `db:"score"`
+	MyVote    int       `db:"my_vote"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// SplitVerses splits a song text into its verses, as they are paginated by the song text endpoint.
+func SplitVerses(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, VerseSeparator)
+}
+
+// HashText returns the hash of a song text that annotation anchors are resolved against.
+func HashText(text string) string {
+	hash := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(hash[:])
+}
+
+// Fragment returns the part of the verses covered by the anchor, or false if the range is out of bounds.
+func (a Anchor) Fragment(verses []string) (string, bool) {
+	if a.VerseIndex < 0 || a.VerseIndex >= len(verses) || a.Start < 0 || a.Start >= a.End {
+		return "", false
+	}
+
+	verse := verses[a.VerseIndex]
+
+	switch a.Unit {
+	case AnchorLines:
+		lines := strings.Split(verse, "\n")
+		if a.End > len(lines) {
+			return "", false
+		}
+		return strings.Join(lines[a.Start:a.End], "\n"), true
+	case AnchorChars:
+		runes := []rune(verse)
+		if a.End > len(runes) {
+			return "", false
+		}
+		return string(runes[a.Start:a.End]), true
+	default:
+		return "", false
+	}
+}
+
+// Remap finds the anchored fragment in edited verses. The anchor is kept if its range still covers the fragment,
+// otherwise the fragment is searched in the verses closest to the original one first.
+// It returns false if the fragment no longer occurs in the lyrics.
+func (a Anchor) Remap(verses []string) (Anchor, bool) {
+	if fragment, ok := a.Fragment(verses); ok && fragment == a.Text {
+		return a, true
+	}
+
+	for _, index := range versesByDistance(a.VerseIndex, len(verses)) {
+		if start, found := a.find(verses[index]); found {
+			return Anchor{
+				VerseIndex: index,
+				Unit:       a.Unit,
+				Start:      start,
+				End:        start + a.End - a.Start,
+				Text:       a.Text,
+			}, true
+		}
+	}
+
+	return a, false
+}
+
+// find returns the start of the first occurrence of the anchored fragment in the verse, in the unit of the anchor.
+func (a Anchor) find(verse string) (int, bool) {
+	switch a.Unit {
+	case AnchorLines:
+		lines := strings.Split(verse, "\n")
+		fragmentLines := strings.Split(a.Text, "\n")
+
+		for start := 0; start+len(fragmentLines) <= len(lines); start++ {
+			if strings.Join(lines[start:start+len(fragmentLines)], "\n") == a.Text {
+				return start, true
+			}
+		}
+	case AnchorChars:
+		if byteIndex := strings.Index(verse, a.Text); byteIndex >= 0 {
+			return utf8.RuneCountInString(verse[:byteIndex]), true
+		}
+	}
+
+	return 0, false
+}
+
+// versesByDistance returns the indexes of count verses ordered by their distance from the verse at origin,
+// preceding verses first.
+func versesByDistance(origin, count int) []int {
+	indexes := make([]int, count)
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	slices.SortStableFunc(indexes, func(a, b int) int {
+		return cmp.Compare(max(a-origin, origin-a), max(b-origin, origin-b))
+	})
+
+	return indexes
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// PrincipalType describes how a principal has been authenticated.
+type PrincipalType string
+
+// Supported principal types.
+const (
+	PrincipalAPIKey PrincipalType = "api_key"
+	PrincipalJWT    PrincipalType = "jwt"
+)
+
+// Role is a named set of permissions granted to a principal.
+type Role string
+
+// Supported roles.
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// Permission allows a principal to perform a group of operations.
+type Permission string
+
+// Supported permissions.
+const (
+	PermissionReadSongs   Permission = "songs:read"
+	PermissionWriteSongs  Permission = "songs:write"
+	PermissionDeleteSongs Permission = "songs:delete"
+	PermissionManageKeys  Permission = "keys:manage"
+	PermissionFavorites   Permission = "favorites:manage"
+	PermissionPlaylists   Permission = "playlists:manage"
+	PermissionRecordPlays Permission = "plays:record"
+	PermissionRateSongs   Permission = "ratings:manage"
+	PermissionModerate    Permission = "reviews:moderate"
+	PermissionAnnotate    Permission = "annotations:manage"
+)
+
+// listenerPermissions are granted to every role: reading songs and managing own favorites, playlists, plays, ratings
+// and annotations.
+var listenerPermissions = []Permission{
+	PermissionReadSongs, PermissionFavorites, PermissionPlaylists, PermissionRecordPlays, PermissionRateSongs, PermissionAnnotate,
+}
+
+// RolePermissions maps every role to the permissions it grants: viewers only get the listener permissions,
+// editors may also create and update songs and moderate reviews, and admins may also delete songs and manage API keys.
+// Songs are deleted permanently, so songs:delete also stands for purging and there is no separate purge permission.
+var RolePermissions = map[Role][]Permission{
+	RoleViewer: listenerPermissions,
+	RoleEditor: append(slices.Clip(listenerPermissions), PermissionWriteSongs, PermissionModerate),
+	RoleAdmin:  append(slices.Clip(listenerPermissions), PermissionWriteSongs, PermissionModerate, PermissionDeleteSongs, PermissionManageKeys),
+}
+
+// IsValid reports whether the role is one of the supported roles.
+func (r Role) IsValid() bool {
+	_, ok := RolePermissions[r]
+	return ok
+}
+
+// Principal represents the authenticated caller of a request.
+// Subject is the API key name for API keys and the sub claim for JWT bearer tokens.
+// UserID identifies the user that owns personal data such as favorites.
+type Principal struct {
+	Type    PrincipalType
+	Subject string
+	KeyID   int32
+	UserID  int32
+	Role    Role
+}
+
+// Can reports whether the role of the principal grants the permission.
+func (p Principal) Can(permission Permission) bool {
+	for _, granted := range RolePermissions[p.Role] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the principal in the form type:subject, as written to the audit trail.
+func (p Principal) String() string {
+	return string(p.Type) + ":" + p.Subject
+}
+
+// Identity returns the immutable identity of the principal, which owns its user and personal data.
+// Names of API keys aren't unique and can be reused, so API keys are identified by their ID in the form api_key#id,
+// while JWT bearer tokens are identified by their sub claim in the form jwt:subject.
+func (p Principal) Identity() string {
+	if p.Type == PrincipalAPIKey {
+		return string(p.Type) + "#" + strconv.Itoa(int(p.KeyID))
+	}
+
+	return p.String()
+}
+
+// APIKey represents the data model for an API key. Only the SHA-256 hash of the key is stored.
+type APIKey struct {
+	ID        int32      `db:"id"`
+	Name      string     `db:"name"`
+	Prefix    string     `db:"prefix"`
+	Hash      string     `db:"key_hash"`
+	Role      Role       `db:"role"`
+	CreatedAt time.Time  `db:"created_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal stored in ctx, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
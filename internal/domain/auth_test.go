@@ -0,0 +1,70 @@
+package domain
+
+import "testing"
+
+func TestRolePermissions(t *testing.T) {
+	permissions := []Permission{
+		PermissionReadSongs, PermissionFavorites, PermissionPlaylists, PermissionRecordPlays, PermissionRateSongs, PermissionAnnotate,
+		PermissionWriteSongs, PermissionModerate, PermissionDeleteSongs, PermissionManageKeys,
+	}
+
+	// granted lists the permissions of every role, the ones not listed are denied.
+	granted := map[Role][]Permission{
+		RoleViewer: {
+			PermissionReadSongs, PermissionFavorites, PermissionPlaylists, PermissionRecordPlays, PermissionRateSongs, PermissionAnnotate,
+		},
+		RoleEditor: {
+			PermissionReadSongs, PermissionFavorites, PermissionPlaylists, PermissionRecordPlays, PermissionRateSongs, PermissionAnnotate,
+			PermissionWriteSongs, PermissionModerate,
+		},
+		RoleAdmin: permissions,
+		"owner":   nil,
+		"":        nil,
+	}
+
+	for role, rolePermissions := range granted {
+		for _, permission := range permissions {
+			want := false
+			for _, grantedPermission := range rolePermissions {
+				want = want || grantedPermission == permission
+			}
+
+			if got := (Principal{Role: role}).Can(permission); got != want {
+				t.Errorf("role %q can %s = %t, want %t", role, permission, got, want)
+			}
+		}
+	}
+}
+
+func TestRoleIsValid(t *testing.T) {
+	tests := []struct {
+		role Role
+		want bool
+	}{
+		{role: RoleViewer, want: true},
+		{role: RoleEditor, want: true},
+		{role: RoleAdmin, want: true},
+		{role: "owner"},
+		{role: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			if got := tt.role.IsValid(); got != tt.want {
+				t.Errorf("%q.IsValid() = %t, want %t", tt.role, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRolePermissionsDontShareBackingArrays(t *testing.T) {
+	// Roles extend the listener permissions, so appending to one role must not change another.
+	viewer := RolePermissions[RoleViewer]
+	if len(viewer) != cap(viewer) {
+		t.Errorf("viewer permissions have spare capacity %d, want none", cap(viewer)-len(viewer))
+	}
+
+	if (Principal{Role: RoleViewer}).Can(PermissionWriteSongs) {
+		t.Error("viewer can write songs granted to editors")
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+// SongOperationType defines the kind of change applied by a batch operation.
+type SongOperationType string
+
+// Supported batch operation types.
+const (
+	OpCreate SongOperationType = "create"
+	OpUpdate SongOperationType = "update"
+	OpDelete SongOperationType = "delete"
+)
+
+// SongOperation represents a single create, update or delete operation of a batch.
+type SongOperation struct {
+	Type   SongOperationType
+	SongID int32
+	Group  string
+	Song   string
+	Params map[string]interface{}
+}
+
+// SongOperationResult represents the outcome of a single batch operation.
+type SongOperationResult struct {
+	Song Song
+	Err  error
+}
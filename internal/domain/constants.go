@@ -1,8 +1,10 @@
 package domain
 
-// Constants for date format, unique constraint violation code, and success message for adding song details.
+// Constants for date format, Postgres error codes, and success message for adding song details.
 const (
 	DateFormat                    = "02.01.2006"
 	CodeUniqueConstraintViolation = "23505"
+	CodeForeignKeyViolation       = "23503"
+	CodeQueryCanceled             = "57014"
 	SuccessfulDetailAddition      = "details added successfully for song with id:"
 )
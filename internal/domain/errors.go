@@ -14,3 +14,73 @@ var (
 	ErrDetailsNotFound  = errors.New("details for song not found")
 	ErrAddingDetails    = errors.New("error adding song details in db")
 )
+
+// Error variables for importing songs.
+var (
+	ErrUnsupportedImportFormat = errors.New("unsupported import format")
+	ErrReadingImportFile       = errors.New("error reading import file")
+	ErrImportConflict          = errors.New("import aborted: song with this name by this group already exist")
+	ErrDuplicateImportRow      = errors.New("duplicate of an earlier row in the same import")
+	ErrInvalidImportRow        = errors.New("invalid import row")
+)
+
+// Error variables for batch operations.
+var (
+	ErrBatchAborted          = errors.New("batch aborted, no operations were applied")
+	ErrBatchRolledBack       = errors.New("operation rolled back because another operation of the batch failed")
+	ErrBatchNotApplied       = errors.New("operation not applied because a previous operation of the batch failed")
+	ErrInvalidBatchOperation = errors.New("invalid batch operation")
+)
+
+// Error variables for patching songs.
+var (
+	ErrInvalidPatch    = errors.New("invalid patch")
+	ErrPatchTestFailed = errors.New("patch test operation failed")
+)
+
+// Error variables for authentication.
+var (
+	ErrMissingCredentials = errors.New("missing API key or bearer token")
+	ErrInvalidCredentials = errors.New("invalid API key or bearer token")
+	ErrAPIKeyNotFound     = errors.New("API key with this id not found")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrInvalidRole        = errors.New("invalid role")
+)
+
+// Error variables for playlists.
+var (
+	ErrPlaylistNotFound      = errors.New("playlist with this id not found")
+	ErrPlaylistEntryNotFound = errors.New("playlist entry with this id not found")
+	ErrInvalidPlaylistOrder  = errors.New("entry ids must list every entry of the playlist exactly once")
+)
+
+// Error variables for plays.
+var (
+	ErrInvalidPeriod   = errors.New("period must be a number of days from 1d to 365d")
+	ErrPlaysBufferFull = errors.New("too many plays waiting to be recorded")
+	ErrRecordingPlays  = errors.New("error recording plays in db")
+)
+
+// Error variables for ratings and reviews.
+var (
+	ErrRatingNotFound = errors.New("rating of this song not found")
+	ErrReviewNotFound = errors.New("review with this id not found")
+)
+
+// Error variables for annotations.
+var (
+	ErrAnnotationNotFound = errors.New("annotation with this id not found")
+	ErrInvalidAnchor      = errors.New("anchor is outside of the song text")
+	ErrRemappingAnchors   = errors.New("error storing re-mapped annotation anchors in db")
+)
+
+// Error variables for health checks.
+var (
+	ErrMigrationsPending = errors.New("latest migration is not applied")
+	ErrProviderResponse  = errors.New("music info provider responded with server error")
+)
+
+// Error variables for rate limiting.
+var (
+	ErrInvalidRateLimit = errors.New("invalid rate limit")
+)
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// HealthStatus is the outcome of a health check or of all readiness checks.
+type HealthStatus string
+
+// Health statuses. HealthDegraded means that only optional dependencies are unavailable,
+// so the server is still ready to serve traffic.
+const (
+	HealthUp       HealthStatus = "up"
+	HealthDegraded HealthStatus = "degraded"
+	HealthDown     HealthStatus = "down"
+)
+
+// HealthCheck is the result of checking a dependency. A failing critical check makes the server not ready.
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Status   HealthStatus
+	Latency  time.Duration
+	Error    string
+}
+
+// HealthReport is the result of all readiness checks.
+type HealthReport struct {
+	Status HealthStatus
+	Checks []HealthCheck
+}
@@ -0,0 +1,16 @@
+package domain
+
+// IdempotencyRecord represents a stored Idempotency-Key together with the fingerprint of the request and its response.
+// Keys are scoped to the identity of the principal that sent the request, so callers can't see or reuse each other's keys.
+// Completed is false while the original request is still being processed.
+type IdempotencyRecord struct {
+	Principal   string `db:"principal"`
+	Key         string `db:"key"`
+	Method      string `db:"method"`
+	Path        string `db:"path"`
+	Fingerprint string `db:"fingerprint"`
+	StatusCode  int    `db:"status_code"`
+	ContentType string `db:"content_type"`
+	Body        string `db:"body"`
+	Completed   bool   `db:"completed"`
+}
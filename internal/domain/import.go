@@ -0,0 +1,52 @@
+package domain
+
+// ConflictPolicy defines how an import handles rows that violate the unique group/song constraint.
+type ConflictPolicy string
+
+// Supported conflict policies for importing songs.
+const (
+	ConflictSkip   ConflictPolicy = "skip"
+	ConflictUpdate ConflictPolicy = "update"
+	ConflictFail   ConflictPolicy = "fail"
+)
+
+// ImportStatus describes the outcome of importing a single row.
+type ImportStatus string
+
+// Possible outcomes of importing a single row.
+const (
+	ImportCreated    ImportStatus = "created"
+	ImportUpdated    ImportStatus = "updated"
+	ImportSkipped    ImportStatus = "skipped"
+	ImportConflict   ImportStatus = "conflict"
+	ImportInvalid    ImportStatus = "invalid"
+	ImportRolledBack ImportStatus = "rolled_back"
+)
+
+// ImportedSong represents the result of inserting a single song during an import.
+type ImportedSong struct {
+	Song   Song
+	Status ImportStatus
+}
+
+// ImportRowResult represents the outcome of importing a single row of an import file.
+type ImportRowResult struct {
+	Row    int
+	Group  string
+	Song   string
+	SongID int32
+	Status ImportStatus
+	Error  string
+}
+
+// ImportReport represents the outcome of a whole import.
+type ImportReport struct {
+	Rows []ImportRowResult
+}
+
+// Supported import and export file formats.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatJSON   = "json"
+)
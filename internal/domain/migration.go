@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// MigrationStatus represents a migration of the database schema and whether it is applied.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// MigrationResult represents a migration applied or rolled back, with the time it took.
+type MigrationResult struct {
+	Version   int64
+	Name      string
+	Direction string
+	Duration  time.Duration
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Play represents a single listen of a song by a user.
+type Play struct {
+	SongID   int32
+	UserID   int32
+	Source   string
+	PlayedAt time.Time
+}
+
+// ChartEntry represents a song at a rank of a chart together with its number of plays in the chart period.
+type ChartEntry struct {
+	Rank  int
+	Plays int64
+	Song  Song
+}
+
+// MaxPeriodDays is the longest period, in days, for which plays are counted.
+const MaxPeriodDays = 365
+
+// ParsePeriod parses a period of whole days such as "7d" and returns the number of days.
+func ParsePeriod(period string) (int, error) {
+	daysStr, found := strings.CutSuffix(period, "d")
+	if !found {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidPeriod, period)
+	}
+
+	days, err := strconv.Atoi(daysStr)
+	if err != nil || days < 1 || days > MaxPeriodDays {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidPeriod, period)
+	}
+
+	return days, nil
+}
+
+// PeriodStart returns the first UTC day of a period of days ending today.
+func PeriodStart(now time.Time, days int) time.Time {
+	today := now.UTC().Truncate(24 * time.Hour)
+	return today.AddDate(0, 0, 1-days)
+}
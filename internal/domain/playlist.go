@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Playlist represents the data model for a playlist owned by a user.
+type Playlist struct {
+	ID          int32     `db:"id"`
+	UserID      int32     `db:"user_id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	IsPublic    bool      `db:"is_public"`
+	SongsCount  int       `db:"songs_count"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// PlaylistEntry represents a song at a position in a playlist. Positions start at 1.
+type PlaylistEntry struct {
+	ID       int32
+	Position int
+	AddedAt  time.Time
+	Song     Song
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit allows Requests requests per Period. Requests are limited by a token bucket holding up to Requests tokens
+// and refilled at Requests tokens per Period, so a client can burst the whole limit and then continue at the average rate.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseRateLimit parses a rate limit written as requests/period, such as 100/1m.
+func ParseRateLimit(value string) (RateLimit, error) {
+	requests, period, found := strings.Cut(value, "/")
+	if !found {
+		return RateLimit{}, fmt.Errorf("%w: %s", ErrInvalidRateLimit, value)
+	}
+
+	limit := RateLimit{}
+	var err error
+
+	if limit.Requests, err = strconv.Atoi(strings.TrimSpace(requests)); err != nil || limit.Requests <= 0 {
+		return RateLimit{}, fmt.Errorf("%w: %s", ErrInvalidRateLimit, value)
+	}
+
+	if limit.Period, err = time.ParseDuration(strings.TrimSpace(period)); err != nil || limit.Period <= 0 {
+		return RateLimit{}, fmt.Errorf("%w: %s", ErrInvalidRateLimit, value)
+	}
+
+	return limit, nil
+}
+
+// String formats the rate limit the way ParseRateLimit parses it.
+func (l RateLimit) String() string {
+	return strconv.Itoa(l.Requests) + "/" + l.Period.String()
+}
+
+// TokenBucket is the state of the token bucket of a client. A bucket that doesn't exist yet is full.
+type TokenBucket struct {
+	Tokens    float64   `db:"tokens"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// RateLimitResult tells whether a request is allowed and how the bucket stands after it.
+// Reset is the time until the bucket is full again, and RetryAfter is the time until the next request is allowed.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      RateLimit
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Take refills the bucket for the time passed since it was updated and takes a token for a request at now if there is one.
+// It returns the updated bucket and the result of the request.
+func (b TokenBucket) Take(limit RateLimit, now time.Time) (TokenBucket, RateLimitResult) {
+	capacity := float64(limit.Requests)
+	perToken := limit.Period / time.Duration(limit.Requests)
+
+	tokens := capacity
+	if !b.UpdatedAt.IsZero() {
+		elapsed := max(now.Sub(b.UpdatedAt), 0)
+		tokens = min(capacity, b.Tokens+float64(elapsed)/float64(perToken))
+	}
+
+	result := RateLimitResult{Limit: limit}
+
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+	}
+
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = time.Duration((capacity - tokens) * float64(perToken))
+
+	return TokenBucket{Tokens: tokens, UpdatedAt: now}, result
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    RateLimit
+		wantErr bool
+	}{
+		{value: "100/1m", want: RateLimit{Requests: 100, Period: time.Minute}},
+		{value: " 5 / 30s ", want: RateLimit{Requests: 5, Period: 30 * time.Second}},
+		{value: "1/1h30m", want: RateLimit{Requests: 1, Period: 90 * time.Minute}},
+		{value: "", wantErr: true},
+		{value: "100", wantErr: true},
+		{value: "100/", wantErr: true},
+		{value: "/1m", wantErr: true},
+		{value: "0/1m", wantErr: true},
+		{value: "-1/1m", wantErr: true},
+		{value: "1.5/1m", wantErr: true},
+		{value: "100/0s", wantErr: true},
+		{value: "100/-1m", wantErr: true},
+		{value: "100/minute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRateLimit(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRateLimit) {
+					t.Errorf("ParseRateLimit(%q) = %v, %v, want %v", tt.value, got, err, ErrInvalidRateLimit)
+				}
+				return
+			}
+
+			if err != nil || got != tt.want {
+				t.Errorf("ParseRateLimit(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+			}
+
+			if parsed, err := ParseRateLimit(got.String()); err != nil || parsed != got {
+				t.Errorf("ParseRateLimit(%q) = %v, %v, want it to round-trip", got.String(), parsed, err)
+			}
+		})
+	}
+}
+
+func TestTokenBucketTake(t *testing.T) {
+	limit := RateLimit{Requests: 3, Period: 3 * time.Second}
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Every step takes a token from the bucket left by the previous step.
+	steps := []struct {
+		name           string
+		at             time.Duration
+		wantAllowed    bool
+		wantRemaining  int
+		wantReset      time.Duration
+		wantRetryAfter time.Duration
+	}{
+		{name: "missing bucket is full", at: 0, wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+		{name: "burst", at: 0, wantAllowed: true, wantRemaining: 1, wantReset: 2 * time.Second},
+		{name: "last token of the burst", at: 0, wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+		{name: "empty bucket", at: 0, wantAllowed: false, wantRemaining: 0, wantReset: 3 * time.Second, wantRetryAfter: time.Second},
+		{name: "partly refilled", at: 500 * time.Millisecond, wantAllowed: false, wantRemaining: 0, wantReset: 2500 * time.Millisecond, wantRetryAfter: 500 * time.Millisecond},
+		{name: "refilled one token", at: time.Second, wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+		{name: "clock going back refills nothing", at: 500 * time.Millisecond, wantAllowed: false, wantRemaining: 0, wantReset: 3 * time.Second, wantRetryAfter: time.Second},
+		{name: "refill is capped at the capacity", at: time.Hour, wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+	}
+
+	var bucket TokenBucket
+	for _, step := range steps {
+		var result RateLimitResult
+		bucket, result = bucket.Take(limit, start.Add(step.at))
+
+		want := RateLimitResult{
+			Allowed:    step.wantAllowed,
+			Limit:      limit,
+			Remaining:  step.wantRemaining,
+			Reset:      step.wantReset,
+			RetryAfter: step.wantRetryAfter,
+		}
+		if result != want {
+			t.Errorf("%s: Take() = %+v, want %+v", step.name, result, want)
+		}
+
+		if !bucket.UpdatedAt.Equal(start.Add(step.at)) {
+			t.Errorf("%s: bucket updated at %v, want %v", step.name, bucket.UpdatedAt, start.Add(step.at))
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// ReviewStatus is the moderation status of a review.
+type ReviewStatus string
+
+// Moderation statuses of reviews. Reviews are pending until a moderator approves or rejects them,
+// and only approved reviews are shown to other users.
+const (
+	ReviewPending  ReviewStatus = "pending"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewRejected ReviewStatus = "rejected"
+)
+
+// Rating represents the rating of a song by a user together with an optional review.
+type Rating struct {
+	ID           int32        `db:"id"`
+	UserID       int32        `db:"user_id"`
+	SongID       int32        `db:"song_id"`
+	Rating       int          `db:"rating"`
+	Review       string       `db:"review"`
+	ReviewStatus ReviewStatus `db:"review_status"`
+	CreatedAt    time.Time    `db:"created_at"`
+	UpdatedAt    time.Time    `db:"updated_at"`
+}
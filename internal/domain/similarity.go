@@ -0,0 +1,23 @@
+package domain
+
+// SimilarityWeights weigh the signals combined into the similarity of two songs.
+// Every signal is a number from 0 to 1, so with weights summing to 1 the similarity is from 0 to 1 as well.
+type SimilarityWeights struct {
+	Text   float64
+	Artist float64
+	Tags   float64
+	Year   float64
+}
+
+// Similarity is the precomputed similarity of a song to another song.
+type Similarity struct {
+	SongID        int32   `db:"song_id"`
+	SimilarSongID int32   `db:"similar_song_id"`
+	Score         float64 `db:"score"`
+}
+
+// SimilarSong represents a song similar to another song together with their similarity.
+type SimilarSong struct {
+	Score float64
+	Song  Song
+}
@@ -7,12 +7,30 @@ import (
 
 // Song represents the data model for a song.
 type Song struct {
-	ID          int32     `db:"id"`
-	Group       string    `db:"group"`
-	Song        string    `db:"song"`
-	ReleaseDate time.Time `db:"release_date"`
-	Text        string    `db:"text"`
-	Link        string    `db:"link"`
+	ID            int32     `db:"id"`
+	Group         string    `db:"group"`
+	Song          string    `db:"song"`
+	ReleaseDate   time.Time `db:"release_date"`
+	Text          string    `db:"text"`
+	Link          string    `db:"link"`
+	IsFavorite    bool      `db:"-"`
+	Plays         *int64    `db:"-"`
+	AverageRating *float64  `db:"-"`
+	RatingsCount  *int      `db:"-"`
+}
+
+// Orders of a list of songs: SortPopular orders songs by their number of plays in a period and SortRating
+// by their average rating, best first.
+const (
+	SortPopular = "popular"
+	SortRating  = "rating"
+)
+
+// SongsSort describes the order of a list of songs. An empty By keeps the default order.
+// Since is the first day of the period in which plays are counted for SortPopular.
+type SongsSort struct {
+	By    string
+	Since time.Time
 }
 
 // SongWithNull represents the data model for a song with nullable fields to handle optional details.
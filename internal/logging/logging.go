@@ -0,0 +1,71 @@
+// Package logging configures the logger and carries a request-scoped log entry in the context,
+// so every line logged while serving a request can be correlated by its request ID.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sync"
+)
+
+// Formats of log lines.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var errUnknownFormat = errors.New("unknown log format")
+
+type contextKey struct{}
+
+// requestLogger holds the entry of a request. Middleware deeper in the chain add fields to it, such as the principal,
+// and the fields are seen by the access log written by the outer middleware.
+type requestLogger struct {
+	mu    sync.Mutex
+	entry *log.Entry
+}
+
+// Configure sets the level and the format of the standard logger.
+func Configure(level log.Level, format string) error {
+	switch format {
+	case FormatText:
+		log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	case FormatJSON:
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return fmt.Errorf("%w: %s", errUnknownFormat, format)
+	}
+
+	log.SetLevel(level)
+
+	return nil
+}
+
+// NewContext returns a copy of ctx carrying entry as the logger of the request.
+func NewContext(ctx context.Context, entry *log.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestLogger{entry: entry})
+}
+
+// FromContext returns the logger of the request in ctx, or an entry of the standard logger if there is none.
+func FromContext(ctx context.Context) *log.Entry {
+	if logger, ok := ctx.Value(contextKey{}).(*requestLogger); ok {
+		logger.mu.Lock()
+		defer logger.mu.Unlock()
+
+		return logger.entry
+	}
+
+	return log.NewEntry(log.StandardLogger())
+}
+
+// AddFields adds fields to the logger of the request in ctx. It does nothing if ctx carries no logger.
+func AddFields(ctx context.Context, fields log.Fields) {
+	if logger, ok := ctx.Value(contextKey{}).(*requestLogger); ok {
+		logger.mu.Lock()
+		defer logger.mu.Unlock()
+
+		logger.entry = logger.entry.WithFields(fields)
+	}
+}
@@ -0,0 +1,117 @@
+// Package metrics defines the Prometheus metrics of the server and the helpers recording them.
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"time"
+)
+
+const namespace = "songs_library"
+
+// Route label of requests that match no route, so that unknown paths can't blow up the number of series.
+const UnmatchedRoute = "unmatched"
+
+// Outcomes of enrichment jobs.
+const (
+	EnrichmentSuccess       = "success"
+	EnrichmentRequestError  = "request_error"
+	EnrichmentResponseError = "response_error"
+	EnrichmentDecodingError = "decoding_error"
+	EnrichmentNotFound      = "details_not_found"
+	EnrichmentDBError       = "db_error"
+	EnrichmentSkipped       = "skipped_at_shutdown"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by method, chi route pattern and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, chi route pattern and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests being served by method and chi route pattern.",
+	}, []string{"method", "route"})
+
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of repository methods, including all their queries.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method"})
+
+	enrichmentJobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "enrichment",
+		Name:      "jobs_total",
+		Help:      "Number of song details enrichment jobs by outcome.",
+	}, []string{"outcome"})
+
+	externalAPIDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "external_api",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests to external APIs by API and status, which is error if no response was received.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"api", "status"})
+)
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterDB exposes the connection pool statistics of the database.
+func RegisterDB(db *sql.DB, dbName string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// StartRequest counts a request as in flight and returns the function recording it when it's served.
+func StartRequest(method, route string) func(status string) {
+	start := time.Now()
+	httpInFlight.WithLabelValues(method, route).Inc()
+
+	return func(status string) {
+		httpInFlight.WithLabelValues(method, route).Dec()
+		httpRequests.WithLabelValues(method, route, status).Inc()
+		httpDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveQuery starts timing a repository method and returns the function recording its duration,
+// meant to be deferred: defer metrics.ObserveQuery("SongsRepo.GetSongs")().
+func ObserveQuery(method string) func() {
+	start := time.Now()
+
+	return func() {
+		queryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveEnrichment counts an enrichment job with its outcome.
+func ObserveEnrichment(outcome string) {
+	enrichmentJobs.WithLabelValues(outcome).Inc()
+}
+
+// ObserveExternalAPI records the latency of a request to an external API.
+func ObserveExternalAPI(api, status string, duration time.Duration) {
+	externalAPIDuration.WithLabelValues(api, status).Observe(duration.Seconds())
+}
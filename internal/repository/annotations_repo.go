@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/lib/pq"
+	"songs-library-go/internal/domain"
+)
+
+const (
+	annotationsTable     = "annotations"
+	annotationVotesTable = "annotation_votes"
+)
+
+// AnnotationsRepo implements the AnnotationsRepo interface for interacting with the database using goqu.
+// Annotations of a song are removed by the database when the song is deleted.
+type AnnotationsRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewAnnotationsRepo creates a new instance of AnnotationsRepo, initializing it with a goqu.Database.
+func NewAnnotationsRepo(db *sql.DB) *AnnotationsRepo {
+	return &AnnotationsRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// Create adds a new annotation and returns it.
+func (r AnnotationsRepo) Create(ctx context.Context, annotation domain.Annotation) (_ domain.Annotation, err error) {
+	ctx, end := startSpan(ctx, "AnnotationsRepo.Create")
+	defer end(&err)
+
+	insert := r.goquDb.Insert(annotationsTable).
+		Rows(goqu.Record{
+			"song_id":      annotation.SongID,
+			"user_id":      annotation.UserID,
+			"body":         annotation.Body,
+			"verse_index":  annotation.VerseIndex,
+			"anchor_unit":  annotation.Unit,
+			"anchor_start": annotation.Start,
+			"anchor_end":   annotation.End,
+			"anchor_text":  annotation.Text,
+			"text_hash":    annotation.TextHash,
+		}).
+		Returning(goqu.Star(), goqu.L("0").As("score"), goqu.L("0").As("my_vote"))
+
+	var created domain.Annotation
+	if _, err := insert.Executor().ScanStructContext(ctx, &created); err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) && pgErr.Code == domain.CodeForeignKeyViolation {
+			return domain.Annotation{}, fmt.Errorf("%w (id: %d)", domain.ErrSongNotFound, annotation.SongID)
+		}
+		return domain.Annotation{}, err
+	}
+
+	return created, nil
+}
+
+// GetAnnotations retrieves all annotations of a song in lyrics order with their vote score and the vote of the user.
+func (r AnnotationsRepo) GetAnnotations(ctx context.Context, songID, userID int32) (_ []domain.Annotation, err error) {
+	ctx, end := startSpan(ctx, "AnnotationsRepo.GetAnnotations")
+	defer end(&err)
+
+	score := r.goquDb.From(annotationVotesTable).
+		Select(goqu.COALESCE(goqu.SUM("value"), 0)).
+		Where(goqu.I(annotationVotesTable + ".annotation_id").Eq(goqu.I(annotationsTable + ".id")))
+
+	myVote := r.goquDb.From(annotationVotesTable).
+		Select("value").
+		Where(goqu.I(annotationVotesTable+".annotation_id").Eq(goqu.I(annotationsTable+".id")), goqu.Ex{annotationVotesTable + ".user_id": userID})
+
+	query := r.goquDb.From(annotationsTable).
+		Select(goqu.Star(), goqu.L("(?)", score).As("score"), goqu.L("COALESCE((?), 0)", myVote).As("my_vote")).
+		Where(goqu.Ex{"song_id": songID}).
+		Order(goqu.C("verse_index").Asc(), goqu.C("anchor_start").Asc(), goqu.C("id").Asc())
+
+	var annotations []domain.Annotation
+	if err := query.Executor().ScanStructsContext(ctx, &annotations); err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+// UpdateAnchors stores re-mapped anchors and stale flags of annotations. An annotation is only updated if its anchor
+// was still resolved against the text with previousHash, so concurrent re-mapping doesn't overwrite a newer anchor.
+func (r AnnotationsRepo) UpdateAnchors(ctx context.Context, annotations []domain.Annotation, previousHashes []string) (err error) {
+	ctx, end := startSpan(ctx, "AnnotationsRepo.UpdateAnchors")
+	defer end(&err)
+
+	return withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		for i, annotation := range annotations {
+			update := tx.Update(annotationsTable).
+				Set(goqu.Record{
+					"verse_index":  annotation.VerseIndex,
+					"anchor_start": annotation.Start,
+					"anchor_end":   annotation.End,
+					"text_hash":    annotation.TextHash,
+					"is_stale":     annotation.Stale,
+				}).
+				Where(goqu.Ex{"id": annotation.ID, "text_hash": previousHashes[i]})
+
+			if _, err := update.Executor().ExecContext(ctx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Vote sets the vote of the user on an annotation, where a value of 0 withdraws the vote, and returns the new score.
+func (r AnnotationsRepo) Vote(ctx context.Context, annotationID, userID int32, value int) (_ int, err error) {
+	ctx, end := startSpan(ctx, "AnnotationsRepo.Vote")
+	defer end(&err)
+
+	var id int32
+	found, err := r.goquDb.From(annotationsTable).Select("id").Where(goqu.Ex{"id": annotationID}).ScanValContext(ctx, &id)
+	if err != nil {
+		return 0, err
+	}
+
+	if !found {
+		return 0, fmt.Errorf("%w (id: %d)", domain.ErrAnnotationNotFound, annotationID)
+	}
+
+	if value == 0 {
+		_, err = r.goquDb.Delete(annotationVotesTable).Where(goqu.Ex{"annotation_id": annotationID, "user_id": userID}).Executor().ExecContext(ctx)
+	} else {
+		_, err = r.goquDb.Insert(annotationVotesTable).
+			Rows(goqu.Record{"annotation_id": annotationID, "user_id": userID, "value": value}).
+			OnConflict(goqu.DoUpdate("annotation_id, user_id", goqu.Record{"value": goqu.I("excluded.value")})).
+			Executor().ExecContext(ctx)
+	}
+
+	if err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) && pgErr.Code == domain.CodeForeignKeyViolation {
+			return 0, fmt.Errorf("%w (id: %d)", domain.ErrAnnotationNotFound, annotationID)
+		}
+		return 0, err
+	}
+
+	var score int
+	query := r.goquDb.From(annotationVotesTable).Select(goqu.COALESCE(goqu.SUM("value"), 0)).Where(goqu.Ex{"annotation_id": annotationID})
+	if _, err := query.ScanValContext(ctx, &score); err != nil {
+		return 0, err
+	}
+
+	return score, nil
+}
+
+// Delete removes an annotation written by the user together with its votes.
+func (r AnnotationsRepo) Delete(ctx context.Context, annotationID, userID int32) (err error) {
+	ctx, end := startSpan(ctx, "AnnotationsRepo.Delete")
+	defer end(&err)
+
+	result, err := r.goquDb.Delete(annotationsTable).Where(goqu.Ex{"id": annotationID, "user_id": userID}).Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w (id: %d)", domain.ErrAnnotationNotFound, annotationID)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/doug-martin/goqu/v9"
+	"songs-library-go/internal/domain"
+)
+
+const apiKeysTable = "api_keys"
+
+// APIKeysRepo stores hashed API keys.
+type APIKeysRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewAPIKeysRepo creates a new instance of APIKeysRepo, initializing it with a goqu.Database.
+func NewAPIKeysRepo(db *sql.DB) *APIKeysRepo {
+	return &APIKeysRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// Create inserts a new API key and returns it with its ID and creation time.
+func (r APIKeysRepo) Create(ctx context.Context, key domain.APIKey) (_ domain.APIKey, err error) {
+	ctx, end := startSpan(ctx, "APIKeysRepo.Create")
+	defer end(&err)
+
+	insert := r.goquDb.Insert(apiKeysTable).
+		Rows(goqu.Record{
+			"name":     key.Name,
+			"prefix":   key.Prefix,
+			"key_hash": key.Hash,
+			"role":     string(key.Role),
+		}).
+		Returning("id", "name", "prefix", "key_hash", "role", "created_at", "revoked_at")
+
+	var created domain.APIKey
+	if _, err := insert.Executor().ScanStructContext(ctx, &created); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	return created, nil
+}
+
+// GetByHash returns the API key with the given hash, including revoked keys.
+func (r APIKeysRepo) GetByHash(ctx context.Context, hash string) (_ domain.APIKey, err error) {
+	ctx, end := startSpan(ctx, "APIKeysRepo.GetByHash")
+	defer end(&err)
+
+	query := r.goquDb.From(apiKeysTable).
+		Select("id", "name", "prefix", "key_hash", "role", "created_at", "revoked_at").
+		Where(goqu.Ex{"key_hash": hash})
+
+	var key domain.APIKey
+	found, err := query.Executor().ScanStructContext(ctx, &key)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	if !found {
+		return domain.APIKey{}, domain.ErrAPIKeyNotFound
+	}
+
+	return key, nil
+}
+
+// List returns all API keys ordered by ID.
+func (r APIKeysRepo) List(ctx context.Context) (_ []domain.APIKey, err error) {
+	ctx, end := startSpan(ctx, "APIKeysRepo.List")
+	defer end(&err)
+
+	query := r.goquDb.From(apiKeysTable).
+		Select("id", "name", "prefix", "key_hash", "role", "created_at", "revoked_at").
+		Order(goqu.C("id").Asc())
+
+	var keys []domain.APIKey
+	if err := query.Executor().ScanStructsContext(ctx, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Revoke marks the API key as revoked. Revoking an already revoked key is not an error.
+func (r APIKeysRepo) Revoke(ctx context.Context, keyID int32) (err error) {
+	ctx, end := startSpan(ctx, "APIKeysRepo.Revoke")
+	defer end(&err)
+
+	update := r.goquDb.Update(apiKeysTable).
+		Set(goqu.Record{"revoked_at": goqu.COALESCE(goqu.C("revoked_at"), goqu.L("now()"))}).
+		Where(goqu.Ex{"id": keyID})
+
+	result, err := update.Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// HealthRepo implements the HealthRepo interface for checking the database.
+type HealthRepo struct {
+	db       *sql.DB
+	migrator *Migrator
+}
+
+// NewHealthRepo creates a new instance of HealthRepo checking the migrations of the migrator.
+func NewHealthRepo(db *sql.DB, migrator *Migrator) *HealthRepo {
+	return &HealthRepo{
+		db:       db,
+		migrator: migrator,
+	}
+}
+
+// Ping checks that the database is reachable.
+func (r HealthRepo) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// CheckMigrations returns domain.ErrMigrationsPending if a migration embedded in the binary is not applied to the database.
+func (r HealthRepo) CheckMigrations(ctx context.Context) error {
+	return r.migrator.CheckPending(ctx)
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/doug-martin/goqu/v9"
+	log "github.com/sirupsen/logrus"
+	"songs-library-go/internal/domain"
+	"time"
+)
+
+const idempotencyKeysTable = "idempotency_keys"
+
+const (
+	errPurgingIdempotencyKeys = "error purging expired idempotency keys"
+	idempotencyPurgeInterval  = time.Hour
+)
+
+// IdempotencyRepo stores Idempotency-Key records and the responses they replay.
+type IdempotencyRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewIdempotencyRepo creates a new instance of IdempotencyRepo, initializing it with a goqu.Database.
+func NewIdempotencyRepo(db *sql.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// Reserve stores a new in-progress record for the key of the principal unless a record that is younger than ttl already exists.
+// It returns true if the key has been reserved, otherwise it returns the existing record.
+func (r IdempotencyRepo) Reserve(ctx context.Context, record domain.IdempotencyRecord, ttl time.Duration) (_ domain.IdempotencyRecord, _ bool, err error) {
+	ctx, end := startSpan(ctx, "IdempotencyRepo.Reserve")
+	defer end(&err)
+
+	insert := r.goquDb.Insert(idempotencyKeysTable).
+		Rows(goqu.Record{
+			"principal":   record.Principal,
+			"key":         record.Key,
+			"method":      record.Method,
+			"path":        record.Path,
+			"fingerprint": record.Fingerprint,
+		}).
+		OnConflict(goqu.DoUpdate("principal, key", goqu.Record{
+			"method":       goqu.L("EXCLUDED.method"),
+			"path":         goqu.L("EXCLUDED.path"),
+			"fingerprint":  goqu.L("EXCLUDED.fingerprint"),
+			"status_code":  nil,
+			"content_type": nil,
+			"body":         nil,
+			"created_at":   goqu.L("now()"),
+		}).Where(goqu.I(idempotencyKeysTable + ".created_at").Lt(time.Now().Add(-ttl)))).
+		Returning("key")
+
+	var key string
+	reserved, err := insert.Executor().ScanValContext(ctx, &key)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	if reserved {
+		return domain.IdempotencyRecord{}, true, nil
+	}
+
+	query := r.goquDb.From(idempotencyKeysTable).
+		Select(
+			"principal", "key", "method", "path", "fingerprint",
+			goqu.COALESCE(goqu.C("status_code"), 0).As("status_code"),
+			goqu.COALESCE(goqu.C("content_type"), "").As("content_type"),
+			goqu.COALESCE(goqu.C("body"), "").As("body"),
+			goqu.L("status_code IS NOT NULL").As("completed"),
+		).
+		Where(goqu.Ex{"principal": record.Principal, "key": record.Key})
+
+	var existing domain.IdempotencyRecord
+	found, err := query.Executor().ScanStructContext(ctx, &existing)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	if !found {
+		// The record expired and was purged between the two queries, so try again.
+		return r.Reserve(ctx, record, ttl)
+	}
+
+	return existing, false, nil
+}
+
+// Complete stores the response of the request that reserved the key.
+func (r IdempotencyRepo) Complete(ctx context.Context, record domain.IdempotencyRecord) (err error) {
+	ctx, end := startSpan(ctx, "IdempotencyRepo.Complete")
+	defer end(&err)
+
+	update := r.goquDb.Update(idempotencyKeysTable).
+		Set(goqu.Record{
+			"status_code":  record.StatusCode,
+			"content_type": record.ContentType,
+			"body":         record.Body,
+		}).
+		Where(goqu.Ex{"principal": record.Principal, "key": record.Key})
+
+	_, err = update.Executor().ExecContext(ctx)
+	return err
+}
+
+// Release removes the record so that the request can be retried.
+func (r IdempotencyRepo) Release(ctx context.Context, record domain.IdempotencyRecord) (err error) {
+	ctx, end := startSpan(ctx, "IdempotencyRepo.Release")
+	defer end(&err)
+
+	de := r.goquDb.Delete(idempotencyKeysTable).Where(goqu.Ex{"principal": record.Principal, "key": record.Key})
+
+	_, err = de.Executor().ExecContext(ctx)
+	return err
+}
+
+// PurgeExpired periodically removes records that are older than ttl until ctx is done.
+func (r IdempotencyRepo) PurgeExpired(ctx context.Context, ttl time.Duration) {
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.purgeExpired(ctx, ttl); err != nil {
+				log.WithError(err).Error(errPurgingIdempotencyKeys)
+			}
+		}
+	}
+}
+
+func (r IdempotencyRepo) purgeExpired(ctx context.Context, ttl time.Duration) (err error) {
+	ctx, end := startSpan(ctx, "IdempotencyRepo.PurgeExpired")
+	defer end(&err)
+
+	de := r.goquDb.Delete(idempotencyKeysTable).Where(goqu.C("created_at").Lt(time.Now().Add(-ttl)))
+
+	_, err = de.Executor().ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    status_code INT,
+    content_type TEXT,
+    body TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE idempotency_keys;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keys issued before roles existed had full access, so they become admins.
+ALTER TABLE api_keys ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'admin';
+ALTER TABLE api_keys ALTER COLUMN role SET DEFAULT 'viewer';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE api_keys DROP COLUMN role;
+-- +goose StatementEnd
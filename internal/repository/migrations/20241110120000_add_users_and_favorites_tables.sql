@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    subject VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE favorites (
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, song_id)
+);
+
+CREATE INDEX favorites_song_id_idx ON favorites (song_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE favorites;
+DROP TABLE users;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every recorded listen. Plays are written in batches and never lock the songs table.
+CREATE TABLE plays (
+    id BIGSERIAL PRIMARY KEY,
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    source VARCHAR(32) NOT NULL DEFAULT '',
+    played_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX plays_song_id_played_at_idx ON plays (song_id, played_at);
+
+-- Number of plays per song and UTC day, used for popularity ranking and charts.
+CREATE TABLE song_daily_plays (
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    plays BIGINT NOT NULL,
+    PRIMARY KEY (song_id, day)
+);
+
+CREATE INDEX song_daily_plays_day_idx ON song_daily_plays (day);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_daily_plays;
+DROP TABLE plays;
+-- +goose StatementEnd
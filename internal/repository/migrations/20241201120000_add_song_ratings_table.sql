@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One rating per user and song, optionally with a review that is shown to other users once approved.
+CREATE TABLE song_ratings (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    review TEXT NOT NULL DEFAULT '',
+    review_status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (review_status IN ('pending', 'approved', 'rejected')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT unique_user_song_rating UNIQUE (user_id, song_id)
+);
+
+CREATE INDEX song_ratings_song_id_idx ON song_ratings (song_id) INCLUDE (rating);
+CREATE INDEX song_ratings_review_status_idx ON song_ratings (review_status, created_at) WHERE review <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_ratings;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Annotations are anchored to a range of lines or characters of a verse. text_hash identifies the song text
+-- the anchor was resolved against, so anchors can be re-mapped after the lyrics are edited.
+CREATE TABLE annotations (
+    id SERIAL PRIMARY KEY,
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    verse_index INT NOT NULL,
+    anchor_unit VARCHAR(8) NOT NULL CHECK (anchor_unit IN ('line', 'char')),
+    anchor_start INT NOT NULL,
+    anchor_end INT NOT NULL,
+    anchor_text TEXT NOT NULL,
+    text_hash CHAR(64) NOT NULL,
+    is_stale BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (anchor_start >= 0 AND anchor_start < anchor_end)
+);
+
+CREATE INDEX annotations_song_id_verse_index_idx ON annotations (song_id, verse_index);
+
+CREATE TABLE annotation_votes (
+    annotation_id INT NOT NULL REFERENCES annotations (id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    value SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    PRIMARY KEY (annotation_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE annotation_votes;
+DROP TABLE annotations;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Most similar songs of every song, computed offline by the similar command.
+CREATE TABLE song_similarities (
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    similar_song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (song_id, similar_song_id)
+);
+
+CREATE INDEX song_similarities_similar_song_id_idx ON song_similarities (similar_song_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_similarities;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tags of songs, such as genres and moods, lowercase.
+CREATE TABLE song_tags (
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (song_id, tag)
+);
+
+CREATE INDEX song_tags_tag_idx ON song_tags (tag);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_tags;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Song of the day, picked on the first request of the UTC day so that it stays the same for everyone.
+CREATE TABLE daily_songs (
+    day DATE PRIMARY KEY,
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE
+);
+
+CREATE INDEX daily_songs_song_id_idx ON daily_songs (song_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE daily_songs;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Users of API keys were keyed by the key name, which isn't unique. Every existing user moves to the oldest key
+-- with its name, and other keys sharing the name get their own user on their next request.
+UPDATE users
+SET subject = 'api_key#' || k.id
+FROM (SELECT DISTINCT ON (name) id, name FROM api_keys ORDER BY name, id) k
+WHERE users.subject = 'api_key:' || k.name;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE users
+SET subject = 'api_key:' || k.name
+FROM (SELECT DISTINCT ON (name) id, name FROM api_keys ORDER BY name, id) k
+WHERE users.subject = 'api_key#' || k.id;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing records belong to no principal and expire with the TTL.
+ALTER TABLE idempotency_keys ADD COLUMN principal VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (principal, key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM idempotency_keys WHERE principal <> '';
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys DROP COLUMN principal;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key);
+-- +goose StatementEnd
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"io/fs"
+	"path"
+	"songs-library-go/internal/domain"
+)
+
+// migrationsFS holds the SQL migrations, so the binary migrates the database without the goose CLI or the source tree.
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Migrator applies the embedded migrations to the database. Migrations hold a Postgres advisory lock,
+// so instances starting at the same time apply every migration once.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// NewMigrator creates a new instance of Migrator for the database.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := fs.Sub(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations, goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		provider: provider,
+	}, nil
+}
+
+// Up applies all pending migrations.
+func (m Migrator) Up(ctx context.Context) ([]domain.MigrationResult, error) {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMigrationResults(results...), nil
+}
+
+// Down rolls back the latest applied migration.
+func (m Migrator) Down(ctx context.Context) (domain.MigrationResult, error) {
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		return domain.MigrationResult{}, err
+	}
+
+	return toMigrationResults(result)[0], nil
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+func (m Migrator) Redo(ctx context.Context) ([]domain.MigrationResult, error) {
+	down, err := m.provider.Down(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	up, err := m.provider.ApplyVersion(ctx, down.Source.Version, true)
+	if err != nil {
+		return toMigrationResults(down), err
+	}
+
+	return toMigrationResults(down, up), nil
+}
+
+// Status returns all migrations ordered by version and whether they are applied.
+func (m Migrator) Status(ctx context.Context) ([]domain.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]domain.MigrationStatus, len(statuses))
+	for i, status := range statuses {
+		migrations[i] = domain.MigrationStatus{
+			Version:   status.Source.Version,
+			Name:      path.Base(status.Source.Path),
+			Applied:   status.State == goose.StateApplied,
+			AppliedAt: status.AppliedAt,
+		}
+	}
+
+	return migrations, nil
+}
+
+// CheckPending returns domain.ErrMigrationsPending if any migration is not applied. It doesn't wait for the lock,
+// so it doesn't block while another instance is migrating.
+func (m Migrator) CheckPending(ctx context.Context) error {
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil {
+		return err
+	}
+
+	if pending {
+		_, target, err := m.provider.GetVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("%w (version: %d)", domain.ErrMigrationsPending, target)
+	}
+
+	return nil
+}
+
+func toMigrationResults(results ...*goose.MigrationResult) []domain.MigrationResult {
+	migrations := make([]domain.MigrationResult, len(results))
+	for i, result := range results {
+		migrations[i] = domain.MigrationResult{
+			Version:   result.Source.Version,
+			Name:      path.Base(result.Source.Path),
+			Direction: result.Direction,
+			Duration:  result.Duration,
+		}
+	}
+
+	return migrations
+}
@@ -0,0 +1,400 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/lib/pq"
+	"math"
+	"songs-library-go/internal/domain"
+	"time"
+)
+
+const (
+	playlistsTable       = "playlists"
+	playlistEntriesTable = "playlist_entries"
+)
+
+// playlistEntryRow is a playlist entry joined with its song.
+type playlistEntryRow struct {
+	EntryID  int32     `db:"entry_id"`
+	Position int       `db:"position"`
+	AddedAt  time.Time `db:"added_at"`
+	domain.SongWithNull
+}
+
+// PlaylistsRepo implements the PlaylistsRepo interface for interacting with the database using goqu.
+// Songs are removed from playlists by the database when they are deleted.
+type PlaylistsRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewPlaylistsRepo creates a new instance of PlaylistsRepo, initializing it with a goqu.Database.
+func NewPlaylistsRepo(db *sql.DB) *PlaylistsRepo {
+	return &PlaylistsRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// Create adds a new playlist and returns it.
+func (r PlaylistsRepo) Create(ctx context.Context, playlist domain.Playlist) (_ domain.Playlist, err error) {
+	ctx, end := startSpan(ctx, "PlaylistsRepo.Create")
+	defer end(&err)
+
+	insert := r.goquDb.Insert(playlistsTable).
+		Rows(goqu.Record{
+			"user_id":     playlist.UserID,
+			"name":        playlist.Name,
+			"description": playlist.Description,
+			"is_public":   playlist.IsPublic,
+		}).
+		Returning("id", "user_id", "name", "description", "is_public", goqu.L("0").As("songs_count"), "created_at", "updated_at")
+
+	var created domain.Playlist
+	if _, err := insert.Executor().ScanStructContext(ctx, &created); err != nil {
+		return domain.Playlist{}, err
+	}
+
+	return created, nil
+}
+
+// GetPlaylists retrieves a paginated list of the playlists owned by the user, most recently updated first.
+func (r PlaylistsRepo) GetPlaylists(ctx context.Context, userID int32, page, limit int) (_ []domain.Playlist, _ int, err error) {
+	ctx, end := startSpan(ctx, "PlaylistsRepo.GetPlaylists")
+	defer end(&err)
+
+	conditions := goqu.Ex{"user_id": userID}
+
+	var totalCount int
+	if _, err := r.goquDb.Select(goqu.COUNT("id")).From(playlistsTable).Where(conditions).Executor().ScanValContext(ctx, &totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	query := r.selectPlaylists().
+		Where(conditions).
+		Order(goqu.C("updated_at").Desc(), goqu.C("id").Desc()).
+		Limit(uint(limit)).
+		Offset(uint((page - 1) * limit))
+
+	var playlists []domain.Playlist
+	if err := query.Executor().ScanStructsContext(ctx, &playlists); err != nil {
+		return nil, 0, err
+	}
+
+	return playlists, int(math.Ceil(float64(totalCount) / float64(limit))), nil
+}
+
+// GetPlaylist retrieves a playlist that is owned by the user or public.
+func (r PlaylistsRepo) GetPlaylist(ctx context.Context, playlistID, userID int32) (_ domain.Playlist, err error) {
+	ctx, end := startSpan(ctx, "PlaylistsRepo.GetPlaylist")
+	defer end(&err)
+
+	query := r.selectPlaylists().
+		Where(goqu.Ex{"id": playlistID}, goqu.Or(goqu.Ex{"user_id": userID}, goqu.Ex{"is_public": true}))
+
+	var playlist domain.Playlist
+	found, err := query.Executor().ScanStructContext(ctx, &playlist)
+	if err != nil {
+		return domain.Playlist{}, err
+	}
+
+	if !found {
+		return domain.Playlist{}, fmt.Errorf("%w (id: %d)", domain.ErrPlaylistNotFound, playlistID)
+	}
+
+	return playlist, nil
+}
+
+// GetEntries retrieves a paginated list of the entries of a playlist in order.
+func (r PlaylistsRepo) GetEntries(ctx context.Context, playlistID int32, page, limit int) (_ []domain.PlaylistEntry, _ int, err error) {
+	ctx, end := startSpan(ctx, "PlaylistsRepo.GetEntries")
+	defer end(&err)
+
+	conditions := goqu.Ex{"playlist_id": playlistID}
+
+	var totalCount int
+	if _, err := r.goquDb.Select(goqu.COUNT("id")).From(playlistEntriesTable).Where(conditions).Executor().ScanValContext(ctx, &totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	query := r.goquDb.From(goqu.T(playlistEntriesTable).As("e")).
+		Join(goqu.T(songsTable).As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("e.song_id")))).
+		Select(
+			goqu.I("e.id").As("entry_id"),
+			goqu.ROW_NUMBER().Over(goqu.W().OrderBy(goqu.I("e.position").Asc())).As("position"),
+			goqu.I("e.added_at").As("added_at"),
+			goqu.I("s.id").As("id"),
+			goqu.I("s.group").As("group"),
+			goqu.I("s.song").As("song"),
+			goqu.I("s.release_date").As("release_date"),
+			goqu.I("s.text").As("text"),
+			goqu.I("s.link").As("link"),
+		).
+		Where(goqu.Ex{"e.playlist_id": playlistID}).
+		Order(goqu.I("e.position").Asc()).
+		Limit(uint(limit)).
+		Offset(uint((page - 1) * limit))
+
+	var rows []playlistEntryRow
+	if err := query.Executor().ScanStructsContext(ctx, &rows); err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]domain.PlaylistEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = domain.PlaylistEntry{
+			ID:       row.EntryID,
+			Position: row.Position,
+			AddedAt:  row.AddedAt,
+			Song:     SongsRepo{}.toSong(row.SongWithNull),
+		}
+	}
+
+	return entries, int(math.Ceil(float64(totalCount) / float64(limit))), nil
+}
+
+// Update replaces the name, description and visibility of a playlist owned by the user.
+func (r PlaylistsRepo) Update(ctx context.Context, playlist domain.Playlist) (_ domain.Playlist, err error) {
+	ctx, end := startSpan(ctx, "PlaylistsRepo.Update")
+	defer end(&err)
+
+	update := r.goquDb.Update(playlistsTable).
+		Set(goqu.Record{
+			"name":        playlist.Name,
+			"description": playlist.Description,
+			"is_public":   playlist.IsPublic,
+			"updated_at":  goqu.L("now()"),
+		}).
+		Where(goqu.Ex{"id": playlist.ID, "user_id": playlist.UserID})
+
+	result, err := update.Executor().ExecContext(ctx)
+	if err != nil {
+		return domain.Playlist{}, err
+	}
+
+	if err := r.checkAffected(result, domain.ErrPlaylistNotFound, playlist.ID); err != nil {
+		return domain.Playlist{}, err
+	}
+
+	return r.GetPlaylist(ctx, playlist.ID, playlist.UserID)
+}
+
+// Delete removes a playlist owned by the user together with its entries.
+func (r PlaylistsRepo) Delete(ctx context.Context, playlistID, userID int32) (err error) {
+	ctx, end := startSpan(ctx, "PlaylistsRepo.Delete")
+	defer end(&err)
+
+	result, err := r.goquDb.Delete(playlistsTable).Where(goqu.Ex{"id": playlistID, "user_id": userID}).Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.checkAffected(result, domain.ErrPlaylistNotFound, playlistID)
+}
+
+// AddEntry inserts the song into a playlist owned by the user at the given position, shifting the following entries down.
+// A position of 0 or after the last entry appends the song.
+func (r PlaylistsRepo) AddEntry(ctx context.Context, playlistID, userID, songID int32, position int) (_ domain.PlaylistEntry, err error) {
+	ctx, end := startSpan(ctx, "PlaylistsRepo.AddEntry")
+	defer end(&err)
+
+	var entry domain.PlaylistEntry
+
+	err = withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		if err := r.lockPlaylist(ctx, tx, playlistID, userID); err != nil {
+			return err
+		}
+
+		count, err := r.renumberEntries(ctx, tx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		if position <= 0 || position > count {
+			position = count + 1
+		} else {
+			shift := tx.Update(playlistEntriesTable).
+				Set(goqu.Record{"position": goqu.L("position + 1")}).
+				Where(goqu.Ex{"playlist_id": playlistID}, goqu.C("position").Gte(position))
+
+			if _, err := shift.Executor().ExecContext(ctx); err != nil {
+				return err
+			}
+		}
+
+		var song domain.SongWithNull
+		songExists, err := tx.From(songsTable).Where(goqu.Ex{"id": songID}).ScanStructContext(ctx, &song)
+		if err != nil {
+			return err
+		}
+
+		if !songExists {
+			return fmt.Errorf("%w (id: %d)", domain.ErrSongNotFound, songID)
+		}
+
+		insert := tx.Insert(playlistEntriesTable).
+			Rows(goqu.Record{"playlist_id": playlistID, "song_id": songID, "position": position}).
+			Returning(goqu.C("id").As("entry_id"), "position", "added_at")
+
+		var row playlistEntryRow
+		if _, err := insert.Executor().ScanStructContext(ctx, &row); err != nil {
+			var pgErr *pq.Error
+			if errors.As(err, &pgErr) && pgErr.Code == domain.CodeForeignKeyViolation {
+				return fmt.Errorf("%w (id: %d)", domain.ErrSongNotFound, songID)
+			}
+			return err
+		}
+
+		entry = domain.PlaylistEntry{
+			ID:       row.EntryID,
+			Position: row.Position,
+			AddedAt:  row.AddedAt,
+			Song:     SongsRepo{}.toSong(song),
+		}
+
+		return r.touchPlaylist(ctx, tx, playlistID)
+	})
+	if err != nil {
+		return domain.PlaylistEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// RemoveEntry removes an entry from a playlist owned by the user.
+func (r PlaylistsRepo) RemoveEntry(ctx context.Context, playlistID, userID, entryID int32) (err error) {
+	ctx, end := startSpan(ctx, "PlaylistsRepo.RemoveEntry")
+	defer end(&err)
+
+	return withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		if err := r.lockPlaylist(ctx, tx, playlistID, userID); err != nil {
+			return err
+		}
+
+		result, err := tx.Delete(playlistEntriesTable).Where(goqu.Ex{"id": entryID, "playlist_id": playlistID}).Executor().ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := r.checkAffected(result, domain.ErrPlaylistEntryNotFound, entryID); err != nil {
+			return err
+		}
+
+		return r.touchPlaylist(ctx, tx, playlistID)
+	})
+}
+
+// ReorderEntries sets the order of the entries of a playlist owned by the user.
+// entryIDs must contain every entry of the playlist exactly once.
+func (r PlaylistsRepo) ReorderEntries(ctx context.Context, playlistID, userID int32, entryIDs []int32) (err error) {
+	ctx, end := startSpan(ctx, "PlaylistsRepo.ReorderEntries")
+	defer end(&err)
+
+	return withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		if err := r.lockPlaylist(ctx, tx, playlistID, userID); err != nil {
+			return err
+		}
+
+		var currentIDs []int32
+		if err := tx.From(playlistEntriesTable).Select("id").Where(goqu.Ex{"playlist_id": playlistID}).ScanValsContext(ctx, &currentIDs); err != nil {
+			return err
+		}
+
+		if !sameEntries(currentIDs, entryIDs) {
+			return domain.ErrInvalidPlaylistOrder
+		}
+
+		update := tx.Update(playlistEntriesTable).
+			Set(goqu.Record{"position": goqu.L("array_position(?::int[], id)", pq.Array(entryIDs))}).
+			Where(goqu.Ex{"playlist_id": playlistID})
+
+		if _, err := update.Executor().ExecContext(ctx); err != nil {
+			return err
+		}
+
+		return r.touchPlaylist(ctx, tx, playlistID)
+	})
+}
+
+func (r PlaylistsRepo) selectPlaylists() *goqu.SelectDataset {
+	songsCount := r.goquDb.From(playlistEntriesTable).
+		Select(goqu.COUNT("*")).
+		Where(goqu.I(playlistEntriesTable + ".playlist_id").Eq(goqu.I(playlistsTable + ".id")))
+
+	return r.goquDb.From(playlistsTable).
+		Select("id", "user_id", "name", "description", "is_public", songsCount.As("songs_count"), "created_at", "updated_at")
+}
+
+func (r PlaylistsRepo) lockPlaylist(ctx context.Context, tx *goqu.TxDatabase, playlistID, userID int32) error {
+	query := tx.From(playlistsTable).
+		Select("id").
+		Where(goqu.Ex{"id": playlistID, "user_id": userID}).
+		ForUpdate(exp.Wait)
+
+	var id int32
+	found, err := query.ScanValContext(ctx, &id)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%w (id: %d)", domain.ErrPlaylistNotFound, playlistID)
+	}
+
+	return nil
+}
+
+// renumberEntries closes the gaps left by removed entries so that positions run from 1 to the number of entries,
+// which it returns.
+func (r PlaylistsRepo) renumberEntries(ctx context.Context, tx *goqu.TxDatabase, playlistID int32) (int, error) {
+	result, err := tx.ExecContext(ctx, `UPDATE playlist_entries e SET position = o.row_number
+		FROM (SELECT id, row_number() OVER (ORDER BY position) FROM playlist_entries WHERE playlist_id = $1) o
+		WHERE e.id = o.id`, playlistID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := result.RowsAffected()
+	return int(count), err
+}
+
+func (r PlaylistsRepo) touchPlaylist(ctx context.Context, tx *goqu.TxDatabase, playlistID int32) error {
+	_, err := tx.Update(playlistsTable).Set(goqu.Record{"updated_at": goqu.L("now()")}).Where(goqu.Ex{"id": playlistID}).Executor().ExecContext(ctx)
+	return err
+}
+
+func (r PlaylistsRepo) checkAffected(result sql.Result, notFound error, id int32) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w (id: %d)", notFound, id)
+	}
+
+	return nil
+}
+
+func sameEntries(currentIDs, entryIDs []int32) bool {
+	if len(currentIDs) != len(entryIDs) {
+		return false
+	}
+
+	remaining := make(map[int32]bool, len(currentIDs))
+	for _, id := range currentIDs {
+		remaining[id] = true
+	}
+
+	for _, id := range entryIDs {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+
+	return true
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/lib/pq"
+	"slices"
+	"songs-library-go/internal/domain"
+	"time"
+)
+
+const (
+	playsTable          = "plays"
+	songDailyPlaysTable = "song_daily_plays"
+)
+
+// chartRow is a song together with its number of plays in the chart period.
+type chartRow struct {
+	domain.SongWithNull
+	Plays int64 `db:"plays"`
+}
+
+// dailyPlays identifies the counter of a song for a UTC day.
+type dailyPlays struct {
+	songID int32
+	day    string
+}
+
+// PlaysRepo implements the PlaysRepo interface for interacting with the database using goqu.
+// Plays are stored in the plays table and counted per song and day in song_daily_plays,
+// so recording plays never updates the songs table.
+type PlaysRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewPlaysRepo creates a new instance of PlaysRepo, initializing it with a goqu.Database.
+func NewPlaysRepo(db *sql.DB) *PlaysRepo {
+	return &PlaysRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// SongExists returns domain.ErrSongNotFound if there is no song with the given ID.
+func (r PlaysRepo) SongExists(ctx context.Context, songID int32) (err error) {
+	ctx, end := startSpan(ctx, "PlaysRepo.SongExists")
+	defer end(&err)
+
+	return songExists(ctx, r.goquDb, songID)
+}
+
+// RecordPlays stores a batch of plays and adds them to the daily counters in a single transaction.
+// Plays of songs that have been deleted in the meantime are dropped.
+func (r PlaysRepo) RecordPlays(ctx context.Context, plays []domain.Play) (err error) {
+	ctx, end := startSpan(ctx, "PlaysRepo.RecordPlays")
+	defer end(&err)
+
+	songIDs := make([]int32, len(plays))
+	userIDs := make([]int32, len(plays))
+	sources := make([]string, len(plays))
+	playedAt := make([]string, len(plays))
+	counters := make(map[dailyPlays]int64)
+
+	for i, play := range plays {
+		songIDs[i] = play.SongID
+		userIDs[i] = play.UserID
+		sources[i] = play.Source
+		playedAt[i] = play.PlayedAt.UTC().Format(time.RFC3339Nano)
+		counters[dailyPlays{songID: play.SongID, day: play.PlayedAt.UTC().Format(time.DateOnly)}]++
+	}
+
+	// Counters are upserted in a stable order so that concurrent batches can't deadlock.
+	keys := make([]dailyPlays, 0, len(counters))
+	for key := range counters {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b dailyPlays) int {
+		return cmp.Or(cmp.Compare(a.songID, b.songID), cmp.Compare(a.day, b.day))
+	})
+
+	counterSongIDs := make([]int32, len(keys))
+	counterDays := make([]string, len(keys))
+	counterPlays := make([]int64, len(keys))
+	for i, key := range keys {
+		counterSongIDs[i] = key.songID
+		counterDays[i] = key.day
+		counterPlays[i] = counters[key]
+	}
+
+	return withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO plays (song_id, user_id, source, played_at)
+			SELECT p.song_id, p.user_id, p.source, p.played_at
+			FROM unnest($1::int[], $2::int[], $3::text[], $4::timestamptz[]) AS p (song_id, user_id, source, played_at)
+			WHERE EXISTS (SELECT 1 FROM songs WHERE songs.id = p.song_id)`,
+			pq.Array(songIDs), pq.Array(userIDs), pq.Array(sources), pq.Array(playedAt))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO song_daily_plays (song_id, day, plays)
+			SELECT c.song_id, c.day, c.plays
+			FROM unnest($1::int[], $2::date[], $3::bigint[]) AS c (song_id, day, plays)
+			WHERE EXISTS (SELECT 1 FROM songs WHERE songs.id = c.song_id)
+			ON CONFLICT (song_id, day) DO UPDATE SET plays = song_daily_plays.plays + EXCLUDED.plays`,
+			pq.Array(counterSongIDs), pq.Array(counterDays), pq.Array(counterPlays))
+		return err
+	})
+}
+
+// GetChart retrieves the limit most played songs since the given day, most played first.
+func (r PlaysRepo) GetChart(ctx context.Context, since time.Time, limit int) (_ []domain.ChartEntry, err error) {
+	ctx, end := startSpan(ctx, "PlaysRepo.GetChart")
+	defer end(&err)
+
+	counts := r.goquDb.From(songDailyPlaysTable).
+		Select("song_id", goqu.SUM("plays").As("plays")).
+		Where(goqu.C("day").Gte(since.Format(time.DateOnly))).
+		GroupBy("song_id")
+
+	query := r.goquDb.From(songsTable).
+		Join(counts.As("counts"), goqu.On(goqu.I("counts.song_id").Eq(goqu.I(songsTable+".id")))).
+		Select(songsTable+".id", "group", "song", "release_date", "text", "link", "counts.plays").
+		Order(goqu.I("counts.plays").Desc(), goqu.I(songsTable+".id").Asc()).
+		Limit(uint(limit))
+
+	var rows []chartRow
+	if err := query.Executor().ScanStructsContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	chart := make([]domain.ChartEntry, len(rows))
+	for i, row := range rows {
+		chart[i] = domain.ChartEntry{
+			Rank:  i + 1,
+			Plays: row.Plays,
+			Song:  SongsRepo{}.toSong(row.SongWithNull),
+		}
+	}
+
+	return chart, nil
+}
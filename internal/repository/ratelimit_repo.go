@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"songs-library-go/internal/domain"
+	"time"
+)
+
+const rateLimitBucketsTable = "rate_limit_buckets"
+
+// RateLimitRepo stores the token buckets of the rate limiter in the database, so the limits hold across instances.
+type RateLimitRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewRateLimitRepo creates a new instance of RateLimitRepo, initializing it with a goqu.Database.
+func NewRateLimitRepo(db *sql.DB) *RateLimitRepo {
+	return &RateLimitRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// Take takes a token from the bucket of the key for a request at now. A missing bucket is inserted full before it is locked,
+// since locking a missing row locks nothing, and the bucket is locked while it is updated, so concurrent requests
+// of the same client on different instances take distinct tokens.
+func (r RateLimitRepo) Take(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (_ domain.RateLimitResult, err error) {
+	ctx, end := startSpan(ctx, "RateLimitRepo.Take")
+	defer end(&err)
+
+	var result domain.RateLimitResult
+
+	err = withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		// A concurrent insert of the same key waits for this transaction and then does nothing.
+		insert := tx.Insert(rateLimitBucketsTable).
+			Rows(goqu.Record{"key": key, "tokens": limit.Requests, "updated_at": now}).
+			OnConflict(goqu.DoNothing())
+
+		if _, err := insert.Executor().ExecContext(ctx); err != nil {
+			return err
+		}
+
+		query := tx.From(rateLimitBucketsTable).
+			Select("tokens", "updated_at").
+			Where(goqu.Ex{"key": key}).
+			ForUpdate(exp.Wait)
+
+		var bucket domain.TokenBucket
+		if _, err := query.ScanStructContext(ctx, &bucket); err != nil {
+			return err
+		}
+
+		bucket, result = bucket.Take(limit, now)
+
+		update := tx.Update(rateLimitBucketsTable).
+			Set(goqu.Record{"tokens": bucket.Tokens, "updated_at": bucket.UpdatedAt}).
+			Where(goqu.Ex{"key": key})
+
+		_, err := update.Executor().ExecContext(ctx)
+		return err
+	})
+	if err != nil {
+		return domain.RateLimitResult{}, err
+	}
+
+	return result, nil
+}
+
+// PurgeIdle removes buckets that haven't been updated for idle. A bucket idle for its whole period is full,
+// the same as a missing bucket.
+func (r RateLimitRepo) PurgeIdle(ctx context.Context, idle time.Duration) (err error) {
+	ctx, end := startSpan(ctx, "RateLimitRepo.PurgeIdle")
+	defer end(&err)
+
+	de := r.goquDb.Delete(rateLimitBucketsTable).Where(goqu.C("updated_at").Lt(time.Now().Add(-idle)))
+
+	_, err = de.Executor().ExecContext(ctx)
+	return err
+}
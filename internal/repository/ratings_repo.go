@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/lib/pq"
+	"math"
+	"songs-library-go/internal/domain"
+)
+
+const songRatingsTable = "song_ratings"
+
+// RatingsRepo implements the RatingsRepo interface for interacting with the database using goqu.
+// Ratings of a song are removed by the database when the song is deleted.
+type RatingsRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewRatingsRepo creates a new instance of RatingsRepo, initializing it with a goqu.Database.
+func NewRatingsRepo(db *sql.DB) *RatingsRepo {
+	return &RatingsRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// Rate adds or replaces the rating of a song by a user and returns it.
+// A changed review has to be approved again, while an unchanged review keeps its status.
+func (r RatingsRepo) Rate(ctx context.Context, rating domain.Rating) (_ domain.Rating, err error) {
+	ctx, end := startSpan(ctx, "RatingsRepo.Rate")
+	defer end(&err)
+
+	insert := r.goquDb.Insert(songRatingsTable).
+		Rows(goqu.Record{
+			"user_id":       rating.UserID,
+			"song_id":       rating.SongID,
+			"rating":        rating.Rating,
+			"review":        rating.Review,
+			"review_status": domain.ReviewPending,
+		}).
+		OnConflict(goqu.DoUpdate("user_id, song_id", goqu.Record{
+			"rating": goqu.I("excluded.rating"),
+			"review": goqu.I("excluded.review"),
+			"review_status": goqu.L("CASE WHEN ? = ? THEN ? ELSE ? END",
+				goqu.I(songRatingsTable+".review"), goqu.I("excluded.review"), goqu.I(songRatingsTable+".review_status"), goqu.I("excluded.review_status")),
+			"updated_at": goqu.L("now()"),
+		})).
+		Returning(goqu.Star())
+
+	var saved domain.Rating
+	if _, err := insert.Executor().ScanStructContext(ctx, &saved); err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) && pgErr.Code == domain.CodeForeignKeyViolation {
+			return domain.Rating{}, fmt.Errorf("%w (id: %d)", domain.ErrSongNotFound, rating.SongID)
+		}
+		return domain.Rating{}, err
+	}
+
+	return saved, nil
+}
+
+// GetRating retrieves the rating of a song by a user.
+func (r RatingsRepo) GetRating(ctx context.Context, userID, songID int32) (_ domain.Rating, err error) {
+	ctx, end := startSpan(ctx, "RatingsRepo.GetRating")
+	defer end(&err)
+
+	var rating domain.Rating
+	found, err := r.goquDb.From(songRatingsTable).Where(goqu.Ex{"user_id": userID, "song_id": songID}).ScanStructContext(ctx, &rating)
+	if err != nil {
+		return domain.Rating{}, err
+	}
+
+	if !found {
+		return domain.Rating{}, fmt.Errorf("%w (song id: %d)", domain.ErrRatingNotFound, songID)
+	}
+
+	return rating, nil
+}
+
+// DeleteRating removes the rating of a song by a user together with its review.
+func (r RatingsRepo) DeleteRating(ctx context.Context, userID, songID int32) (err error) {
+	ctx, end := startSpan(ctx, "RatingsRepo.DeleteRating")
+	defer end(&err)
+
+	result, err := r.goquDb.Delete(songRatingsTable).Where(goqu.Ex{"user_id": userID, "song_id": songID}).Executor().ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w (song id: %d)", domain.ErrRatingNotFound, songID)
+	}
+
+	return nil
+}
+
+// GetReviews retrieves a paginated list of reviews with the given status, newest first.
+// A positive songID limits the list to the reviews of that song, which must exist.
+func (r RatingsRepo) GetReviews(ctx context.Context, songID int32, status domain.ReviewStatus, page, limit int) (_ []domain.Rating, _ int, err error) {
+	ctx, end := startSpan(ctx, "RatingsRepo.GetReviews")
+	defer end(&err)
+
+	conditions := goqu.Ex{"review_status": status, "review": goqu.Op{"neq": ""}}
+
+	if songID > 0 {
+		if err := songExists(ctx, r.goquDb, songID); err != nil {
+			return nil, 0, err
+		}
+
+		conditions["song_id"] = songID
+	}
+
+	var totalCount int
+	if _, err := r.goquDb.Select(goqu.COUNT("id")).From(songRatingsTable).Where(conditions).Executor().ScanValContext(ctx, &totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	query := r.goquDb.From(songRatingsTable).
+		Where(conditions).
+		Order(goqu.C("created_at").Desc(), goqu.C("id").Desc()).
+		Limit(uint(limit)).
+		Offset(uint((page - 1) * limit))
+
+	var reviews []domain.Rating
+	if err := query.Executor().ScanStructsContext(ctx, &reviews); err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, int(math.Ceil(float64(totalCount) / float64(limit))), nil
+}
+
+// SetReviewStatus sets the moderation status of a review and returns the review.
+func (r RatingsRepo) SetReviewStatus(ctx context.Context, reviewID int32, status domain.ReviewStatus) (_ domain.Rating, err error) {
+	ctx, end := startSpan(ctx, "RatingsRepo.SetReviewStatus")
+	defer end(&err)
+
+	update := r.goquDb.Update(songRatingsTable).
+		Set(goqu.Record{"review_status": status}).
+		Where(goqu.Ex{"id": reviewID, "review": goqu.Op{"neq": ""}}).
+		Returning(goqu.Star())
+
+	var review domain.Rating
+	found, err := update.Executor().ScanStructContext(ctx, &review)
+	if err != nil {
+		return domain.Rating{}, err
+	}
+
+	if !found {
+		return domain.Rating{}, fmt.Errorf("%w (id: %d)", domain.ErrReviewNotFound, reviewID)
+	}
+
+	return review, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/lib/pq"
+	"net"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// readFromReplica runs read on the replica if there is one and falls back to the primary if the replica is unavailable.
+// Errors reported by the replica for the query itself, such as a statement timeout, are returned as they are.
+func readFromReplica(ctx context.Context, primary, replica *goqu.Database, read func(db *goqu.Database) error) error {
+	if replica == nil {
+		return read(primary)
+	}
+
+	err := read(replica)
+	if err == nil || !replicaUnavailable(ctx, err) {
+		return err
+	}
+
+	logging.FromContext(ctx).WithError(err).Warn(mesReplicaFallback)
+
+	return read(primary)
+}
+
+// replicaUnavailable reports whether err means that the replica can't serve queries: it can't be reached,
+// the connection broke, or the server is starting, shutting down or in recovery. Any other error, such as
+// a failed scan or a constraint violation, would fail on the primary as well.
+func replicaUnavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		// Class 08 are connection exceptions and class 57 operator interventions, such as cannot_connect_now.
+		// A query canceled by the statement timeout is class 57 as well, but the replica did serve it.
+		class := pgErr.Code.Class()
+		return class == "08" || (class == "57" && pgErr.Code != domain.CodeQueryCanceled)
+	}
+
+	return false
+}
@@ -1,33 +1,42 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	// Import the PostgreSQL driver.
 	_ "github.com/lib/pq"
 	log "github.com/sirupsen/logrus"
-	"os/exec"
 	"songs-library-go/internal/config"
 	"time"
 )
 
 const (
-	successfulConnectionToDb   = "successfully connected to db"
-	errConnectingToDb          = "error connecting to db"
-	mesReconnectingToDB        = "reconnecting to db"
-	successfulReconnectionToDb = "successfully reconnected to db"
-	errRunningMigrations       = "error running migration"
-	successfulRunMigrations    = "successfully executed migrations"
+	successfulConnectionToDb      = "successfully connected to db"
+	errConnectingToDb             = "error connecting to db"
+	successfulConnectionToReplica = "successfully connected to db replica"
+	errConnectingToReplica        = "error connecting to db replica, reads fall back to the primary"
+	mesReplicaFallback            = "db replica is unavailable, reading from the primary"
+	mesReconnectingToDB           = "reconnecting to db"
+	successfulReconnectionToDb    = "successfully reconnected to db"
+	errRunningMigrations          = "error running migration"
+	successfulRunMigrations       = "successfully executed migrations"
+	mesAppliedMigration           = "applied migration"
 )
 
-const (
-	pingInterval = 10 * time.Second
-	countTries   = 10
-)
+const pingInterval = 10 * time.Second
 
 // Init establishes a connection to the PostgreSQL database, checks the connection, and runs migrations.
 func Init(cfg *config.Config) *sql.DB {
-	conn, err := sql.Open("postgres", fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable", cfg.DbUser, cfg.DbPassword, cfg.DbHost, cfg.DbPort, cfg.DbName))
+	conn := Connect(cfg)
+
+	runMigrations(conn)
+
+	return conn
+}
+
+// Connect establishes a connection to the PostgreSQL database and checks the connection without running migrations.
+func Connect(cfg *config.Config) *sql.DB {
+	conn, err := open(cfg, cfg.PostgresDSN())
 	if err != nil {
 		log.WithError(err).Fatal(errConnectingToDb)
 	}
@@ -36,44 +45,83 @@ func Init(cfg *config.Config) *sql.DB {
 		log.WithError(err).Fatal(errConnectingToDb)
 	}
 
-	go pingDatabase(conn)
-
 	log.Info(successfulConnectionToDb)
 
-	runMigrations(cfg)
+	return conn
+}
+
+// ConnectReplica establishes a connection to the read replica of the database, or returns nil if there is none.
+// An unreachable replica doesn't stop the server, reads fall back to the primary until it is back.
+func ConnectReplica(cfg *config.Config) *sql.DB {
+	dsn := cfg.PostgresReplicaDSN()
+	if dsn == "" {
+		return nil
+	}
+
+	conn, err := open(cfg, dsn)
+	if err != nil {
+		log.WithError(err).Fatal(errConnectingToReplica)
+	}
+
+	if err := conn.Ping(); err != nil {
+		log.WithError(err).Warn(errConnectingToReplica)
+		return conn
+	}
+
+	log.Info(successfulConnectionToReplica)
 
 	return conn
 }
 
-func runMigrations(cfg *config.Config) {
-	cmd := exec.Command("goose", "postgres", fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable", cfg.DbUser, cfg.DbPassword, cfg.DbHost, cfg.DbPort, cfg.DbName), "up")
-	cmd.Dir = "./internal/repository/migrations"
+// open opens a pool of connections to dsn with the pool settings of cfg.
+func open(cfg *config.Config, dsn string) (*sql.DB, error) {
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetMaxOpenConns(cfg.DbMaxOpenConns)
+	conn.SetMaxIdleConns(cfg.DbMaxIdleConns)
+	conn.SetConnMaxLifetime(cfg.DbConnMaxLifetime)
+
+	return conn, nil
+}
 
-	output, err := cmd.CombinedOutput()
+func runMigrations(conn *sql.DB) {
+	migrator, err := NewMigrator(conn)
 	if err != nil {
-		log.WithError(fmt.Errorf("%s: %s", err, output)).Fatalf(errRunningMigrations)
+		log.WithError(err).Fatal(errRunningMigrations)
+	}
+
+	results, err := migrator.Up(context.Background())
+	if err != nil {
+		log.WithError(err).Fatal(errRunningMigrations)
+	}
+
+	for _, result := range results {
+		log.WithField("duration", result.Duration).Infof("%s %s", mesAppliedMigration, result.Name)
 	}
 
 	log.Info(successfulRunMigrations)
 }
 
-func pingDatabase(conn *sql.DB) {
+// PingDatabase periodically checks the connection to the database until ctx is done and logs while it is unreachable.
+// The process keeps running, /readyz reports the database as down instead.
+func PingDatabase(ctx context.Context, conn *sql.DB) {
 	count := 0
 	ticker := time.NewTicker(pingInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-ctx.Done():
+			return
 		case <-ticker.C:
-			err := conn.Ping()
+			err := conn.PingContext(ctx)
 			if err != nil {
 				count++
 
-				if count == countTries {
-					log.WithError(err).Fatal(errConnectingToDb)
-				}
-
-				log.WithError(err).Info(mesReconnectingToDB)
+				log.WithError(err).Infof("%s (attempt: %d)", mesReconnectingToDB, count)
 			} else {
 				if count != 0 {
 					count = 0
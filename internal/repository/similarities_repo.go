@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/lib/pq"
+	"songs-library-go/internal/domain"
+)
+
+const (
+	songSimilaritiesTable = "song_similarities"
+	similaritiesBatchSize = 1000
+)
+
+// similarSongRow is a song together with its similarity to another song.
+type similarSongRow struct {
+	domain.SongWithNull
+	Score float64 `db:"score"`
+}
+
+// SimilaritiesRepo implements the SimilaritiesRepo interface for interacting with the database using goqu.
+type SimilaritiesRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewSimilaritiesRepo creates a new instance of SimilaritiesRepo, initializing it with a goqu.Database.
+func NewSimilaritiesRepo(db *sql.DB) *SimilaritiesRepo {
+	return &SimilaritiesRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// ReplaceSimilarities replaces all precomputed similarities in a single transaction, so readers see either
+// the previous or the new similarities. Similarities of songs that have been deleted in the meantime are dropped.
+func (r SimilaritiesRepo) ReplaceSimilarities(ctx context.Context, similarities []domain.Similarity) (err error) {
+	ctx, end := startSpan(ctx, "SimilaritiesRepo.ReplaceSimilarities")
+	defer end(&err)
+
+	return withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		if _, err := tx.Delete(songSimilaritiesTable).Executor().ExecContext(ctx); err != nil {
+			return err
+		}
+
+		for start := 0; start < len(similarities); start += similaritiesBatchSize {
+			batch := similarities[start:min(start+similaritiesBatchSize, len(similarities))]
+
+			songIDs := make([]int32, len(batch))
+			similarSongIDs := make([]int32, len(batch))
+			scores := make([]float64, len(batch))
+
+			for i, similarity := range batch {
+				songIDs[i] = similarity.SongID
+				similarSongIDs[i] = similarity.SimilarSongID
+				scores[i] = similarity.Score
+			}
+
+			_, err := tx.ExecContext(ctx, `INSERT INTO song_similarities (song_id, similar_song_id, score)
+				SELECT s.song_id, s.similar_song_id, s.score
+				FROM unnest($1::int[], $2::int[], $3::float8[]) AS s (song_id, similar_song_id, score)
+				WHERE EXISTS (SELECT 1 FROM songs WHERE songs.id = s.song_id)
+					AND EXISTS (SELECT 1 FROM songs WHERE songs.id = s.similar_song_id)`,
+				pq.Array(songIDs), pq.Array(similarSongIDs), pq.Array(scores))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetSimilar retrieves the limit songs most similar to a song, which must exist, most similar first.
+func (r SimilaritiesRepo) GetSimilar(ctx context.Context, songID int32, limit int) (_ []domain.SimilarSong, err error) {
+	ctx, end := startSpan(ctx, "SimilaritiesRepo.GetSimilar")
+	defer end(&err)
+
+	if err := songExists(ctx, r.goquDb, songID); err != nil {
+		return nil, err
+	}
+
+	query := r.goquDb.From(songSimilaritiesTable).
+		Join(goqu.T(songsTable), goqu.On(goqu.I(songsTable+".id").Eq(goqu.I(songSimilaritiesTable+".similar_song_id")))).
+		Select(songsTable+".id", "group", "song", "release_date", "text", "link", "score").
+		Where(goqu.Ex{songSimilaritiesTable + ".song_id": songID}).
+		Order(goqu.C("score").Desc(), goqu.I(songsTable+".id").Asc()).
+		Limit(uint(limit))
+
+	var rows []similarSongRow
+	if err := query.Executor().ScanStructsContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	similarSongs := make([]domain.SimilarSong, len(rows))
+	for i, row := range rows {
+		similarSongs[i] = domain.SimilarSong{
+			Score: row.Score,
+			Song:  SongsRepo{}.toSong(row.SongWithNull),
+		}
+	}
+
+	return similarSongs, nil
+}
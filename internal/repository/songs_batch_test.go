@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/lib/pq"
+	"songs-library-go/internal/domain"
+	"testing"
+)
+
+func newMockSongsRepo(t *testing.T) (*SongsRepo, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("creating sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	goquDb := goqu.New("postgres", db)
+
+	return &SongsRepo{goquDb: goquDb, querier: goquDb}, mock
+}
+
+var batchOps = []domain.SongOperation{
+	{Type: domain.OpCreate, Group: "Muse", Song: "Hysteria"},
+	{Type: domain.OpUpdate, SongID: 2, Params: map[string]interface{}{"text": "Verse"}},
+	{Type: domain.OpDelete, SongID: 3},
+}
+
+func expectCreate(mock sqlmock.Sqlmock, id int) *sqlmock.ExpectedQuery {
+	return mock.ExpectQuery(`INSERT INTO "songs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group", "song"}).AddRow(id, "Muse", "Hysteria"))
+}
+
+func expectUpdate(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+	return mock.ExpectQuery(`UPDATE "songs"`)
+}
+
+func expectDelete(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+	return mock.ExpectExec(`DELETE FROM "songs"`)
+}
+
+func updatedSongRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "group", "song", "release_date", "text", "link"}).
+		AddRow(2, "Muse", "Uprising", nil, "Verse", nil)
+}
+
+func TestApplyBatchAtomic(t *testing.T) {
+	tests := []struct {
+		name     string
+		expect   func(mock sqlmock.Sqlmock)
+		wantErr  error
+		wantErrs []error
+	}{
+		{
+			name: "all operations succeed",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectCreate(mock, 1)
+				expectUpdate(mock).WillReturnRows(updatedSongRows())
+				expectDelete(mock).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantErrs: []error{nil, nil, nil},
+		},
+		{
+			name: "failed operation rolls back the batch",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectCreate(mock, 1)
+				expectUpdate(mock).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr:  domain.ErrBatchAborted,
+			wantErrs: []error{domain.ErrBatchRolledBack, domain.ErrSongNotFound, domain.ErrBatchNotApplied},
+		},
+		{
+			name: "failed first operation applies none",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectCreate(mock, 0).WillReturnError(&pq.Error{Code: domain.CodeUniqueConstraintViolation})
+				mock.ExpectRollback()
+			},
+			wantErr:  domain.ErrBatchAborted,
+			wantErrs: []error{domain.ErrSongAlreadyExist, domain.ErrBatchNotApplied, domain.ErrBatchNotApplied},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock := newMockSongsRepo(t)
+			tt.expect(mock)
+
+			results, err := repo.ApplyBatch(context.Background(), batchOps, true)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApplyBatch() error = %v, want %v", err, tt.wantErr)
+			}
+
+			assertBatchResults(t, results, tt.wantErrs)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestApplyBatchBestEffort(t *testing.T) {
+	repo, mock := newMockSongsRepo(t)
+
+	expectCreate(mock, 1)
+	expectUpdate(mock).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	expectDelete(mock).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	results, err := repo.ApplyBatch(context.Background(), batchOps, false)
+	if err != nil {
+		t.Fatalf("ApplyBatch() error = %v", err)
+	}
+
+	assertBatchResults(t, results, []error{nil, domain.ErrSongNotFound, nil})
+
+	if results[0].Song.ID != 1 {
+		t.Errorf("created song ID = %d, want 1", results[0].Song.ID)
+	}
+
+	if results[2].Song.ID != 3 {
+		t.Errorf("deleted song ID = %d, want 3", results[2].Song.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func assertBatchResults(t *testing.T, results []domain.SongOperationResult, wantErrs []error) {
+	t.Helper()
+
+	if len(results) != len(wantErrs) {
+		t.Fatalf("ApplyBatch() returned %d results, want %d", len(results), len(wantErrs))
+	}
+
+	for i, result := range results {
+		if !errors.Is(result.Err, wantErrs[i]) || (wantErrs[i] == nil) != (result.Err == nil) {
+			t.Errorf("result %d error = %v, want %v", i, result.Err, wantErrs[i])
+		}
+	}
+}
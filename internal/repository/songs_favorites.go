@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/lib/pq"
+	"songs-library-go/internal/domain"
+)
+
+const favoritesTable = "favorites"
+
+// AddFavorite adds the song to the favorites of the user. Adding a song that is already a favorite is not an error.
+func (r SongsRepo) AddFavorite(ctx context.Context, userID, songID int32) (err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.AddFavorite")
+	defer end(&err)
+
+	insert := r.goquDb.Insert(favoritesTable).
+		Rows(goqu.Record{"user_id": userID, "song_id": songID}).
+		OnConflict(goqu.DoNothing())
+
+	if _, err := insert.Executor().ExecContext(ctx); err != nil {
+		var pgErr *pq.Error
+		if errors.As(err, &pgErr) && pgErr.Code == domain.CodeForeignKeyViolation {
+			return fmt.Errorf("%w (id: %d)", domain.ErrSongNotFound, songID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RemoveFavorite removes the song from the favorites of the user. Removing a song that is not a favorite is not an error.
+func (r SongsRepo) RemoveFavorite(ctx context.Context, userID, songID int32) (err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.RemoveFavorite")
+	defer end(&err)
+
+	de := r.goquDb.Delete(favoritesTable).Where(goqu.Ex{"user_id": userID, "song_id": songID})
+
+	_, err = de.Executor().ExecContext(ctx)
+	return err
+}
+
+func (r SongsRepo) isFavorite(userID int32) exp.LiteralExpression {
+	return goqu.L("EXISTS (?)", r.goquDb.From(favoritesTable).
+		Select(goqu.L("1")).
+		Where(goqu.Ex{"user_id": userID}, goqu.I(favoritesTable+".song_id").Eq(goqu.I(songsTable+".id"))))
+}
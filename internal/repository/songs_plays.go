@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"time"
+)
+
+// playsSince counts the plays of a song since the given day from the daily counters.
+func (r SongsRepo) playsSince(since time.Time) exp.LiteralExpression {
+	return goqu.L("COALESCE((?), 0)", r.goquDb.From(songDailyPlaysTable).
+		Select(goqu.SUM("plays")).
+		Where(goqu.I(songDailyPlaysTable+".song_id").Eq(goqu.I(songsTable+".id")), goqu.C("day").Gte(since.Format(time.DateOnly))))
+}
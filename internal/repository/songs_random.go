@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/doug-martin/goqu/v9"
+	"songs-library-go/internal/domain"
+	"time"
+)
+
+const dailySongsTable = "daily_songs"
+
+// idRange is the range of IDs of the songs matching a filter. Both bounds are NULL if no song matches.
+type idRange struct {
+	MinID sql.NullInt64 `db:"min_id"`
+	MaxID sql.NullInt64 `db:"max_id"`
+}
+
+// GetRandomSongs picks a distinct song matching the filters for every pivot from [0, 1). A pivot is mapped to an ID
+// in the range of IDs of matching songs and picks the first matching song not picked yet from this ID on,
+// wrapping around to the smallest ID. Every pick is an index range scan instead of ordering the table by random(),
+// at the cost of favoring songs that follow gaps in the IDs. Fewer songs are returned if fewer songs match.
+func (r SongsRepo) GetRandomSongs(ctx context.Context, pivots []float64, filtersMap map[string]interface{}, userID int32, favoritedOnly bool, minRating float64) (_ []domain.Song, err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.GetRandomSongs")
+	defer end(&err)
+
+	query, _ := r.filterSongs(r.goquDb.From(songsTable), filtersMap)
+	query = query.Where(r.listConditions(userID, favoritedOnly, minRating)...)
+
+	var ids idRange
+	if _, err := query.Select(goqu.MIN("id").As("min_id"), goqu.MAX("id").As("max_id")).ScanStructContext(ctx, &ids); err != nil {
+		return nil, err
+	}
+
+	if !ids.MinID.Valid {
+		return nil, nil
+	}
+
+	query = query.Select(r.listColumns(userID)...).Order(goqu.I("id").Asc()).Limit(1)
+
+	songs := make([]domain.Song, 0, len(pivots))
+	picked := make([]int32, 0, len(pivots))
+
+	for _, pivot := range pivots {
+		pivotID := ids.MinID.Int64 + int64(pivot*float64(ids.MaxID.Int64-ids.MinID.Int64+1))
+
+		remaining := query
+		if len(picked) > 0 {
+			remaining = remaining.Where(goqu.C("id").NotIn(picked))
+		}
+
+		var song songListItem
+		found, err := remaining.Where(goqu.C("id").Gte(pivotID)).ScanStructContext(ctx, &song)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			found, err = remaining.ScanStructContext(ctx, &song)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !found {
+			break
+		}
+
+		songs = append(songs, r.toListSong(song))
+		picked = append(picked, song.ID)
+	}
+
+	return songs, nil
+}
+
+// GetDailySong retrieves the song of the UTC day. The first request of the day picks the song at the pivot
+// as GetRandomSongs does and stores it, so concurrent requests and later requests get the same song.
+func (r SongsRepo) GetDailySong(ctx context.Context, day time.Time, pivot float64, userID int32) (_ domain.Song, err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.GetDailySong")
+	defer end(&err)
+
+	date := day.UTC().Format(time.DateOnly)
+
+	song, found, err := r.getDailySong(ctx, date, userID)
+	if err != nil || found {
+		return song, err
+	}
+
+	songs, err := r.GetRandomSongs(ctx, []float64{pivot}, nil, userID, false, 0)
+	if err != nil {
+		return domain.Song{}, err
+	}
+
+	if len(songs) == 0 {
+		return domain.Song{}, fmt.Errorf("%w (day: %s)", domain.ErrSongNotFound, date)
+	}
+
+	insert := r.goquDb.Insert(dailySongsTable).
+		Rows(goqu.Record{"day": date, "song_id": songs[0].ID}).
+		OnConflict(goqu.DoNothing())
+	if _, err := insert.Executor().ExecContext(ctx); err != nil {
+		return domain.Song{}, err
+	}
+
+	song, found, err = r.getDailySong(ctx, date, userID)
+	if err != nil {
+		return domain.Song{}, err
+	}
+
+	if !found {
+		return domain.Song{}, fmt.Errorf("%w (day: %s)", domain.ErrSongNotFound, date)
+	}
+
+	return song, nil
+}
+
+func (r SongsRepo) getDailySong(ctx context.Context, date string, userID int32) (domain.Song, bool, error) {
+	query := r.goquDb.From(songsTable).
+		Join(goqu.T(dailySongsTable), goqu.On(goqu.I(dailySongsTable+".song_id").Eq(goqu.I(songsTable+".id")))).
+		Select(r.listColumns(userID)...).
+		Where(goqu.Ex{dailySongsTable + ".day": date})
+
+	var song songListItem
+	found, err := query.ScanStructContext(ctx, &song)
+	if err != nil || !found {
+		return domain.Song{}, false, err
+	}
+
+	return r.toListSong(song), true, nil
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// averageRating computes the average rating of a song rounded to two decimals, or NULL if it has no ratings.
+func (r SongsRepo) averageRating() exp.LiteralExpression {
+	return goqu.L("(?)", r.goquDb.From(songRatingsTable).
+		Select(goqu.Func("ROUND", goqu.AVG("rating"), 2)).
+		Where(goqu.I(songRatingsTable+".song_id").Eq(goqu.I(songsTable+".id"))))
+}
+
+func (r SongsRepo) ratingsCount() exp.LiteralExpression {
+	return goqu.L("(?)", r.goquDb.From(songRatingsTable).
+		Select(goqu.COUNT("*")).
+		Where(goqu.I(songRatingsTable+".song_id").Eq(goqu.I(songsTable+".id"))))
+}
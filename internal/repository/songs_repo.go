@@ -1,10 +1,12 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/lib/pq"
 	"math"
 	"songs-library-go/internal/domain"
@@ -13,64 +15,158 @@ import (
 
 const songsTable = "songs"
 
+const (
+	exportCursor    = "songs_export"
+	exportFetchSize = 1000
+)
+
+// querier is implemented by both goqu.Database and goqu.TxDatabase.
+type querier interface {
+	Insert(table interface{}) *goqu.InsertDataset
+	Update(table interface{}) *goqu.UpdateDataset
+	Delete(table interface{}) *goqu.DeleteDataset
+}
+
+// songListItem is a song row together with the favorite flag of the requesting user, its rating
+// and, when sorted by popularity, its number of plays.
+type songListItem struct {
+	domain.SongWithNull
+	IsFavorite    bool            `db:"is_favorite"`
+	AverageRating sql.NullFloat64 `db:"average_rating"`
+	RatingsCount  int             `db:"ratings_count"`
+	Plays         sql.NullInt64   `db:"plays"`
+}
+
 // SongsRepo implements the SongsRepo interface for interacting with the database using goqu.
 type SongsRepo struct {
-	goquDb *goqu.Database
+	goquDb    *goqu.Database
+	replicaDb *goqu.Database
+	querier   querier
 }
 
 // NewSongsRepo creates a new instance of SongsRepo, initializing it with a goqu.Database.
-func NewSongsRepo(db *sql.DB) *SongsRepo {
+// Song lists and texts are read from replica if it isn't nil, and from db while the replica is unavailable.
+func NewSongsRepo(db *sql.DB, replica *sql.DB) *SongsRepo {
+	goquDb := goqu.New("postgres", db)
+
+	var replicaDb *goqu.Database
+	if replica != nil {
+		replicaDb = goqu.New("postgres", replica)
+	}
+
 	return &SongsRepo{
-		goquDb: goqu.New("postgres", db),
+		goquDb:    goquDb,
+		replicaDb: replicaDb,
+		querier:   goquDb,
 	}
 }
 
 // GetSongs retrieves a paginated list of songs from the database based on filters and pagination parameters.
-func (r SongsRepo) GetSongs(page int, limit int, filtersMap map[string]interface{}) ([]domain.Song, int, error) {
-	query := r.goquDb.From(songsTable)
+// Every song is flagged if the user has favorited it and carries its average rating and number of ratings.
+// favoritedOnly limits the list to the user's favorites and a positive minRating to songs rated at least as high.
+// Sorted by popularity, songs also carry their number of plays since sort.Since.
+func (r SongsRepo) GetSongs(ctx context.Context, page int, limit int, filtersMap map[string]interface{}, userID int32, favoritedOnly bool, minRating float64, sort domain.SongsSort) (_ []domain.Song, _ int, err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.GetSongs")
+	defer end(&err)
+
+	var songs []songListItem
+	var totalCount int
 
-	conditions := goqu.Ex{}
+	err = readFromReplica(ctx, r.goquDb, r.replicaDb, func(db *goqu.Database) error {
+		query, conditions := r.filterSongs(db.From(songsTable), filtersMap)
 
-	if len(filtersMap) > 0 {
-		for field, value := range filtersMap {
-			if field == "text" && value != "" {
-				words := strings.Fields(value.(string))
+		extraConditions := r.listConditions(userID, favoritedOnly, minRating)
+		query = query.Where(extraConditions...)
 
-				var textConditions []goqu.Expression
-				for _, word := range words {
-					textConditions = append(textConditions, goqu.I("text").ILike("%"+word+"%"))
-				}
+		var err error
+		if totalCount, err = r.getTotalCount(ctx, db, conditions, extraConditions...); err != nil {
+			return err
+		}
 
-				query = query.Where(goqu.And(textConditions...))
-			} else {
-				conditions[field] = value
-			}
+		columns := r.listColumns(userID)
+
+		switch sort.By {
+		case domain.SortPopular:
+			columns = append(columns, r.playsSince(sort.Since).As("plays"))
+			query = query.Order(goqu.I("plays").Desc(), goqu.I("id").Asc())
+		case domain.SortRating:
+			query = query.Order(goqu.I("average_rating").Desc().NullsLast(), goqu.I("ratings_count").Desc(), goqu.I("id").Asc())
 		}
 
-		query = query.Where(conditions)
-	}
+		query = query.
+			Select(columns...).
+			Limit(uint(limit)).
+			Offset(uint((page - 1) * limit))
 
-	totalCount, err := r.getTotalCount(conditions)
+		songs = nil
+		return query.Executor().ScanStructsContext(ctx, &songs)
+	})
 	if err != nil {
 		return nil, 0, err
 	}
 
-	query = query.Limit(uint(limit)).Offset(uint((page - 1) * limit))
+	normalizedSongs := make([]domain.Song, len(songs))
+	for i, song := range songs {
+		normalizedSongs[i] = r.toListSong(song)
+	}
 
-	var songs []domain.SongWithNull
-	if err := query.Executor().ScanStructs(&songs); err != nil {
-		return nil, 0, err
+	return normalizedSongs, int(math.Ceil(float64(totalCount) / float64(limit))), nil
+}
+
+// ExportSongs streams all songs matching the filters through a server-side cursor, calling fn for every song in id order.
+// Iteration stops at the first error returned by fn.
+func (r SongsRepo) ExportSongs(ctx context.Context, filtersMap map[string]interface{}, fn func(domain.Song) error) (err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.ExportSongs")
+	defer end(&err)
+
+	query, _ := r.filterSongs(r.goquDb.From(songsTable), filtersMap)
+
+	// Filter values are bound as parameters of the DECLARE rather than interpolated into the cursor query.
+	querySQL, args, err := query.Order(goqu.I("id").Asc()).Prepared(true).ToSQL()
+	if err != nil {
+		return err
 	}
 
-	return r.toSongs(songs), int(math.Ceil(float64(totalCount) / float64(limit))), nil
+	return withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		if _, err := tx.ExecContext(ctx, "DECLARE "+exportCursor+" NO SCROLL CURSOR FOR "+querySQL, args...); err != nil {
+			return err
+		}
+
+		for {
+			var songs []domain.SongWithNull
+			if err := tx.ScanStructsContext(ctx, &songs, fmt.Sprintf("FETCH %d FROM %s", exportFetchSize, exportCursor)); err != nil {
+				return err
+			}
+
+			for _, song := range songs {
+				if err := fn(r.toSong(song)); err != nil {
+					return err
+				}
+			}
+
+			if len(songs) < exportFetchSize {
+				_, err := tx.ExecContext(ctx, "CLOSE "+exportCursor)
+				return err
+			}
+		}
+	})
 }
 
 // GetSongText retrieves the text of a song by its ID from the database.
-func (r SongsRepo) GetSongText(songID int32) (string, error) {
-	query := r.goquDb.Select("text").From(songsTable).Where(goqu.Ex{"id": songID})
+func (r SongsRepo) GetSongText(ctx context.Context, songID int32) (_ string, err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.GetSongText")
+	defer end(&err)
 
 	var text sql.NullString
-	songExists, err := query.Executor().ScanVal(&text)
+	var songExists bool
+
+	err = readFromReplica(ctx, r.goquDb, r.replicaDb, func(db *goqu.Database) error {
+		query := db.Select("text").From(songsTable).Where(goqu.Ex{"id": songID})
+
+		var err error
+		songExists, err = query.Executor().ScanValContext(ctx, &text)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
@@ -86,11 +182,14 @@ func (r SongsRepo) GetSongText(songID int32) (string, error) {
 	return text.String, nil
 }
 
-// Delete removes a song from the database by its ID.
-func (r SongsRepo) Delete(songID int32) error {
-	de := r.goquDb.Delete(songsTable).Where(goqu.Ex{"id": songID})
+// Delete removes a song from the database by its ID. Favorites and playlist entries of the song are removed by the database.
+func (r SongsRepo) Delete(ctx context.Context, songID int32) (err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.Delete")
+	defer end(&err)
+
+	de := r.querier.Delete(songsTable).Where(goqu.Ex{"id": songID})
 
-	res, err := de.Executor().Exec()
+	res, err := de.Executor().ExecContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -108,14 +207,17 @@ func (r SongsRepo) Delete(songID int32) error {
 }
 
 // UpdateSong modifies an existing song in the database and returns the updated song.
-func (r SongsRepo) UpdateSong(songID int32, paramsMap map[string]interface{}) (domain.Song, error) {
-	update := r.goquDb.Update(songsTable).
+func (r SongsRepo) UpdateSong(ctx context.Context, songID int32, paramsMap map[string]interface{}) (_ domain.Song, err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.UpdateSong")
+	defer end(&err)
+
+	update := r.querier.Update(songsTable).
 		Set(paramsMap).
 		Where(goqu.Ex{"id": songID}).
 		Returning("id", "group", "song", "release_date", "text", "link")
 
 	var updatedSong domain.SongWithNull
-	songExists, err := update.Executor().ScanStruct(&updatedSong)
+	songExists, err := update.Executor().ScanStructContext(ctx, &updatedSong)
 	if err != nil {
 		var pgErr *pq.Error
 		if errors.As(err, &pgErr) && pgErr.Code == domain.CodeUniqueConstraintViolation {
@@ -131,14 +233,56 @@ func (r SongsRepo) UpdateSong(songID int32, paramsMap map[string]interface{}) (d
 	return r.toSong(updatedSong), nil
 }
 
+// PatchSong locks the song, calls fn with its current state and updates the song with the parameters returned by fn
+// in a single transaction.
+func (r SongsRepo) PatchSong(ctx context.Context, songID int32, fn func(domain.Song) (map[string]interface{}, error)) (_ domain.Song, err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.PatchSong")
+	defer end(&err)
+
+	var patchedSong domain.Song
+
+	err = withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		query := tx.From(songsTable).Where(goqu.Ex{"id": songID}).ForUpdate(exp.Wait)
+
+		var song domain.SongWithNull
+		songExists, err := query.ScanStructContext(ctx, &song)
+		if err != nil {
+			return err
+		}
+
+		if !songExists {
+			return fmt.Errorf("%w (id: %d)", domain.ErrSongNotFound, songID)
+		}
+
+		paramsMap, err := fn(r.toSong(song))
+		if err != nil {
+			return err
+		}
+
+		txRepo := r
+		txRepo.querier = tx
+
+		patchedSong, err = txRepo.UpdateSong(ctx, songID, paramsMap)
+		return err
+	})
+	if err != nil {
+		return domain.Song{}, err
+	}
+
+	return patchedSong, nil
+}
+
 // Create adds a new song to the database and returns the created song.
-func (r SongsRepo) Create(groupName, songName string) (domain.Song, error) {
-	insert := r.goquDb.Insert(songsTable).
+func (r SongsRepo) Create(ctx context.Context, groupName, songName string) (_ domain.Song, err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.Create")
+	defer end(&err)
+
+	insert := r.querier.Insert(songsTable).
 		Rows(goqu.Record{"group": groupName, "song": songName}).
 		Returning("id", "group", "song")
 
 	var newSong domain.Song
-	_, err := insert.Executor().ScanStruct(&newSong)
+	_, err = insert.Executor().ScanStructContext(ctx, &newSong)
 	if err != nil {
 		var pgErr *pq.Error
 		if errors.As(err, &pgErr) && pgErr.Code == domain.CodeUniqueConstraintViolation {
@@ -151,12 +295,15 @@ func (r SongsRepo) Create(groupName, songName string) (domain.Song, error) {
 }
 
 // AddDetails updates the song details in the database based on the provided parameters.
-func (r SongsRepo) AddDetails(songID int32, paramsMap map[string]interface{}) error {
+func (r SongsRepo) AddDetails(ctx context.Context, songID int32, paramsMap map[string]interface{}) (err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.AddDetails")
+	defer end(&err)
+
 	update := r.goquDb.Update(songsTable).
 		Set(paramsMap).
 		Where(goqu.Ex{"id": songID})
 
-	res, err := update.Executor().Exec()
+	res, err := update.Executor().ExecContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -173,11 +320,255 @@ func (r SongsRepo) AddDetails(songID int32, paramsMap map[string]interface{}) er
 	return nil
 }
 
-func (r SongsRepo) getTotalCount(conditions goqu.Ex) (int, error) {
-	query := r.goquDb.Select(goqu.COUNT("id")).From(songsTable).Where(conditions)
+// ApplyBatch applies create, update and delete operations in order using the same queries as Create, UpdateSong and Delete.
+// In atomic mode all operations run in a single transaction, which is rolled back on the first failure and
+// domain.ErrBatchAborted is returned; otherwise every operation is applied independently.
+func (r SongsRepo) ApplyBatch(ctx context.Context, ops []domain.SongOperation, atomic bool) (_ []domain.SongOperationResult, err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.ApplyBatch")
+	defer end(&err)
+
+	results := make([]domain.SongOperationResult, len(ops))
+
+	if !atomic {
+		for i, op := range ops {
+			results[i] = r.applyOperation(ctx, op)
+		}
+
+		return results, nil
+	}
+
+	failed := -1
+
+	err = withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		txRepo := r
+		txRepo.querier = tx
+
+		for i, op := range ops {
+			results[i] = txRepo.applyOperation(ctx, op)
+			if results[i].Err != nil {
+				failed = i
+				return results[i].Err
+			}
+		}
+
+		return nil
+	})
+	if err == nil {
+		return results, nil
+	}
+
+	if failed == -1 {
+		return nil, err
+	}
+
+	for i := range results {
+		switch {
+		case i < failed:
+			results[i] = domain.SongOperationResult{Err: domain.ErrBatchRolledBack}
+		case i > failed:
+			results[i] = domain.SongOperationResult{Err: domain.ErrBatchNotApplied}
+		}
+	}
+
+	return results, domain.ErrBatchAborted
+}
+
+func (r SongsRepo) applyOperation(ctx context.Context, op domain.SongOperation) domain.SongOperationResult {
+	var result domain.SongOperationResult
+
+	switch op.Type {
+	case domain.OpCreate:
+		result.Song, result.Err = r.Create(ctx, op.Group, op.Song)
+	case domain.OpUpdate:
+		result.Song, result.Err = r.UpdateSong(ctx, op.SongID, op.Params)
+	case domain.OpDelete:
+		result.Song.ID = op.SongID
+		result.Err = r.Delete(ctx, op.SongID)
+	default:
+		result.Err = fmt.Errorf("%w: %s", domain.ErrInvalidBatchOperation, op.Type)
+	}
+
+	return result
+}
+
+// ImportSongs runs fn inside a single transaction, passing it a function that inserts a batch of songs
+// according to the conflict policy. The transaction is rolled back if fn returns an error.
+func (r SongsRepo) ImportSongs(ctx context.Context, policy domain.ConflictPolicy, fn func(insert func([]domain.Song) ([]domain.ImportedSong, error)) error) (err error) {
+	ctx, end := startSpan(ctx, "SongsRepo.ImportSongs")
+	defer end(&err)
+
+	return withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		return fn(func(songs []domain.Song) ([]domain.ImportedSong, error) {
+			return r.insertBatch(ctx, tx, songs, policy)
+		})
+	})
+}
+
+func (r SongsRepo) insertBatch(ctx context.Context, tx *goqu.TxDatabase, songs []domain.Song, policy domain.ConflictPolicy) ([]domain.ImportedSong, error) {
+	records := make([]interface{}, len(songs))
+	for i, song := range songs {
+		records[i] = r.toRecord(song)
+	}
+
+	insert := tx.Insert(songsTable).
+		Rows(records...).
+		Returning("id", "group", "song", "release_date", "text", "link", goqu.L("xmax = 0").As("inserted"))
+
+	if policy == domain.ConflictUpdate {
+		insert = insert.OnConflict(goqu.DoUpdate(`"group", song`, goqu.Record{
+			"release_date": goqu.L("COALESCE(EXCLUDED.release_date, songs.release_date)"),
+			"text":         goqu.L("COALESCE(EXCLUDED.text, songs.text)"),
+			"link":         goqu.L("COALESCE(EXCLUDED.link, songs.link)"),
+		}))
+	} else {
+		insert = insert.OnConflict(goqu.DoNothing())
+	}
+
+	var insertedSongs []struct {
+		domain.SongWithNull
+		Inserted bool `db:"inserted"`
+	}
+	if err := insert.Executor().ScanStructsContext(ctx, &insertedSongs); err != nil {
+		return nil, err
+	}
+
+	insertedByKey := make(map[string]domain.ImportedSong, len(insertedSongs))
+	for _, insertedSong := range insertedSongs {
+		status := domain.ImportUpdated
+		if insertedSong.Inserted {
+			status = domain.ImportCreated
+		}
+
+		insertedByKey[insertedSong.Group+"\x00"+insertedSong.Song] = domain.ImportedSong{
+			Song:   r.toSong(insertedSong.SongWithNull),
+			Status: status,
+		}
+	}
+
+	results := make([]domain.ImportedSong, len(songs))
+	for i, song := range songs {
+		result, ok := insertedByKey[song.Group+"\x00"+song.Song]
+		if !ok {
+			status := domain.ImportSkipped
+			if policy == domain.ConflictFail {
+				status = domain.ImportConflict
+			}
+
+			result = domain.ImportedSong{Song: song, Status: status}
+		}
+
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+func (r SongsRepo) toRecord(song domain.Song) goqu.Record {
+	record := goqu.Record{
+		"group":        song.Group,
+		"song":         song.Song,
+		"release_date": nil,
+		"text":         nil,
+		"link":         nil,
+	}
+
+	if !song.ReleaseDate.IsZero() {
+		record["release_date"] = song.ReleaseDate
+	}
+	if song.Text != "" {
+		record["text"] = song.Text
+	}
+	if song.Link != "" {
+		record["link"] = song.Link
+	}
+
+	return record
+}
+
+func (r SongsRepo) filterSongs(query *goqu.SelectDataset, filtersMap map[string]interface{}) (*goqu.SelectDataset, goqu.Ex) {
+	conditions := goqu.Ex{}
+
+	if len(filtersMap) > 0 {
+		for field, value := range filtersMap {
+			if field == "text" && value != "" {
+				words := strings.Fields(value.(string))
+
+				var textConditions []goqu.Expression
+				for _, word := range words {
+					textConditions = append(textConditions, goqu.I("text").ILike("%"+word+"%"))
+				}
+
+				query = query.Where(goqu.And(textConditions...))
+			} else {
+				conditions[field] = value
+			}
+		}
+
+		query = query.Where(conditions)
+	}
+
+	return query, conditions
+}
+
+// listConditions limits a list of songs to the user's favorites if favoritedOnly is set
+// and to songs rated at least minRating if it is positive.
+func (r SongsRepo) listConditions(userID int32, favoritedOnly bool, minRating float64) []exp.Expression {
+	var conditions []exp.Expression
+	if favoritedOnly {
+		conditions = append(conditions, r.isFavorite(userID))
+	}
+	if minRating > 0 {
+		conditions = append(conditions, r.averageRating().Gte(minRating))
+	}
+
+	return conditions
+}
+
+// listColumns selects the columns of songListItem except plays.
+func (r SongsRepo) listColumns(userID int32) []interface{} {
+	return []interface{}{
+		songsTable + ".id", "group", "song", "release_date", "text", "link",
+		r.isFavorite(userID).As("is_favorite"),
+		r.averageRating().As("average_rating"),
+		r.ratingsCount().As("ratings_count"),
+	}
+}
+
+func (r SongsRepo) toListSong(song songListItem) domain.Song {
+	normalizedSong := r.toSong(song.SongWithNull)
+	normalizedSong.IsFavorite = song.IsFavorite
+	normalizedSong.RatingsCount = &song.RatingsCount
+
+	if song.AverageRating.Valid {
+		normalizedSong.AverageRating = &song.AverageRating.Float64
+	}
+	if song.Plays.Valid {
+		normalizedSong.Plays = &song.Plays.Int64
+	}
+
+	return normalizedSong
+}
+
+// songExists returns domain.ErrSongNotFound if there is no song with the given ID.
+func songExists(ctx context.Context, goquDb *goqu.Database, songID int32) error {
+	var id int32
+	found, err := goquDb.From(songsTable).Select("id").Where(goqu.Ex{"id": songID}).ScanValContext(ctx, &id)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%w (id: %d)", domain.ErrSongNotFound, songID)
+	}
+
+	return nil
+}
+
+func (r SongsRepo) getTotalCount(ctx context.Context, db *goqu.Database, conditions goqu.Ex, extraConditions ...exp.Expression) (int, error) {
+	query := db.Select(goqu.COUNT("id")).From(songsTable).Where(conditions).Where(extraConditions...)
 
 	var totalCount int
-	if _, err := query.Executor().ScanVal(&totalCount); err != nil {
+	if _, err := query.Executor().ScanValContext(ctx, &totalCount); err != nil {
 		return 0, err
 	}
 
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/lib/pq"
+	"songs-library-go/internal/domain"
+)
+
+const songTagsTable = "song_tags"
+
+// songTagRow is a tag of a song.
+type songTagRow struct {
+	SongID int32  `db:"song_id"`
+	Tag    string `db:"tag"`
+}
+
+// TagsRepo implements the TagsRepo interface for interacting with the database using goqu.
+// Tags of a song are removed by the database when the song is deleted.
+type TagsRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewTagsRepo creates a new instance of TagsRepo, initializing it with a goqu.Database.
+func NewTagsRepo(db *sql.DB) *TagsRepo {
+	return &TagsRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// GetTags retrieves the tags of a song, which must exist, in alphabetical order.
+func (r TagsRepo) GetTags(ctx context.Context, songID int32) (_ []string, err error) {
+	ctx, end := startSpan(ctx, "TagsRepo.GetTags")
+	defer end(&err)
+
+	if err := songExists(ctx, r.goquDb, songID); err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+
+	query := r.goquDb.From(songTagsTable).Select("tag").Where(goqu.Ex{"song_id": songID}).Order(goqu.C("tag").Asc())
+	if err := query.ScanValsContext(ctx, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// ReplaceTags replaces the tags of a song, which must exist, with the given distinct tags.
+// The song is locked, so it can't be deleted while its tags are replaced.
+func (r TagsRepo) ReplaceTags(ctx context.Context, songID int32, tags []string) (err error) {
+	ctx, end := startSpan(ctx, "TagsRepo.ReplaceTags")
+	defer end(&err)
+
+	return withTx(ctx, r.goquDb, func(tx *goqu.TxDatabase) error {
+		if err := lockSong(ctx, tx, songID); err != nil {
+			return err
+		}
+
+		if _, err := tx.Delete(songTagsTable).Where(goqu.Ex{"song_id": songID}).Executor().ExecContext(ctx); err != nil {
+			return err
+		}
+
+		if len(tags) == 0 {
+			return nil
+		}
+
+		_, err := tx.ExecContext(ctx, `INSERT INTO song_tags (song_id, tag) SELECT $1, unnest($2::varchar[])`, songID, pq.Array(tags))
+		return err
+	})
+}
+
+// ExportTags reads the tags of all songs, keyed by song ID.
+func (r TagsRepo) ExportTags(ctx context.Context) (_ map[int32][]string, err error) {
+	ctx, end := startSpan(ctx, "TagsRepo.ExportTags")
+	defer end(&err)
+
+	var rows []songTagRow
+	if err := r.goquDb.From(songTagsTable).Select("song_id", "tag").ScanStructsContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	tags := make(map[int32][]string)
+	for _, row := range rows {
+		tags[row.SongID] = append(tags[row.SongID], row.Tag)
+	}
+
+	return tags, nil
+}
+
+// lockSong locks the row of a song, which must exist, against deletion until the end of the transaction.
+func lockSong(ctx context.Context, tx *goqu.TxDatabase, songID int32) error {
+	var id int32
+	found, err := tx.From(songsTable).Select("id").Where(goqu.Ex{"id": songID}).ForKeyShare(exp.Wait).ScanValContext(ctx, &id)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%w (id: %d)", domain.ErrSongNotFound, songID)
+	}
+
+	return nil
+}
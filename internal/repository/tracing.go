@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v9"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"songs-library-go/internal/metrics"
+	"songs-library-go/internal/tracing"
+)
+
+// startSpan starts the span of a repository method and times the method for the metrics. The returned function
+// ends both and records err, if any, on the span, so it is meant to be deferred with a named error result.
+func startSpan(ctx context.Context, method string) (context.Context, func(err *error)) {
+	observe := metrics.ObserveQuery(method)
+	ctx, span := tracing.Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(semconv.DBSystemPostgreSQL))
+
+	return ctx, func(err *error) {
+		if err != nil && *err != nil {
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+
+		span.End()
+		observe()
+	}
+}
+
+// withTx runs fn in a transaction bound to ctx, which is committed if fn returns nil and rolled back otherwise.
+func withTx(ctx context.Context, goquDb *goqu.Database, fn func(tx *goqu.TxDatabase) error) error {
+	tx, err := goquDb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Wrap(func() error {
+		return fn(tx)
+	})
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/doug-martin/goqu/v9"
+)
+
+const usersTable = "users"
+
+// UsersRepo stores the users behind authenticated principals.
+type UsersRepo struct {
+	goquDb *goqu.Database
+}
+
+// NewUsersRepo creates a new instance of UsersRepo, initializing it with a goqu.Database.
+func NewUsersRepo(db *sql.DB) *UsersRepo {
+	return &UsersRepo{
+		goquDb: goqu.New("postgres", db),
+	}
+}
+
+// Ensure returns the ID of the user with the given subject, creating the user on first use.
+func (r UsersRepo) Ensure(ctx context.Context, subject string) (_ int32, err error) {
+	ctx, end := startSpan(ctx, "UsersRepo.Ensure")
+	defer end(&err)
+
+	insert := r.goquDb.Insert(usersTable).
+		Rows(goqu.Record{"subject": subject}).
+		OnConflict(goqu.DoUpdate("subject", goqu.Record{"subject": goqu.L("EXCLUDED.subject")})).
+		Returning("id")
+
+	var userID int32
+	if _, err := insert.Executor().ScanValContext(ctx, &userID); err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
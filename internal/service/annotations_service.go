@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+)
+
+// AnnotationsRepo defines methods for storing annotations of song lyrics and votes on them.
+type AnnotationsRepo interface {
+	Create(ctx context.Context, annotation domain.Annotation) (domain.Annotation, error)
+	GetAnnotations(ctx context.Context, songID, userID int32) ([]domain.Annotation, error)
+	UpdateAnchors(ctx context.Context, annotations []domain.Annotation, previousHashes []string) error
+	Vote(ctx context.Context, annotationID, userID int32, value int) (int, error)
+	Delete(ctx context.Context, annotationID, userID int32) error
+}
+
+// SongTextsRepo defines the method for reading the lyrics that annotations are anchored to.
+type SongTextsRepo interface {
+	GetSongText(ctx context.Context, songID int32) (string, error)
+}
+
+// AnnotationsService manages annotations of song lyrics. Anchors are re-mapped lazily: whenever annotations are read,
+// those resolved against an older version of the lyrics are searched in the current lyrics and flagged as stale
+// if their fragment can't be found, so every way of editing lyrics is covered.
+type AnnotationsService struct {
+	repo      AnnotationsRepo
+	songsRepo SongTextsRepo
+}
+
+// NewAnnotationsService initializes and returns a new instance of AnnotationsService with the provided repositories.
+func NewAnnotationsService(repo AnnotationsRepo, songsRepo SongTextsRepo) *AnnotationsService {
+	return &AnnotationsService{
+		repo:      repo,
+		songsRepo: songsRepo,
+	}
+}
+
+// Create anchors a new annotation by the user to a range of a verse of the current lyrics.
+func (s AnnotationsService) Create(ctx context.Context, userID, songID int32, annotationInput dto.CreateAnnotationDto) (domain.Annotation, error) {
+	songText, err := s.songsRepo.GetSongText(ctx, songID)
+	if err != nil {
+		return domain.Annotation{}, err
+	}
+
+	anchor := domain.Anchor{
+		VerseIndex: annotationInput.VerseIndex,
+		Unit:       domain.AnchorUnit(annotationInput.Unit),
+		Start:      annotationInput.Start,
+		End:        annotationInput.End,
+	}
+
+	fragment, ok := anchor.Fragment(domain.SplitVerses(songText))
+	if !ok {
+		return domain.Annotation{}, fmt.Errorf("%w (verse: %d, %s range: %d-%d)", domain.ErrInvalidAnchor, anchor.VerseIndex, anchor.Unit, anchor.Start, anchor.End)
+	}
+	anchor.Text = fragment
+
+	return s.repo.Create(ctx, domain.Annotation{
+		SongID:   songID,
+		UserID:   userID,
+		Body:     annotationInput.Body,
+		Anchor:   anchor,
+		TextHash: domain.HashText(songText),
+	})
+}
+
+// GetAnnotations retrieves the annotations of the verses on a page of the song text, paginated like the song text,
+// together with the total number of pages.
+func (s AnnotationsService) GetAnnotations(ctx context.Context, userID, songID int32, params dto.PaginationParamsDto) ([]domain.Annotation, int, error) {
+	songText, err := s.songsRepo.GetSongText(ctx, songID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	annotations, err := s.repo.GetAnnotations(ctx, songID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	verses := domain.SplitVerses(songText)
+	annotations = s.remapAnchors(ctx, annotations, verses, domain.HashText(songText))
+
+	totalPages := int(math.Ceil(float64(len(verses)) / float64(params.Limit)))
+	start := (params.Page - 1) * params.Limit
+	end := start + params.Limit
+
+	pageAnnotations := make([]domain.Annotation, 0)
+	for _, annotation := range annotations {
+		if annotation.VerseIndex >= start && annotation.VerseIndex < end {
+			pageAnnotations = append(pageAnnotations, annotation)
+		}
+	}
+
+	return pageAnnotations, totalPages, nil
+}
+
+// Vote sets the vote of the user on an annotation and returns its new score.
+func (s AnnotationsService) Vote(ctx context.Context, userID, annotationID int32, voteInput dto.VoteDto) (int, error) {
+	return s.repo.Vote(ctx, annotationID, userID, voteInput.Value)
+}
+
+// Delete removes an annotation written by the user.
+func (s AnnotationsService) Delete(ctx context.Context, userID, annotationID int32) error {
+	return s.repo.Delete(ctx, annotationID, userID)
+}
+
+// remapAnchors re-maps the anchors of annotations resolved against other lyrics than those with textHash and stores them.
+// Failing to store them is only logged, since they are re-mapped again on the next read.
+func (s AnnotationsService) remapAnchors(ctx context.Context, annotations []domain.Annotation, verses []string, textHash string) []domain.Annotation {
+	var remapped []domain.Annotation
+	var previousHashes []string
+
+	for i, annotation := range annotations {
+		if annotation.TextHash == textHash {
+			continue
+		}
+
+		anchor, found := annotation.Remap(verses)
+
+		previousHashes = append(previousHashes, annotation.TextHash)
+		annotation.Anchor = anchor
+		annotation.Stale = !found
+		annotation.TextHash = textHash
+
+		annotations[i] = annotation
+		remapped = append(remapped, annotation)
+	}
+
+	if len(remapped) == 0 {
+		return annotations
+	}
+
+	if err := s.repo.UpdateAnchors(ctx, remapped, previousHashes); err != nil {
+		logging.FromContext(ctx).WithError(err).Error(domain.ErrRemappingAnchors)
+	}
+
+	return annotations
+}
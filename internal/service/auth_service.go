@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"songs-library-go/internal/domain"
+	"strings"
+	"sync"
+)
+
+// APIKeysRepo defines methods for storing, looking up and revoking hashed API keys.
+type APIKeysRepo interface {
+	Create(ctx context.Context, key domain.APIKey) (domain.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (domain.APIKey, error)
+	List(ctx context.Context) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, keyID int32) error
+}
+
+// UsersRepo defines methods for resolving the users behind authenticated principals.
+type UsersRepo interface {
+	Ensure(ctx context.Context, subject string) (int32, error)
+}
+
+const (
+	apiKeyPrefix      = "sl_"
+	apiKeySecretBytes = 32
+	apiKeyShownChars  = 8
+)
+
+// JWTKeys holds the locally configured keys used to validate JWT bearer tokens.
+// Tokens are rejected when neither an HMAC secret nor an RSA public key is configured.
+type JWTKeys struct {
+	HMACSecret   []byte
+	RSAPublicKey *rsa.PublicKey
+	Issuer       string
+	Audience     string
+}
+
+// AuthService issues and revokes API keys and authenticates requests with API keys or JWT bearer tokens.
+// User IDs of authenticated principals are cached, since users are never deleted.
+type AuthService struct {
+	repo      APIKeysRepo
+	usersRepo UsersRepo
+	jwtKeys   JWTKeys
+	userIDs   *sync.Map
+}
+
+// NewAuthService initializes and returns a new instance of AuthService with the provided repositories and JWT keys.
+func NewAuthService(repo APIKeysRepo, usersRepo UsersRepo, jwtKeys JWTKeys) *AuthService {
+	return &AuthService{
+		repo:      repo,
+		usersRepo: usersRepo,
+		jwtKeys:   jwtKeys,
+		userIDs:   &sync.Map{},
+	}
+}
+
+// jwtClaims are the registered claims of a JWT bearer token together with the role of its subject.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Role domain.Role `json:"role"`
+}
+
+// IssueKey generates a new API key with the given name and role. The returned secret is shown only once, since only its hash is stored.
+func (s AuthService) IssueKey(ctx context.Context, name string, role domain.Role) (domain.APIKey, string, error) {
+	if !role.IsValid() {
+		return domain.APIKey{}, "", fmt.Errorf("%w: %s", domain.ErrInvalidRole, role)
+	}
+
+	secretBytes := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return domain.APIKey{}, "", err
+	}
+
+	secret := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	key, err := s.repo.Create(ctx, domain.APIKey{
+		Name:   name,
+		Prefix: secret[:len(apiKeyPrefix)+apiKeyShownChars],
+		Hash:   hashAPIKey(secret),
+		Role:   role,
+	})
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+
+	return key, secret, nil
+}
+
+// RevokeKey revokes the API key with the given ID.
+func (s AuthService) RevokeKey(ctx context.Context, keyID int32) error {
+	return s.repo.Revoke(ctx, keyID)
+}
+
+// ListKeys returns all issued API keys.
+func (s AuthService) ListKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+// Authenticate resolves the principal for an API key or a JWT bearer token.
+// It returns domain.ErrInvalidCredentials for unknown, revoked or otherwise invalid credentials.
+func (s AuthService) Authenticate(ctx context.Context, credential string) (domain.Principal, error) {
+	var principal domain.Principal
+	var err error
+
+	if strings.HasPrefix(credential, apiKeyPrefix) {
+		principal, err = s.authenticateAPIKey(ctx, credential)
+	} else {
+		principal, err = s.authenticateJWT(credential)
+	}
+
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	principal.UserID, err = s.userID(ctx, principal)
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	return principal, nil
+}
+
+// userID returns the ID of the user owned by the identity of the principal, so API keys sharing a name don't share a user.
+func (s AuthService) userID(ctx context.Context, principal domain.Principal) (int32, error) {
+	subject := principal.Identity()
+
+	if userID, ok := s.userIDs.Load(subject); ok {
+		return userID.(int32), nil
+	}
+
+	userID, err := s.usersRepo.Ensure(ctx, subject)
+	if err != nil {
+		return 0, err
+	}
+
+	s.userIDs.Store(subject, userID)
+
+	return userID, nil
+}
+
+func (s AuthService) authenticateAPIKey(ctx context.Context, secret string) (domain.Principal, error) {
+	key, err := s.repo.GetByHash(ctx, hashAPIKey(secret))
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return domain.Principal{}, domain.ErrInvalidCredentials
+		}
+
+		return domain.Principal{}, err
+	}
+
+	if key.RevokedAt != nil {
+		return domain.Principal{}, fmt.Errorf("%w: API key %d revoked", domain.ErrInvalidCredentials, key.ID)
+	}
+
+	return domain.Principal{
+		Type:    domain.PrincipalAPIKey,
+		Subject: key.Name,
+		KeyID:   key.ID,
+		Role:    key.Role,
+	}, nil
+}
+
+func (s AuthService) authenticateJWT(tokenString string) (domain.Principal, error) {
+	var methods []string
+	if len(s.jwtKeys.HMACSecret) > 0 {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if s.jwtKeys.RSAPublicKey != nil {
+		methods = append(methods, "RS256", "RS384", "RS512")
+	}
+
+	if len(methods) == 0 {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if s.jwtKeys.Issuer != "" {
+		options = append(options, jwt.WithIssuer(s.jwtKeys.Issuer))
+	}
+	if s.jwtKeys.Audience != "" {
+		options = append(options, jwt.WithAudience(s.jwtKeys.Audience))
+	}
+
+	var claims jwtClaims
+	if _, err := jwt.ParseWithClaims(tokenString, &claims, s.jwtKey, options...); err != nil {
+		return domain.Principal{}, fmt.Errorf("%w: %s", domain.ErrInvalidCredentials, err)
+	}
+
+	if claims.Subject == "" {
+		return domain.Principal{}, fmt.Errorf("%w: missing sub claim", domain.ErrInvalidCredentials)
+	}
+
+	// Tokens without a role claim get the least privileged role.
+	if claims.Role == "" {
+		claims.Role = domain.RoleViewer
+	}
+
+	if !claims.Role.IsValid() {
+		return domain.Principal{}, fmt.Errorf("%w: unknown role %q", domain.ErrInvalidCredentials, claims.Role)
+	}
+
+	return domain.Principal{
+		Type:    domain.PrincipalJWT,
+		Subject: claims.Subject,
+		Role:    claims.Role,
+	}, nil
+}
+
+func (s AuthService) jwtKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return s.jwtKeys.HMACSecret, nil
+	case *jwt.SigningMethodRSA:
+		return s.jwtKeys.RSAPublicKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+func hashAPIKey(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
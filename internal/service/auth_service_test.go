@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"songs-library-go/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryAPIKeysRepo stores API keys in memory, looked up by their hash like the repository.
+type memoryAPIKeysRepo struct {
+	APIKeysRepo
+	keys []domain.APIKey
+}
+
+func (r *memoryAPIKeysRepo) Create(_ context.Context, key domain.APIKey) (domain.APIKey, error) {
+	key.ID = int32(len(r.keys) + 1)
+	r.keys = append(r.keys, key)
+	return key, nil
+}
+
+func (r *memoryAPIKeysRepo) GetByHash(_ context.Context, hash string) (domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+
+	return domain.APIKey{}, domain.ErrAPIKeyNotFound
+}
+
+func (r *memoryAPIKeysRepo) Revoke(_ context.Context, keyID int32) error {
+	now := time.Now()
+	r.keys[keyID-1].RevokedAt = &now
+	return nil
+}
+
+// memoryUsersRepo assigns sequential user IDs to subjects.
+type memoryUsersRepo struct {
+	users map[string]int32
+}
+
+func (r *memoryUsersRepo) Ensure(_ context.Context, subject string) (int32, error) {
+	if r.users == nil {
+		r.users = make(map[string]int32)
+	}
+
+	if _, ok := r.users[subject]; !ok {
+		r.users[subject] = int32(len(r.users) + 1)
+	}
+
+	return r.users[subject], nil
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	repo := &memoryAPIKeysRepo{}
+	s := NewAuthService(repo, &memoryUsersRepo{}, JWTKeys{})
+
+	key, secret, err := s.IssueKey(context.Background(), "ci", domain.RoleEditor)
+	if err != nil {
+		t.Fatalf("IssueKey() error = %v", err)
+	}
+
+	if !strings.HasPrefix(secret, apiKeyPrefix) || !strings.HasPrefix(secret, key.Prefix) {
+		t.Errorf("secret %q, want it to start with %q and the shown prefix %q", secret, apiKeyPrefix, key.Prefix)
+	}
+
+	if repo.keys[0].Hash != hashAPIKey(secret) || strings.Contains(repo.keys[0].Hash, secret) {
+		t.Errorf("stored hash = %q, want the SHA-256 hash of the secret", repo.keys[0].Hash)
+	}
+
+	revoked, revokedSecret, err := s.IssueKey(context.Background(), "old", domain.RoleAdmin)
+	if err != nil {
+		t.Fatalf("IssueKey() error = %v", err)
+	}
+
+	if err := s.RevokeKey(context.Background(), revoked.ID); err != nil {
+		t.Fatalf("RevokeKey() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		want    domain.Principal
+		wantErr error
+	}{
+		{
+			name:   "issued key",
+			secret: secret,
+			want:   domain.Principal{Type: domain.PrincipalAPIKey, Subject: "ci", KeyID: key.ID, UserID: 1, Role: domain.RoleEditor},
+		},
+		{name: "revoked key", secret: revokedSecret, wantErr: domain.ErrInvalidCredentials},
+		{name: "unknown key", secret: apiKeyPrefix + "unknown", wantErr: domain.ErrInvalidCredentials},
+		{name: "issued key with a changed character", secret: secret[:len(secret)-1] + "_", wantErr: domain.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Authenticate(context.Background(), tt.secret)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Authenticate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueKeyWithInvalidRole(t *testing.T) {
+	repo := &memoryAPIKeysRepo{}
+
+	_, _, err := NewAuthService(repo, &memoryUsersRepo{}, JWTKeys{}).IssueKey(context.Background(), "ci", "owner")
+	if !errors.Is(err, domain.ErrInvalidRole) {
+		t.Fatalf("IssueKey() error = %v, want %v", err, domain.ErrInvalidRole)
+	}
+
+	if len(repo.keys) != 0 {
+		t.Errorf("stored %d keys, want none", len(repo.keys))
+	}
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	hmacSecret := []byte("hmac-secret")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("marshaling RSA public key: %v", err)
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})
+
+	bothKeys := JWTKeys{HMACSecret: hmacSecret, RSAPublicKey: &rsaKey.PublicKey, Issuer: "https://auth.example", Audience: "songs"}
+	rsaOnly := JWTKeys{RSAPublicKey: &rsaKey.PublicKey}
+
+	now := time.Now()
+	validClaims := func() jwtClaims {
+		return jwtClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "alice",
+				Issuer:    "https://auth.example",
+				Audience:  jwt.ClaimStrings{"songs"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+			Role: domain.RoleEditor,
+		}
+	}
+
+	withClaims := func(change func(*jwtClaims)) jwtClaims {
+		claims := validClaims()
+		change(&claims)
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		keys    JWTKeys
+		method  jwt.SigningMethod
+		key     interface{}
+		claims  jwtClaims
+		want    domain.Principal
+		wantErr error
+	}{
+		{
+			name:   "HS256",
+			keys:   bothKeys,
+			method: jwt.SigningMethodHS256,
+			key:    hmacSecret,
+			claims: validClaims(),
+			want:   domain.Principal{Type: domain.PrincipalJWT, Subject: "alice", UserID: 1, Role: domain.RoleEditor},
+		},
+		{
+			name:   "RS256",
+			keys:   bothKeys,
+			method: jwt.SigningMethodRS256,
+			key:    rsaKey,
+			claims: validClaims(),
+			want:   domain.Principal{Type: domain.PrincipalJWT, Subject: "alice", UserID: 1, Role: domain.RoleEditor},
+		},
+		{
+			name:   "without a role",
+			keys:   rsaOnly,
+			method: jwt.SigningMethodRS512,
+			key:    rsaKey,
+			claims: withClaims(func(c *jwtClaims) { c.Role = "" }),
+			want:   domain.Principal{Type: domain.PrincipalJWT, Subject: "alice", UserID: 1, Role: domain.RoleViewer},
+		},
+		{
+			name:    "HS256 when only an RSA key is configured",
+			keys:    rsaOnly,
+			method:  jwt.SigningMethodHS256,
+			key:     publicKeyPEM,
+			claims:  validClaims(),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "HS256 signed with the RSA public key",
+			keys:    bothKeys,
+			method:  jwt.SigningMethodHS256,
+			key:     publicKeyPEM,
+			claims:  validClaims(),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "alg none",
+			keys:    bothKeys,
+			method:  jwt.SigningMethodNone,
+			key:     jwt.UnsafeAllowNoneSignatureType,
+			claims:  validClaims(),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "signed with another RSA key",
+			keys:    rsaOnly,
+			method:  jwt.SigningMethodRS256,
+			key:     otherRSAKey(t),
+			claims:  validClaims(),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "no keys configured",
+			method:  jwt.SigningMethodHS256,
+			key:     hmacSecret,
+			claims:  validClaims(),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "expired",
+			keys:    bothKeys,
+			method:  jwt.SigningMethodHS256,
+			key:     hmacSecret,
+			claims:  withClaims(func(c *jwtClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) }),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "without expiration",
+			keys:    bothKeys,
+			method:  jwt.SigningMethodHS256,
+			key:     hmacSecret,
+			claims:  withClaims(func(c *jwtClaims) { c.ExpiresAt = nil }),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "not yet valid",
+			keys:    bothKeys,
+			method:  jwt.SigningMethodHS256,
+			key:     hmacSecret,
+			claims:  withClaims(func(c *jwtClaims) { c.NotBefore = jwt.NewNumericDate(now.Add(time.Minute)) }),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "another issuer",
+			keys:    bothKeys,
+			method:  jwt.SigningMethodHS256,
+			key:     hmacSecret,
+			claims:  withClaims(func(c *jwtClaims) { c.Issuer = "https://evil.example" }),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "another audience",
+			keys:    bothKeys,
+			method:  jwt.SigningMethodHS256,
+			key:     hmacSecret,
+			claims:  withClaims(func(c *jwtClaims) { c.Audience = jwt.ClaimStrings{"billing"} }),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "without a subject",
+			keys:    bothKeys,
+			method:  jwt.SigningMethodHS256,
+			key:     hmacSecret,
+			claims:  withClaims(func(c *jwtClaims) { c.Subject = "" }),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+		{
+			name:    "unknown role",
+			keys:    bothKeys,
+			method:  jwt.SigningMethodHS256,
+			key:     hmacSecret,
+			claims:  withClaims(func(c *jwtClaims) { c.Role = "owner" }),
+			wantErr: domain.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwt.NewWithClaims(tt.method, tt.claims).SignedString(tt.key)
+			if err != nil {
+				t.Fatalf("signing token: %v", err)
+			}
+
+			got, err := NewAuthService(&memoryAPIKeysRepo{}, &memoryUsersRepo{}, tt.keys).Authenticate(context.Background(), token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Authenticate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateUsesUserOfIdentity(t *testing.T) {
+	repo := &memoryAPIKeysRepo{}
+	s := NewAuthService(repo, &memoryUsersRepo{}, JWTKeys{})
+
+	// Names of API keys aren't unique, so keys sharing a name must not share a user.
+	_, first, _ := s.IssueKey(context.Background(), "ci", domain.RoleViewer)
+	_, second, _ := s.IssueKey(context.Background(), "ci", domain.RoleViewer)
+
+	firstPrincipal, err := s.Authenticate(context.Background(), first)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	secondPrincipal, err := s.Authenticate(context.Background(), second)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	if firstPrincipal.UserID == secondPrincipal.UserID {
+		t.Errorf("keys sharing a name got the same user %d", firstPrincipal.UserID)
+	}
+}
+
+func otherRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+
+	return key
+}
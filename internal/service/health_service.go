@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"songs-library-go/internal/domain"
+	"sync"
+	"time"
+)
+
+// HealthRepo defines methods for checking the database.
+type HealthRepo interface {
+	Ping(ctx context.Context) error
+	CheckMigrations(ctx context.Context) error
+}
+
+// healthCheckTimeout bounds every readiness check, so a hanging dependency can't hang the probe.
+const healthCheckTimeout = 2 * time.Second
+
+// healthCheck checks a dependency. Only critical checks decide whether the server is ready.
+type healthCheck struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+// HealthService checks the dependencies of the server for the readiness probe.
+type HealthService struct {
+	checks []healthCheck
+}
+
+// NewHealthService initializes and returns a new instance of HealthService that checks the database, the migrations
+// and, as an optional dependency, the music info API used for enrichment if it is configured.
+func NewHealthService(repo HealthRepo, musicInfoAPIURL string) *HealthService {
+	checks := []healthCheck{
+		{name: "database", critical: true, check: repo.Ping},
+		{name: "migrations", critical: true, check: repo.CheckMigrations},
+	}
+
+	if musicInfoAPIURL != "" {
+		checks = append(checks, healthCheck{name: "music_info_api", critical: false, check: reachable(musicInfoAPIURL)})
+	}
+
+	return &HealthService{checks: checks}
+}
+
+// Readiness runs all checks concurrently. The report is down if a critical check fails
+// and degraded if only optional checks fail.
+func (s HealthService) Readiness(ctx context.Context) domain.HealthReport {
+	report := domain.HealthReport{
+		Status: domain.HealthUp,
+		Checks: make([]domain.HealthCheck, len(s.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = runHealthCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, check := range report.Checks {
+		if check.Status == domain.HealthUp {
+			continue
+		}
+
+		if check.Critical {
+			report.Status = domain.HealthDown
+		} else if report.Status == domain.HealthUp {
+			report.Status = domain.HealthDegraded
+		}
+	}
+
+	return report
+}
+
+func runHealthCheck(ctx context.Context, check healthCheck) domain.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.check(ctx)
+
+	result := domain.HealthCheck{
+		Name:     check.name,
+		Critical: check.critical,
+		Status:   domain.HealthUp,
+		Latency:  time.Since(start),
+	}
+
+	if err != nil {
+		result.Status = domain.HealthDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// reachable checks that the server at url responds. Any response but a server error counts,
+// since the API has no dedicated health endpoint.
+func reachable(url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%w (status: %d)", domain.ErrProviderResponse, resp.StatusCode)
+		}
+
+		return nil
+	}
+}
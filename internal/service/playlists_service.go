@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+)
+
+// PlaylistsRepo defines methods for storing playlists and their ordered entries.
+type PlaylistsRepo interface {
+	Create(ctx context.Context, playlist domain.Playlist) (domain.Playlist, error)
+	GetPlaylists(ctx context.Context, userID int32, page, limit int) ([]domain.Playlist, int, error)
+	GetPlaylist(ctx context.Context, playlistID, userID int32) (domain.Playlist, error)
+	GetEntries(ctx context.Context, playlistID int32, page, limit int) ([]domain.PlaylistEntry, int, error)
+	Update(ctx context.Context, playlist domain.Playlist) (domain.Playlist, error)
+	Delete(ctx context.Context, playlistID, userID int32) error
+	AddEntry(ctx context.Context, playlistID, userID, songID int32, position int) (domain.PlaylistEntry, error)
+	RemoveEntry(ctx context.Context, playlistID, userID, entryID int32) error
+	ReorderEntries(ctx context.Context, playlistID, userID int32, entryIDs []int32) error
+}
+
+// PlaylistsService manages playlists of users. Playlists can only be changed by their owner,
+// and private playlists of other users are reported as not found.
+type PlaylistsService struct {
+	repo PlaylistsRepo
+}
+
+// NewPlaylistsService initializes and returns a new instance of PlaylistsService with the provided repository.
+func NewPlaylistsService(repo PlaylistsRepo) *PlaylistsService {
+	return &PlaylistsService{
+		repo: repo,
+	}
+}
+
+// GetPlaylists retrieves a paginated list of the playlists owned by the user.
+func (s PlaylistsService) GetPlaylists(ctx context.Context, userID int32, params dto.PaginationParamsDto) ([]domain.Playlist, int, error) {
+	return s.repo.GetPlaylists(ctx, userID, params.Page, params.Limit)
+}
+
+// GetPlaylist retrieves a playlist owned by the user or public together with a page of its entries.
+func (s PlaylistsService) GetPlaylist(ctx context.Context, userID, playlistID int32, params dto.PaginationParamsDto) (domain.Playlist, []domain.PlaylistEntry, int, error) {
+	playlist, err := s.repo.GetPlaylist(ctx, playlistID, userID)
+	if err != nil {
+		return domain.Playlist{}, nil, 0, err
+	}
+
+	entries, totalPages, err := s.repo.GetEntries(ctx, playlistID, params.Page, params.Limit)
+	if err != nil {
+		return domain.Playlist{}, nil, 0, err
+	}
+
+	return playlist, entries, totalPages, nil
+}
+
+// Create adds a new playlist owned by the user.
+func (s PlaylistsService) Create(ctx context.Context, userID int32, playlistInput dto.PlaylistInputDto) (domain.Playlist, error) {
+	return s.repo.Create(ctx, s.toPlaylist(userID, 0, playlistInput))
+}
+
+// Replace replaces the name, description and visibility of a playlist owned by the user.
+func (s PlaylistsService) Replace(ctx context.Context, userID, playlistID int32, playlistInput dto.PlaylistInputDto) (domain.Playlist, error) {
+	return s.repo.Update(ctx, s.toPlaylist(userID, playlistID, playlistInput))
+}
+
+// Delete removes a playlist owned by the user.
+func (s PlaylistsService) Delete(ctx context.Context, userID, playlistID int32) error {
+	return s.repo.Delete(ctx, playlistID, userID)
+}
+
+// AddEntry adds a song to a playlist owned by the user.
+func (s PlaylistsService) AddEntry(ctx context.Context, userID, playlistID int32, entryInput dto.AddPlaylistEntryDto) (domain.PlaylistEntry, error) {
+	return s.repo.AddEntry(ctx, playlistID, userID, entryInput.SongID, entryInput.Position)
+}
+
+// RemoveEntry removes an entry from a playlist owned by the user.
+func (s PlaylistsService) RemoveEntry(ctx context.Context, userID, playlistID, entryID int32) error {
+	return s.repo.RemoveEntry(ctx, playlistID, userID, entryID)
+}
+
+// Reorder sets the order of all entries of a playlist owned by the user.
+func (s PlaylistsService) Reorder(ctx context.Context, userID, playlistID int32, reorderInput dto.ReorderPlaylistDto) error {
+	return s.repo.ReorderEntries(ctx, playlistID, userID, reorderInput.EntryIDs)
+}
+
+func (s PlaylistsService) toPlaylist(userID, playlistID int32, playlistInput dto.PlaylistInputDto) domain.Playlist {
+	return domain.Playlist{
+		ID:          playlistID,
+		UserID:      userID,
+		Name:        playlistInput.Name,
+		Description: playlistInput.Description,
+		IsPublic:    playlistInput.IsPublic,
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"time"
+)
+
+// PlaysRepo defines methods for storing plays and reading the daily play counters.
+type PlaysRepo interface {
+	SongExists(ctx context.Context, songID int32) error
+	RecordPlays(ctx context.Context, plays []domain.Play) error
+	GetChart(ctx context.Context, since time.Time, limit int) ([]domain.ChartEntry, error)
+}
+
+const (
+	playsBufferSize = 10000
+	maxPlaysBatch   = 1000
+)
+
+// PlaysService records plays of songs and ranks songs by their plays.
+// Plays are queued in memory and written in batches by FlushPeriodically, so a burst of plays
+// results in a few aggregated writes instead of a write per play.
+type PlaysService struct {
+	repo  PlaysRepo
+	plays chan domain.Play
+}
+
+// NewPlaysService initializes and returns a new instance of PlaysService with the provided repository.
+func NewPlaysService(repo PlaysRepo) *PlaysService {
+	return &PlaysService{
+		repo:  repo,
+		plays: make(chan domain.Play, playsBufferSize),
+	}
+}
+
+// Record queues a play of the song by the user. It returns domain.ErrPlaysBufferFull
+// when plays are queued faster than they can be written.
+func (s PlaysService) Record(ctx context.Context, userID, songID int32, playInput dto.RecordPlayDto) error {
+	if err := s.repo.SongExists(ctx, songID); err != nil {
+		return err
+	}
+
+	play := domain.Play{
+		SongID:   songID,
+		UserID:   userID,
+		Source:   playInput.Source,
+		PlayedAt: playInput.PlayedAt,
+	}
+
+	if play.PlayedAt.IsZero() {
+		play.PlayedAt = time.Now()
+	}
+
+	select {
+	case s.plays <- play:
+		return nil
+	default:
+		return domain.ErrPlaysBufferFull
+	}
+}
+
+// FlushPeriodically writes queued plays every interval, or as soon as a full batch is queued, until ctx is done.
+// Then it writes the plays still queued, so no accepted play is lost on shutdown. Batches that can't be written
+// are logged and dropped.
+func (s PlaysService) FlushPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Batches are written even once ctx is done, since the queued plays are written on shutdown.
+	writeCtx := context.WithoutCancel(ctx)
+
+	batch := make([]domain.Play, 0, maxPlaysBatch)
+
+	for {
+		select {
+		case play := <-s.plays:
+			batch = append(batch, play)
+			if len(batch) < maxPlaysBatch {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-ctx.Done():
+			s.flushQueued(writeCtx, batch)
+			return
+		}
+
+		s.writeBatch(writeCtx, batch)
+		batch = batch[:0]
+	}
+}
+
+// flushQueued writes the batch and all plays still queued.
+func (s PlaysService) flushQueued(ctx context.Context, batch []domain.Play) {
+	for {
+		select {
+		case play := <-s.plays:
+			batch = append(batch, play)
+			if len(batch) < maxPlaysBatch {
+				continue
+			}
+		default:
+			if len(batch) > 0 {
+				s.writeBatch(ctx, batch)
+			}
+			return
+		}
+
+		s.writeBatch(ctx, batch)
+		batch = batch[:0]
+	}
+}
+
+func (s PlaysService) writeBatch(ctx context.Context, batch []domain.Play) {
+	if err := s.repo.RecordPlays(ctx, batch); err != nil {
+		logging.FromContext(ctx).WithError(err).Errorf("%s (plays: %d)", domain.ErrRecordingPlays, len(batch))
+	}
+}
+
+// GetChart retrieves the most played songs in the period of params.
+func (s PlaysService) GetChart(ctx context.Context, params dto.ChartParamsDto) ([]domain.ChartEntry, error) {
+	days, err := domain.ParsePeriod(params.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetChart(ctx, domain.PeriodStart(time.Now(), days), params.Limit)
+}
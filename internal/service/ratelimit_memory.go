@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"songs-library-go/internal/domain"
+	"sync"
+	"time"
+)
+
+// MemoryRateLimitStore keeps the token buckets in memory, so the limits hold per instance.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]domain.TokenBucket
+}
+
+// NewMemoryRateLimitStore initializes and returns a new empty instance of MemoryRateLimitStore.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]domain.TokenBucket),
+	}
+}
+
+// Take takes a token from the bucket of the key for a request at now.
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit domain.RateLimit, now time.Time) (domain.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, result := s.buckets[key].Take(limit, now)
+	s.buckets[key] = bucket
+
+	return result, nil
+}
+
+// PurgeIdle removes buckets that haven't been updated for idle.
+func (s *MemoryRateLimitStore) PurgeIdle(_ context.Context, idle time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-idle)
+	for key, bucket := range s.buckets {
+		if bucket.UpdatedAt.Before(cutoff) {
+			delete(s.buckets, key)
+		}
+	}
+
+	return nil
+}
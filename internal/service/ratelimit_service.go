@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"songs-library-go/internal/domain"
+	"time"
+)
+
+// RateLimitStore defines methods for taking tokens from the token buckets of clients and purging idle buckets.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (domain.RateLimitResult, error)
+	PurgeIdle(ctx context.Context, idle time.Duration) error
+}
+
+const (
+	defaultRateLimitScope  = "default"
+	ipRateLimitScope       = "ip"
+	rateLimitPurgeInterval = 10 * time.Minute
+	errPurgingRateLimits   = "error purging idle rate limit buckets"
+)
+
+// RateLimitService limits the requests of every client with token buckets. A route with its own limit has a bucket per client,
+// while the other routes share the default bucket of the client. Every IP address also has a bucket for all its requests,
+// which limits requests before they are authenticated.
+type RateLimitService struct {
+	store        RateLimitStore
+	defaultLimit domain.RateLimit
+	routeLimits  map[string]domain.RateLimit
+	ipLimit      domain.RateLimit
+}
+
+// NewRateLimitService initializes and returns a new instance of RateLimitService with the provided store, the default limit,
+// the limits of routes keyed by method and route pattern, such as "GET /songs", and the limit of IP addresses.
+func NewRateLimitService(store RateLimitStore, defaultLimit domain.RateLimit, routeLimits map[string]domain.RateLimit, ipLimit domain.RateLimit) *RateLimitService {
+	return &RateLimitService{
+		store:        store,
+		defaultLimit: defaultLimit,
+		routeLimits:  routeLimits,
+		ipLimit:      ipLimit,
+	}
+}
+
+// Allow takes a token of the client for a request of the route, keyed by method and route pattern.
+func (s RateLimitService) Allow(ctx context.Context, route, client string) (domain.RateLimitResult, error) {
+	scope := route
+	limit, ok := s.routeLimits[route]
+	if !ok {
+		scope = defaultRateLimitScope
+		limit = s.defaultLimit
+	}
+
+	return s.store.Take(ctx, scope+"|"+client, limit, time.Now())
+}
+
+// AllowIP takes a token of the IP address for a request of any route.
+func (s RateLimitService) AllowIP(ctx context.Context, ip string) (domain.RateLimitResult, error) {
+	return s.store.Take(ctx, ipRateLimitScope+"|"+ip, s.ipLimit, time.Now())
+}
+
+// PurgeIdle periodically removes the buckets idle for longer than the longest period until ctx is done.
+// Those buckets are full, so removing them changes no limit.
+func (s RateLimitService) PurgeIdle(ctx context.Context) {
+	idle := max(s.defaultLimit.Period, s.ipLimit.Period)
+	for _, limit := range s.routeLimits {
+		idle = max(idle, limit.Period)
+	}
+
+	ticker := time.NewTicker(rateLimitPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.store.PurgeIdle(ctx, idle); err != nil {
+				log.WithError(err).Error(errPurgingRateLimits)
+			}
+		}
+	}
+}
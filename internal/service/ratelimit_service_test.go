@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"songs-library-go/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestRateLimitServiceScopes(t *testing.T) {
+	s := NewRateLimitService(
+		NewMemoryRateLimitStore(),
+		domain.RateLimit{Requests: 2, Period: time.Minute},
+		map[string]domain.RateLimit{"POST /songs/import": {Requests: 1, Period: time.Minute}},
+		domain.RateLimit{Requests: 3, Period: time.Minute},
+	)
+
+	// Every request takes a token from the bucket left by the previous requests.
+	requests := []struct {
+		name        string
+		allow       func(ctx context.Context) (domain.RateLimitResult, error)
+		wantAllowed bool
+		wantLimit   int
+	}{
+		{
+			name: "route without a limit of its own",
+			allow: func(ctx context.Context) (domain.RateLimitResult, error) {
+				return s.Allow(ctx, "GET /songs", "api_key#1")
+			},
+			wantAllowed: true,
+			wantLimit:   2,
+		},
+		{
+			name: "another route shares the default bucket",
+			allow: func(ctx context.Context) (domain.RateLimitResult, error) {
+				return s.Allow(ctx, "GET /charts", "api_key#1")
+			},
+			wantAllowed: true,
+			wantLimit:   2,
+		},
+		{
+			name: "default bucket is empty",
+			allow: func(ctx context.Context) (domain.RateLimitResult, error) {
+				return s.Allow(ctx, "GET /songs", "api_key#1")
+			},
+			wantAllowed: false,
+			wantLimit:   2,
+		},
+		{
+			name: "route with its own limit has its own bucket",
+			allow: func(ctx context.Context) (domain.RateLimitResult, error) {
+				return s.Allow(ctx, "POST /songs/import", "api_key#1")
+			},
+			wantAllowed: true,
+			wantLimit:   1,
+		},
+		{
+			name: "route bucket is empty",
+			allow: func(ctx context.Context) (domain.RateLimitResult, error) {
+				return s.Allow(ctx, "POST /songs/import", "api_key#1")
+			},
+			wantAllowed: false,
+			wantLimit:   1,
+		},
+		{
+			name: "another client has its own buckets",
+			allow: func(ctx context.Context) (domain.RateLimitResult, error) {
+				return s.Allow(ctx, "GET /songs", "jwt:alice")
+			},
+			wantAllowed: true,
+			wantLimit:   2,
+		},
+		{
+			name:        "IP address bucket is separate from the client buckets",
+			allow:       func(ctx context.Context) (domain.RateLimitResult, error) { return s.AllowIP(ctx, "ip:192.0.2.1") },
+			wantAllowed: true,
+			wantLimit:   3,
+		},
+	}
+
+	for _, request := range requests {
+		result, err := request.allow(context.Background())
+		if err != nil {
+			t.Fatalf("%s: error = %v", request.name, err)
+		}
+
+		if result.Allowed != request.wantAllowed || result.Limit.Requests != request.wantLimit {
+			t.Errorf("%s: allowed = %v with limit %d, want %v with limit %d", request.name, result.Allowed, result.Limit.Requests, request.wantAllowed, request.wantLimit)
+		}
+	}
+}
+
+func TestMemoryRateLimitStorePurgeIdle(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	limit := domain.RateLimit{Requests: 1, Period: time.Minute}
+
+	store.Take(context.Background(), "idle", limit, time.Now().Add(-2*time.Minute))
+	store.Take(context.Background(), "active", limit, time.Now())
+
+	if err := store.PurgeIdle(context.Background(), time.Minute); err != nil {
+		t.Fatalf("PurgeIdle() error = %v", err)
+	}
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("idle bucket wasn't purged")
+	}
+
+	if _, ok := store.buckets["active"]; !ok {
+		t.Error("active bucket was purged")
+	}
+}
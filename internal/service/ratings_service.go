@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+)
+
+// RatingsRepo defines methods for storing ratings and reviews of songs.
+type RatingsRepo interface {
+	Rate(ctx context.Context, rating domain.Rating) (domain.Rating, error)
+	GetRating(ctx context.Context, userID, songID int32) (domain.Rating, error)
+	DeleteRating(ctx context.Context, userID, songID int32) error
+	GetReviews(ctx context.Context, songID int32, status domain.ReviewStatus, page, limit int) ([]domain.Rating, int, error)
+	SetReviewStatus(ctx context.Context, reviewID int32, status domain.ReviewStatus) (domain.Rating, error)
+}
+
+// RatingsService manages ratings of songs by users and the moderation of their reviews.
+type RatingsService struct {
+	repo RatingsRepo
+}
+
+// NewRatingsService initializes and returns a new instance of RatingsService with the provided repository.
+func NewRatingsService(repo RatingsRepo) *RatingsService {
+	return &RatingsService{
+		repo: repo,
+	}
+}
+
+// Rate adds or replaces the rating of a song by the user. A new or changed review is pending until it is moderated.
+func (s RatingsService) Rate(ctx context.Context, userID, songID int32, rateInput dto.RateSongDto) (domain.Rating, error) {
+	return s.repo.Rate(ctx, domain.Rating{
+		UserID: userID,
+		SongID: songID,
+		Rating: rateInput.Rating,
+		Review: rateInput.Review,
+	})
+}
+
+// GetRating retrieves the rating of a song by the user.
+func (s RatingsService) GetRating(ctx context.Context, userID, songID int32) (domain.Rating, error) {
+	return s.repo.GetRating(ctx, userID, songID)
+}
+
+// DeleteRating removes the rating of a song by the user.
+func (s RatingsService) DeleteRating(ctx context.Context, userID, songID int32) error {
+	return s.repo.DeleteRating(ctx, userID, songID)
+}
+
+// GetSongReviews retrieves a paginated list of the approved reviews of a song.
+func (s RatingsService) GetSongReviews(ctx context.Context, songID int32, params dto.PaginationParamsDto) ([]domain.Rating, int, error) {
+	return s.repo.GetReviews(ctx, songID, domain.ReviewApproved, params.Page, params.Limit)
+}
+
+// GetReviews retrieves a paginated list of the reviews of all songs with the given moderation status.
+func (s RatingsService) GetReviews(ctx context.Context, params dto.GetReviewsDto) ([]domain.Rating, int, error) {
+	return s.repo.GetReviews(ctx, 0, domain.ReviewStatus(params.Status), params.PaginationParams.Page, params.PaginationParams.Limit)
+}
+
+// Moderate sets the moderation status of a review.
+func (s RatingsService) Moderate(ctx context.Context, reviewID int32, moderateInput dto.ModerateReviewDto) (domain.Rating, error) {
+	return s.repo.SetReviewStatus(ctx, reviewID, domain.ReviewStatus(moderateInput.Status))
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"songs-library-go/internal/domain"
+	"strings"
+	"unicode"
+)
+
+// SimilaritiesRepo defines methods for storing precomputed similarities of songs and reading similar songs.
+type SimilaritiesRepo interface {
+	ReplaceSimilarities(ctx context.Context, similarities []domain.Similarity) error
+	GetSimilar(ctx context.Context, songID int32, limit int) ([]domain.SimilarSong, error)
+}
+
+// SongsCatalogRepo defines the method for reading the whole catalog of songs.
+type SongsCatalogRepo interface {
+	ExportSongs(ctx context.Context, filtersMap map[string]interface{}, fn func(domain.Song) error) error
+}
+
+// TagsCatalogRepo defines the method for reading the tags of all songs.
+type TagsCatalogRepo interface {
+	ExportTags(ctx context.Context) (map[int32][]string, error)
+}
+
+const (
+	// minTermLength is the minimal number of runes of a word of the lyrics counted as a term.
+	minTermLength = 2
+	// maxTermDocumentShare is the share of songs above which a term or a tag is too common to tell songs apart
+	// or to find candidates by.
+	maxTermDocumentShare = 0.5
+	// yearProximityRange is the number of years between releases at which the year signal drops to zero.
+	yearProximityRange = 10
+)
+
+// similarityDocument holds what the similarity of a song to other songs is computed from.
+type similarityDocument struct {
+	songID int32
+	artist string
+	year   int
+	terms  map[string]float64
+	tags   []string
+}
+
+// SimilarityService computes similarities of songs offline and serves the most similar songs.
+// The similarity of two songs is the weighted sum of the cosine similarity of the TF-IDF vectors of their lyrics,
+// a shared artist, the Jaccard similarity of their tags and the proximity of their release years. Only songs that
+// share lyric terms, an artist or a tag are compared, so the computation doesn't grow with the square of the catalog.
+type SimilarityService struct {
+	repo      SimilaritiesRepo
+	songsRepo SongsCatalogRepo
+	tagsRepo  TagsCatalogRepo
+	weights   domain.SimilarityWeights
+}
+
+// NewSimilarityService initializes and returns a new instance of SimilarityService with the provided repositories
+// and weights of the similarity signals.
+func NewSimilarityService(repo SimilaritiesRepo, songsRepo SongsCatalogRepo, tagsRepo TagsCatalogRepo, weights domain.SimilarityWeights) *SimilarityService {
+	return &SimilarityService{
+		repo:      repo,
+		songsRepo: songsRepo,
+		tagsRepo:  tagsRepo,
+		weights:   weights,
+	}
+}
+
+// GetSimilar retrieves the limit songs most similar to a song as of the last computation.
+func (s SimilarityService) GetSimilar(ctx context.Context, songID int32, limit int) ([]domain.SimilarSong, error) {
+	return s.repo.GetSimilar(ctx, songID, limit)
+}
+
+// Compute computes the top most similar songs of every song of the catalog and replaces the stored similarities.
+// It returns the number of stored similarities.
+func (s SimilarityService) Compute(ctx context.Context, top int) (int, error) {
+	songTags, err := s.tagsRepo.ExportTags(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var documents []similarityDocument
+	documentFrequencies := make(map[string]int)
+
+	err = s.songsRepo.ExportSongs(ctx, nil, func(song domain.Song) error {
+		document := similarityDocument{
+			songID: song.ID,
+			artist: strings.ToLower(strings.TrimSpace(song.Group)),
+			terms:  termCounts(song.Text),
+			tags:   songTags[song.ID],
+		}
+
+		if !song.ReleaseDate.IsZero() {
+			document.year = song.ReleaseDate.Year()
+		}
+
+		for term := range document.terms {
+			documentFrequencies[term]++
+		}
+
+		documents = append(documents, document)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Every term of a song is weighted by TF-IDF and the vector is normalized, so a dot product is a cosine similarity.
+	// postings lists the songs containing every term, by index in documents.
+	maxDocumentFrequency := int(maxTermDocumentShare * float64(len(documents)))
+	postings := make(map[string][]int)
+	artists := make(map[string][]int)
+	tagged := make(map[string][]int)
+
+	for i, document := range documents {
+		var norm float64
+		for term, count := range document.terms {
+			frequency := documentFrequencies[term]
+			if frequency < 2 || frequency > maxDocumentFrequency {
+				delete(document.terms, term)
+				continue
+			}
+
+			weight := (1 + math.Log(count)) * math.Log(float64(len(documents))/float64(frequency))
+			document.terms[term] = weight
+			norm += weight * weight
+		}
+
+		for term, weight := range document.terms {
+			document.terms[term] = weight / math.Sqrt(norm)
+			postings[term] = append(postings[term], i)
+		}
+
+		if document.artist != "" {
+			artists[document.artist] = append(artists[document.artist], i)
+		}
+
+		for _, tag := range document.tags {
+			tagged[tag] = append(tagged[tag], i)
+		}
+	}
+
+	// Songs sharing only a tag as common as a too common term aren't compared, but the tag still counts
+	// for songs compared for other reasons.
+	for tag, songs := range tagged {
+		if len(songs) > maxDocumentFrequency {
+			delete(tagged, tag)
+		}
+	}
+
+	var similarities []domain.Similarity
+
+	for i, document := range documents {
+		textScores := make(map[int]float64)
+		for term, weight := range document.terms {
+			for _, j := range postings[term] {
+				if j != i {
+					textScores[j] += weight * documents[j].terms[term]
+				}
+			}
+		}
+
+		related := slices.Clip(artists[document.artist])
+		for _, tag := range document.tags {
+			related = append(related, tagged[tag]...)
+		}
+
+		for _, j := range related {
+			if _, ok := textScores[j]; !ok && j != i {
+				textScores[j] = 0
+			}
+		}
+
+		candidates := make([]domain.Similarity, 0, len(textScores))
+		for j, textScore := range textScores {
+			score := s.weights.Text*textScore +
+				s.weights.Tags*tagsSimilarity(document.tags, documents[j].tags) +
+				s.weights.Year*yearProximity(document.year, documents[j].year)
+			if document.artist != "" && document.artist == documents[j].artist {
+				score += s.weights.Artist
+			}
+
+			if score > 0 {
+				candidates = append(candidates, domain.Similarity{
+					SongID:        document.songID,
+					SimilarSongID: documents[j].songID,
+					Score:         score,
+				})
+			}
+		}
+
+		slices.SortFunc(candidates, func(a, b domain.Similarity) int {
+			return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.SimilarSongID, b.SimilarSongID))
+		})
+
+		similarities = append(similarities, candidates[:min(top, len(candidates))]...)
+	}
+
+	if err := s.repo.ReplaceSimilarities(ctx, similarities); err != nil {
+		return 0, err
+	}
+
+	return len(similarities), nil
+}
+
+// termCounts counts the lowercase words of the lyrics, splitting on everything but letters and digits.
+func termCounts(text string) map[string]float64 {
+	counts := make(map[string]float64)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) >= minTermLength {
+			counts[word]++
+		}
+	}
+
+	return counts
+}
+
+// tagsSimilarity is the Jaccard similarity of the distinct tags of two songs: the number of shared tags divided by
+// the number of tags of either song. It is 0 if either song has no tags.
+func tagsSimilarity(tags, otherTags []string) float64 {
+	if len(tags) == 0 || len(otherTags) == 0 {
+		return 0
+	}
+
+	var shared int
+	for _, tag := range tags {
+		if slices.Contains(otherTags, tag) {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(tags)+len(otherTags)-shared)
+}
+
+// yearProximity is 1 for songs released in the same year, decreasing linearly to 0 at yearProximityRange years apart.
+// It is 0 if the release year of either song is unknown.
+func yearProximity(year, otherYear int) float64 {
+	if year == 0 || otherYear == 0 {
+		return 0
+	}
+
+	distance := math.Abs(float64(year - otherYear))
+	return max(0, 1-distance/yearProximityRange)
+}
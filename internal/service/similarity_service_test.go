@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"songs-library-go/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestTermCounts(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want map[string]float64
+	}{
+		{name: "empty", text: "", want: map[string]float64{}},
+		{name: "lowercased and counted", text: "Love, love LOVE me do", want: map[string]float64{"love": 3, "me": 1, "do": 1}},
+		{name: "split on punctuation and newlines", text: "rock'n'roll\n\nall-night", want: map[string]float64{"rock": 1, "roll": 1, "all": 1, "night": 1}},
+		{name: "short words dropped", text: "a I n 'n' ok", want: map[string]float64{"ok": 1}},
+		{name: "unicode letters and digits", text: "Я люблю 1999 Über", want: map[string]float64{"люблю": 1, "1999": 1, "über": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := termCounts(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("termCounts(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYearProximity(t *testing.T) {
+	tests := []struct {
+		year, otherYear int
+		want            float64
+	}{
+		{2000, 2000, 1},
+		{2000, 2005, 0.5},
+		{2005, 2000, 0.5},
+		{2000, 2009, 0.1},
+		{2000, 2010, 0},
+		{2000, 1980, 0},
+		{0, 2000, 0},
+		{2000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := yearProximity(tt.year, tt.otherYear); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("yearProximity(%d, %d) = %v, want %v", tt.year, tt.otherYear, got, tt.want)
+		}
+	}
+}
+
+func TestTagsSimilarity(t *testing.T) {
+	tests := []struct {
+		name            string
+		tags, otherTags []string
+		want            float64
+	}{
+		{name: "same tags", tags: []string{"rock", "ballad"}, otherTags: []string{"ballad", "rock"}, want: 1},
+		{name: "one of three tags shared", tags: []string{"rock", "ballad"}, otherTags: []string{"rock", "live"}, want: 1.0 / 3},
+		{name: "no shared tags", tags: []string{"rock"}, otherTags: []string{"jazz"}, want: 0},
+		{name: "no tags", tags: nil, otherTags: []string{"jazz"}, want: 0},
+		{name: "neither has tags", tags: nil, otherTags: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagsSimilarity(tt.tags, tt.otherTags); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("tagsSimilarity(%v, %v) = %v, want %v", tt.tags, tt.otherTags, got, tt.want)
+			}
+		})
+	}
+}
+
+// similarityRepos is an in-memory catalog of songs and tags storing the computed similarities.
+type similarityRepos struct {
+	songs        []domain.Song
+	tags         map[int32][]string
+	similarities []domain.Similarity
+}
+
+func (r *similarityRepos) ExportSongs(_ context.Context, _ map[string]interface{}, fn func(domain.Song) error) error {
+	for _, song := range r.songs {
+		if err := fn(song); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *similarityRepos) ExportTags(context.Context) (map[int32][]string, error) {
+	return r.tags, nil
+}
+
+func (r *similarityRepos) ReplaceSimilarities(_ context.Context, similarities []domain.Similarity) error {
+	r.similarities = similarities
+	return nil
+}
+
+func (r *similarityRepos) GetSimilar(context.Context, int32, int) ([]domain.SimilarSong, error) {
+	return nil, nil
+}
+
+// similarityCatalog has two pairs of songs sharing rare lyric terms, songs by the same artist, tagged songs,
+// and filler songs that make the shared terms and tags rare enough to count.
+func similarityCatalog() *similarityRepos {
+	year := func(year int) time.Time { return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	return &similarityRepos{
+		songs: []domain.Song{
+			{ID: 1, Group: "Muse", Text: "starlight tonight far away", ReleaseDate: year(2006)},
+			{ID: 2, Group: "Coldplay", Text: "starlight tonight yellow", ReleaseDate: year(2000)},
+			{ID: 3, Group: " muse ", Text: "uprising rebels"},
+			{ID: 4, Group: "Queen", Text: "champions forever"},
+			{ID: 5, Group: "ABBA", Text: "dancing queen"},
+			{ID: 6, Group: "Nirvana", Text: "teen spirit"},
+			{ID: 7, Group: "Oasis", Text: "wonderwall maybe"},
+		},
+		tags: map[int32][]string{
+			4: {"rock", "anthem"},
+			6: {"rock", "grunge"},
+		},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights domain.SimilarityWeights
+		top     int
+		want    map[int32][]int32
+	}{
+		{
+			name:    "text only",
+			weights: domain.SimilarityWeights{Text: 1},
+			top:     5,
+			want:    map[int32][]int32{1: {2}, 2: {1}},
+		},
+		{
+			name:    "artist only, case and spaces ignored",
+			weights: domain.SimilarityWeights{Artist: 1},
+			top:     5,
+			want:    map[int32][]int32{1: {3}, 3: {1}},
+		},
+		{
+			name:    "tags only",
+			weights: domain.SimilarityWeights{Tags: 1},
+			top:     5,
+			want:    map[int32][]int32{4: {6}, 6: {4}},
+		},
+		{
+			name:    "all signals, most similar first",
+			weights: domain.SimilarityWeights{Text: 0.5, Artist: 0.2, Tags: 0.2, Year: 0.1},
+			top:     5,
+			want:    map[int32][]int32{1: {2, 3}, 2: {1}, 3: {1}, 4: {6}, 6: {4}},
+		},
+		{
+			name:    "top limits the similar songs of a song",
+			weights: domain.SimilarityWeights{Text: 0.5, Artist: 0.2, Tags: 0.2, Year: 0.1},
+			top:     1,
+			want:    map[int32][]int32{1: {2}, 2: {1}, 3: {1}, 4: {6}, 6: {4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := similarityCatalog()
+			s := NewSimilarityService(repos, repos, repos, tt.weights)
+
+			count, err := s.Compute(context.Background(), tt.top)
+			if err != nil {
+				t.Fatalf("Compute() error = %v", err)
+			}
+
+			if count != len(repos.similarities) {
+				t.Errorf("Compute() = %d, stored %d similarities", count, len(repos.similarities))
+			}
+
+			got := make(map[int32][]int32)
+			for i, similarity := range repos.similarities {
+				got[similarity.SongID] = append(got[similarity.SongID], similarity.SimilarSongID)
+
+				if similarity.Score <= 0 || similarity.Score > 1+1e-9 {
+					t.Errorf("similarity %+v: score out of (0, 1]", similarity)
+				}
+
+				if i > 0 && repos.similarities[i-1].SongID == similarity.SongID && repos.similarities[i-1].Score < similarity.Score {
+					t.Errorf("similarities of song %d aren't ordered by score", similarity.SongID)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("similar songs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeScore(t *testing.T) {
+	repos := similarityCatalog()
+	weights := domain.SimilarityWeights{Text: 0.5, Artist: 0.2, Tags: 0.2, Year: 0.1}
+
+	if _, err := NewSimilarityService(repos, repos, repos, weights).Compute(context.Background(), 5); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+
+	scores := make(map[[2]int32]float64)
+	for _, similarity := range repos.similarities {
+		scores[[2]int32{similarity.SongID, similarity.SimilarSongID}] = similarity.Score
+	}
+
+	// Songs 4 and 6 share one of their three tags and nothing else.
+	if got, want := scores[[2]int32{4, 6}], weights.Tags/3; math.Abs(got-want) > 1e-9 {
+		t.Errorf("score of songs 4 and 6 = %v, want %v", got, want)
+	}
+
+	// Songs 1 and 3 share only the artist.
+	if got, want := scores[[2]int32{1, 3}], weights.Artist; math.Abs(got-want) > 1e-9 {
+		t.Errorf("score of songs 1 and 3 = %v, want %v", got, want)
+	}
+
+	// Songs 1 and 2 share two of their rare terms and were released 6 years apart.
+	if got := scores[[2]int32{1, 2}]; got <= weights.Year*0.4 || got >= weights.Text+weights.Year*0.4 {
+		t.Errorf("score of songs 1 and 2 = %v, want a text similarity between 0 and 1 plus %v", got, weights.Year*0.4)
+	}
+
+	if scores[[2]int32{1, 2}] != scores[[2]int32{2, 1}] {
+		t.Errorf("similarity isn't symmetric: %v and %v", scores[[2]int32{1, 2}], scores[[2]int32{2, 1}])
+	}
+}
+
+func TestComputeDoesNotCompareSongsByCommonTags(t *testing.T) {
+	repos := similarityCatalog()
+	repos.tags = map[int32][]string{1: {"pop"}, 3: {"pop"}, 5: {"pop"}, 6: {"pop"}, 7: {"pop"}}
+
+	if _, err := NewSimilarityService(repos, repos, repos, domain.SimilarityWeights{Artist: 0.5, Tags: 0.5}).Compute(context.Background(), 5); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+
+	// Only songs 1 and 3 are compared, for their artist, and their common tag still counts.
+	want := []domain.Similarity{{SongID: 1, SimilarSongID: 3, Score: 1}, {SongID: 3, SimilarSongID: 1, Score: 1}}
+	if !reflect.DeepEqual(repos.similarities, want) {
+		t.Errorf("similarities = %+v, want %+v", repos.similarities, want)
+	}
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/tracing"
+	"strings"
+	"time"
+)
+
+const (
+	importBatchSize     = 500
+	maxImportLineLength = 1024 * 1024
+)
+
+type importRowReader interface {
+	next() (dto.ImportSongDto, error)
+}
+
+// Import reads songs from r in the given format, validates every row and inserts valid rows in batches
+// inside a single transaction following the conflict policy. Songs created without details are queued for enrichment.
+// The returned report is filled even when the import is aborted with domain.ErrImportConflict.
+func (s SongsService) Import(ctx context.Context, r io.Reader, params dto.ImportParamsDto) (domain.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "SongsService.Import")
+	defer span.End()
+
+	rows, err := s.newImportRowReader(r, params.Format)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	policy := domain.ConflictPolicy(params.OnConflict)
+
+	var report domain.ImportReport
+	var withoutDetails map[int]bool
+
+	err = s.repo.ImportSongs(ctx, policy, func(insert func([]domain.Song) ([]domain.ImportedSong, error)) error {
+		report = domain.ImportReport{}
+		withoutDetails = make(map[int]bool)
+		seenRows := make(map[string]int)
+		batch := make([]domain.Song, 0, importBatchSize)
+		batchRows := make([]int, 0, importBatchSize)
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+
+			results, err := insert(batch)
+			if err != nil {
+				return err
+			}
+
+			conflict := false
+			for i, result := range results {
+				row := &report.Rows[batchRows[i]]
+				row.Status = result.Status
+				row.SongID = result.Song.ID
+
+				if result.Status == domain.ImportConflict {
+					row.Error = domain.ErrSongAlreadyExist.Error()
+					conflict = true
+				}
+			}
+
+			batch = batch[:0]
+			batchRows = batchRows[:0]
+
+			if conflict {
+				return domain.ErrImportConflict
+			}
+
+			return nil
+		}
+
+		for rowNumber := 1; ; rowNumber++ {
+			importSong, err := rows.next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			row := domain.ImportRowResult{Row: rowNumber, Group: importSong.Group, Song: importSong.Song}
+
+			if err != nil {
+				if !errors.Is(err, domain.ErrInvalidImportRow) {
+					return err
+				}
+
+				row.Status = domain.ImportInvalid
+				row.Error = err.Error()
+				report.Rows = append(report.Rows, row)
+				continue
+			}
+
+			song, err := s.validateImportRow(importSong)
+			if err != nil {
+				row.Status = domain.ImportInvalid
+				row.Error = err.Error()
+				report.Rows = append(report.Rows, row)
+				continue
+			}
+
+			row.Group, row.Song = song.Group, song.Song
+
+			key := song.Group + "\x00" + song.Song
+			if firstRow, ok := seenRows[key]; ok {
+				row.Status = domain.ImportSkipped
+				row.Error = fmt.Sprintf("%s (row: %d)", domain.ErrDuplicateImportRow, firstRow)
+				report.Rows = append(report.Rows, row)
+				continue
+			}
+			seenRows[key] = rowNumber
+
+			report.Rows = append(report.Rows, row)
+			withoutDetails[len(report.Rows)-1] = song.ReleaseDate.IsZero() && song.Text == "" && song.Link == ""
+			batch = append(batch, song)
+			batchRows = append(batchRows, len(report.Rows)-1)
+
+			if len(batch) == importBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+
+		return flush()
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrImportConflict) {
+			s.markRolledBack(&report)
+		}
+
+		return report, err
+	}
+
+	for i, row := range report.Rows {
+		if row.Status == domain.ImportCreated && withoutDetails[i] {
+			s.enqueueDetails(ctx, row.SongID, row.Group, row.Song)
+		}
+	}
+
+	return report, nil
+}
+
+func (s SongsService) validateImportRow(importSong dto.ImportSongDto) (domain.Song, error) {
+	createSongInput := dto.CreateSongDto{
+		Group: strings.TrimSpace(importSong.Group),
+		Song:  strings.TrimSpace(importSong.Song),
+	}
+
+	if err := s.validator.Struct(createSongInput); err != nil {
+		return domain.Song{}, err
+	}
+
+	detailsInput := dto.SongParamsDto{
+		ReleaseDate: trimmedOrNil(importSong.ReleaseDate),
+		Text:        trimmedOrNil(importSong.Text),
+		Link:        trimmedOrNil(importSong.Link),
+	}
+
+	if err := s.validator.Struct(detailsInput); err != nil {
+		return domain.Song{}, err
+	}
+
+	song := domain.Song{
+		Group: createSongInput.Group,
+		Song:  createSongInput.Song,
+	}
+
+	if detailsInput.ReleaseDate != nil {
+		song.ReleaseDate, _ = time.Parse(domain.DateFormat, *detailsInput.ReleaseDate)
+	}
+	if detailsInput.Text != nil {
+		song.Text = *detailsInput.Text
+	}
+	if detailsInput.Link != nil {
+		song.Link = *detailsInput.Link
+	}
+
+	return song, nil
+}
+
+func (s SongsService) markRolledBack(report *domain.ImportReport) {
+	for i := range report.Rows {
+		if report.Rows[i].Status == domain.ImportCreated || report.Rows[i].Status == domain.ImportUpdated {
+			report.Rows[i].Status = domain.ImportRolledBack
+			report.Rows[i].SongID = 0
+		}
+	}
+}
+
+func (s SongsService) newImportRowReader(r io.Reader, format string) (importRowReader, error) {
+	switch format {
+	case domain.FormatCSV:
+		return newCSVRowReader(r)
+	case domain.FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineLength)
+		return &ndjsonRowReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedImportFormat, format)
+	}
+}
+
+type csvRowReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	fields  int
+}
+
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrReadingImportFile, err)
+	}
+
+	validColumns := map[string]bool{
+		"id":           true,
+		"group":        true,
+		"song":         true,
+		"release_date": true,
+		"text":         true,
+		"link":         true,
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !validColumns[column] {
+			return nil, fmt.Errorf("%w: unknown column %q", domain.ErrReadingImportFile, column)
+		}
+
+		if _, ok := columns[column]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", domain.ErrReadingImportFile, column)
+		}
+
+		columns[column] = i
+	}
+
+	if _, ok := columns["group"]; !ok {
+		return nil, fmt.Errorf("%w: missing column %q", domain.ErrReadingImportFile, "group")
+	}
+
+	if _, ok := columns["song"]; !ok {
+		return nil, fmt.Errorf("%w: missing column %q", domain.ErrReadingImportFile, "song")
+	}
+
+	return &csvRowReader{reader: reader, columns: columns, fields: len(header)}, nil
+}
+
+// next reads the next record. A malformed record, such as one with a bare quote, is an invalid row,
+// and reading continues with the line after it.
+func (c *csvRowReader) next() (dto.ImportSongDto, error) {
+	record, err := c.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return dto.ImportSongDto{}, io.EOF
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return dto.ImportSongDto{}, fmt.Errorf("%w: %w", domain.ErrInvalidImportRow, err)
+		}
+
+		return dto.ImportSongDto{}, fmt.Errorf("%w: %w", domain.ErrReadingImportFile, err)
+	}
+
+	if len(record) != c.fields {
+		return dto.ImportSongDto{}, fmt.Errorf("%w: expected %d fields, got %d", domain.ErrInvalidImportRow, c.fields, len(record))
+	}
+
+	var importSong dto.ImportSongDto
+	importSong.Group = record[c.columns["group"]]
+	importSong.Song = record[c.columns["song"]]
+	importSong.ReleaseDate = c.optionalField(record, "release_date")
+	importSong.Text = c.optionalField(record, "text")
+	importSong.Link = c.optionalField(record, "link")
+
+	return importSong, nil
+}
+
+func (c *csvRowReader) optionalField(record []string, column string) *string {
+	i, ok := c.columns[column]
+	if !ok || record[i] == "" {
+		return nil
+	}
+
+	return &record[i]
+}
+
+type ndjsonRowReader struct {
+	scanner *bufio.Scanner
+}
+
+func (n *ndjsonRowReader) next() (dto.ImportSongDto, error) {
+	for n.scanner.Scan() {
+		line := strings.TrimSpace(n.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var importSong dto.ImportSongDto
+		if err := json.Unmarshal([]byte(line), &importSong); err != nil {
+			return dto.ImportSongDto{}, fmt.Errorf("%w: %s", domain.ErrInvalidImportRow, err)
+		}
+
+		return importSong, nil
+	}
+
+	if err := n.scanner.Err(); err != nil {
+		return dto.ImportSongDto{}, fmt.Errorf("%w: %w", domain.ErrReadingImportFile, err)
+	}
+
+	return dto.ImportSongDto{}, io.EOF
+}
+
+func trimmedOrNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+
+	return &trimmed
+}
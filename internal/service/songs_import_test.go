@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"reflect"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"strings"
+	"testing"
+)
+
+// importRow is a row read by an importRowReader, or the error reading it.
+type importRow struct {
+	song dto.ImportSongDto
+	err  error
+}
+
+func readImportRows(t *testing.T, rows importRowReader) []importRow {
+	t.Helper()
+
+	var read []importRow
+	for {
+		song, err := rows.next()
+		if errors.Is(err, io.EOF) {
+			return read
+		}
+
+		if err != nil && !errors.Is(err, domain.ErrInvalidImportRow) {
+			t.Fatalf("next() error = %v, want an invalid row or io.EOF", err)
+		}
+
+		read = append(read, importRow{song: song, err: err})
+	}
+}
+
+func assertImportRows(t *testing.T, got, want []importRow) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("read %d rows, want %d: %+v", len(got), len(want), got)
+	}
+
+	for i := range want {
+		if !errors.Is(got[i].err, want[i].err) {
+			t.Errorf("row %d: error = %v, want %v", i+1, got[i].err, want[i].err)
+		}
+
+		if !reflect.DeepEqual(got[i].song, want[i].song) {
+			t.Errorf("row %d: song = %+v, want %+v", i+1, got[i].song, want[i].song)
+		}
+	}
+}
+
+func ptr(value string) *string {
+	return &value
+}
+
+func TestCSVRowReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []importRow
+	}{
+		{
+			name:  "required columns",
+			input: "group,song\nMuse,Hysteria\n",
+			want:  []importRow{{song: dto.ImportSongDto{Group: "Muse", Song: "Hysteria"}}},
+		},
+		{
+			name:  "all columns in any order with a BOM and mixed case header",
+			input: "\ufeffLink, song ,GROUP,release_date,text,id\nhttps://example.com,Hysteria,Muse,01.12.2003,\"Verse 1\n\nVerse 2\",7\n",
+			want: []importRow{{song: dto.ImportSongDto{
+				Group:       "Muse",
+				Song:        "Hysteria",
+				ReleaseDate: ptr("01.12.2003"),
+				Text:        ptr("Verse 1\n\nVerse 2"),
+				Link:        ptr("https://example.com"),
+			}}},
+		},
+		{
+			name:  "empty optional fields",
+			input: "group,song,release_date,text,link\nMuse,Hysteria,,,\n",
+			want:  []importRow{{song: dto.ImportSongDto{Group: "Muse", Song: "Hysteria"}}},
+		},
+		{
+			name:  "wrong number of fields",
+			input: "group,song\nMuse\nMuse,Hysteria,extra\nMuse,Starlight\n",
+			want: []importRow{
+				{err: domain.ErrInvalidImportRow},
+				{err: domain.ErrInvalidImportRow},
+				{song: dto.ImportSongDto{Group: "Muse", Song: "Starlight"}},
+			},
+		},
+		{
+			name:  "malformed record between valid records",
+			input: "group,song\nMuse,Hysteria\nMu\"se,Uprising\nMuse,Starlight\n",
+			want: []importRow{
+				{song: dto.ImportSongDto{Group: "Muse", Song: "Hysteria"}},
+				{err: domain.ErrInvalidImportRow},
+				{song: dto.ImportSongDto{Group: "Muse", Song: "Starlight"}},
+			},
+		},
+		{
+			name:  "header only",
+			input: "group,song\n",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := newCSVRowReader(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("newCSVRowReader() error = %v", err)
+			}
+
+			assertImportRows(t, readImportRows(t, rows), tt.want)
+		})
+	}
+}
+
+func TestNewCSVRowReaderRejectsHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "empty file", input: "", wantErr: "EOF"},
+		{name: "unknown column", input: "group,song,album\n", wantErr: `unknown column "album"`},
+		{name: "duplicate column", input: "group,song,Group\n", wantErr: `duplicate column "group"`},
+		{name: "missing group", input: "song,text\n", wantErr: `missing column "group"`},
+		{name: "missing song", input: "group,text\n", wantErr: `missing column "song"`},
+		{name: "malformed header", input: "group,\"song\n", wantErr: "parse error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newCSVRowReader(strings.NewReader(tt.input))
+			if !errors.Is(err, domain.ErrReadingImportFile) {
+				t.Fatalf("newCSVRowReader() error = %v, want %v", err, domain.ErrReadingImportFile)
+			}
+
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("newCSVRowReader() error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNDJSONRowReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []importRow
+	}{
+		{
+			name:  "rows with and without details",
+			input: `{"group":"Muse","song":"Hysteria","release_date":"01.12.2003","text":"Verse","link":"https://example.com"}` + "\n" + `{"group":"Muse","song":"Starlight"}`,
+			want: []importRow{
+				{song: dto.ImportSongDto{Group: "Muse", Song: "Hysteria", ReleaseDate: ptr("01.12.2003"), Text: ptr("Verse"), Link: ptr("https://example.com")}},
+				{song: dto.ImportSongDto{Group: "Muse", Song: "Starlight"}},
+			},
+		},
+		{
+			name:  "blank lines are skipped",
+			input: "\n  \n" + `{"group":"Muse","song":"Hysteria"}` + "\n\n",
+			want:  []importRow{{song: dto.ImportSongDto{Group: "Muse", Song: "Hysteria"}}},
+		},
+		{
+			name:  "malformed lines are invalid rows",
+			input: `{"group":"Muse",` + "\n" + `{"group":1,"song":"Uprising"}` + "\n" + `{"group":"Muse","song":"Starlight"}`,
+			want: []importRow{
+				{err: domain.ErrInvalidImportRow},
+				{err: domain.ErrInvalidImportRow},
+				{song: dto.ImportSongDto{Group: "Muse", Song: "Starlight"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := &ndjsonRowReader{scanner: bufio.NewScanner(strings.NewReader(tt.input))}
+
+			assertImportRows(t, readImportRows(t, rows), tt.want)
+		})
+	}
+}
+
+func TestNDJSONRowReaderRejectsTooLongLine(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader(`{"group":"` + strings.Repeat("a", 100) + `"}`))
+	scanner.Buffer(make([]byte, 0, 16), 64)
+
+	_, err := (&ndjsonRowReader{scanner: scanner}).next()
+	if !errors.Is(err, domain.ErrReadingImportFile) {
+		t.Fatalf("next() error = %v, want %v", err, domain.ErrReadingImportFile)
+	}
+}
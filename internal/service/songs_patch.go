@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/tracing"
+	"strings"
+	"time"
+)
+
+// Replace replaces all fields of an existing song, clearing the optional fields that are not provided.
+func (s SongsService) Replace(ctx context.Context, songID int32, replaceSongInput dto.ReplaceSongDto) (domain.Song, error) {
+	ctx, span := tracing.Start(ctx, "SongsService.Replace")
+	defer span.End()
+
+	return s.repo.UpdateSong(ctx, songID, s.makeReplaceParamsMap(replaceSongInput))
+}
+
+// Patch partially modifies an existing song. A JSON Merge Patch sets the provided fields and clears the fields set to null,
+// while JSON Patch operations are applied to the current song inside a transaction and the result replaces the song.
+func (s SongsService) Patch(ctx context.Context, songID int32, patch dto.SongPatchDto) (domain.Song, error) {
+	ctx, span := tracing.Start(ctx, "SongsService.Patch")
+	defer span.End()
+
+	if len(patch.Operations) == 0 {
+		paramsMap := s.makeSongParamsMap(patch.Set)
+		for _, field := range patch.Clear {
+			paramsMap[field] = nil
+		}
+
+		return s.repo.UpdateSong(ctx, songID, paramsMap)
+	}
+
+	return s.repo.PatchSong(ctx, songID, func(song domain.Song) (map[string]interface{}, error) {
+		document := s.toPatchDocument(song)
+
+		if err := s.applyJSONPatch(document, patch.Operations); err != nil {
+			return nil, err
+		}
+
+		if document["group"] == nil || document["song"] == nil {
+			return nil, fmt.Errorf("%w: group and song can't be removed", domain.ErrInvalidPatch)
+		}
+
+		replaceSongInput := dto.ReplaceSongDto{
+			Group:       strings.TrimSpace(*document["group"]),
+			Song:        strings.TrimSpace(*document["song"]),
+			ReleaseDate: trimmedOrNil(document["release_date"]),
+			Text:        trimmedOrNil(document["text"]),
+			Link:        trimmedOrNil(document["link"]),
+		}
+
+		if err := s.validator.Struct(replaceSongInput); err != nil {
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPatch, err)
+		}
+
+		return s.makeReplaceParamsMap(replaceSongInput), nil
+	})
+}
+
+func (s SongsService) applyJSONPatch(document map[string]*string, operations []dto.JSONPatchOperationDto) error {
+	for i, operation := range operations {
+		field := strings.TrimPrefix(operation.Path, "/")
+		from := strings.TrimPrefix(operation.From, "/")
+
+		switch operation.Op {
+		case "add", "replace", "test":
+			var value *string
+			if err := json.Unmarshal(operation.Value, &value); err != nil {
+				return fmt.Errorf("%w: operation %d: value must be a string or null", domain.ErrInvalidPatch, i)
+			}
+
+			if operation.Op == "test" {
+				if !equalValues(document[field], value) {
+					return fmt.Errorf("%w: operation %d (path: %s)", domain.ErrPatchTestFailed, i, operation.Path)
+				}
+				continue
+			}
+
+			document[field] = value
+		case "remove":
+			// Unset fields don't exist in the document, so they can't be removed.
+			if document[field] == nil {
+				return fmt.Errorf("%w: operation %d: %s has no value", domain.ErrInvalidPatch, i, operation.Path)
+			}
+
+			document[field] = nil
+		case "move", "copy":
+			if document[from] == nil {
+				return fmt.Errorf("%w: operation %d: %s has no value", domain.ErrInvalidPatch, i, operation.From)
+			}
+
+			value := document[from]
+			if operation.Op == "move" && from != field {
+				document[from] = nil
+			}
+
+			document[field] = value
+		}
+	}
+
+	return nil
+}
+
+func (s SongsService) toPatchDocument(song domain.Song) map[string]*string {
+	document := map[string]*string{
+		"group":        &song.Group,
+		"song":         &song.Song,
+		"release_date": nil,
+		"text":         nil,
+		"link":         nil,
+	}
+
+	if !song.ReleaseDate.IsZero() {
+		releaseDate := song.ReleaseDate.Format(domain.DateFormat)
+		document["release_date"] = &releaseDate
+	}
+	if song.Text != "" {
+		document["text"] = &song.Text
+	}
+	if song.Link != "" {
+		document["link"] = &song.Link
+	}
+
+	return document
+}
+
+func (s SongsService) makeReplaceParamsMap(replaceSongInput dto.ReplaceSongDto) map[string]interface{} {
+	paramsMap := map[string]interface{}{
+		"group":        replaceSongInput.Group,
+		"song":         replaceSongInput.Song,
+		"release_date": nil,
+		"text":         nil,
+		"link":         nil,
+	}
+
+	if replaceSongInput.ReleaseDate != nil {
+		date, _ := time.Parse(domain.DateFormat, *replaceSongInput.ReleaseDate)
+		paramsMap["release_date"] = date
+	}
+
+	if replaceSongInput.Text != nil {
+		paramsMap["text"] = *replaceSongInput.Text
+	}
+
+	if replaceSongInput.Link != nil {
+		paramsMap["link"] = *replaceSongInput.Link
+	}
+
+	return paramsMap
+}
+
+func equalValues(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/validator"
+	"testing"
+	"time"
+)
+
+func jsonPatch(t *testing.T, operations string) []dto.JSONPatchOperationDto {
+	t.Helper()
+
+	var patch []dto.JSONPatchOperationDto
+	if err := json.Unmarshal([]byte(operations), &patch); err != nil {
+		t.Fatalf("invalid patch %s: %v", operations, err)
+	}
+
+	return patch
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations string
+		want       map[string]*string
+		wantErr    error
+	}{
+		{
+			name:       "replace and add",
+			operations: `[{"op":"replace","path":"/song","value":"Starlight"},{"op":"add","path":"/text","value":"Far away"}]`,
+			want:       map[string]*string{"group": ptr("Muse"), "song": ptr("Starlight"), "text": ptr("Far away"), "link": ptr("https://example.com")},
+		},
+		{
+			name:       "remove",
+			operations: `[{"op":"remove","path":"/link"}]`,
+			want:       map[string]*string{"group": ptr("Muse"), "song": ptr("Hysteria"), "text": nil, "link": nil},
+		},
+		{
+			name:       "add null clears",
+			operations: `[{"op":"add","path":"/link","value":null}]`,
+			want:       map[string]*string{"group": ptr("Muse"), "song": ptr("Hysteria"), "text": nil, "link": nil},
+		},
+		{
+			name:       "move",
+			operations: `[{"op":"move","from":"/link","path":"/text"}]`,
+			want:       map[string]*string{"group": ptr("Muse"), "song": ptr("Hysteria"), "text": ptr("https://example.com"), "link": nil},
+		},
+		{
+			name:       "copy",
+			operations: `[{"op":"copy","from":"/song","path":"/text"}]`,
+			want:       map[string]*string{"group": ptr("Muse"), "song": ptr("Hysteria"), "text": ptr("Hysteria"), "link": ptr("https://example.com")},
+		},
+		{
+			name:       "passing tests",
+			operations: `[{"op":"test","path":"/song","value":"Hysteria"},{"op":"test","path":"/text","value":null},{"op":"replace","path":"/song","value":"Uprising"}]`,
+			want:       map[string]*string{"group": ptr("Muse"), "song": ptr("Uprising"), "text": nil, "link": ptr("https://example.com")},
+		},
+		{
+			name:       "operations see the result of earlier operations",
+			operations: `[{"op":"replace","path":"/song","value":"Uprising"},{"op":"test","path":"/song","value":"Uprising"}]`,
+			want:       map[string]*string{"group": ptr("Muse"), "song": ptr("Uprising"), "text": nil, "link": ptr("https://example.com")},
+		},
+		{
+			name:       "failed test of a value",
+			operations: `[{"op":"test","path":"/song","value":"Starlight"}]`,
+			wantErr:    domain.ErrPatchTestFailed,
+		},
+		{
+			name:       "failed test of a missing value",
+			operations: `[{"op":"test","path":"/text","value":"Far away"}]`,
+			wantErr:    domain.ErrPatchTestFailed,
+		},
+		{
+			name:       "failed test of null",
+			operations: `[{"op":"test","path":"/link","value":null}]`,
+			wantErr:    domain.ErrPatchTestFailed,
+		},
+		{
+			name:       "value that isn't a string",
+			operations: `[{"op":"replace","path":"/song","value":42}]`,
+			wantErr:    domain.ErrInvalidPatch,
+		},
+		{
+			name:       "missing value",
+			operations: `[{"op":"add","path":"/text"}]`,
+			wantErr:    domain.ErrInvalidPatch,
+		},
+		{
+			name:       "move to the same path",
+			operations: `[{"op":"move","from":"/link","path":"/link"}]`,
+			want:       map[string]*string{"group": ptr("Muse"), "song": ptr("Hysteria"), "text": nil, "link": ptr("https://example.com")},
+		},
+		{
+			name:       "remove of a null value",
+			operations: `[{"op":"remove","path":"/text"}]`,
+			wantErr:    domain.ErrInvalidPatch,
+		},
+		{
+			name:       "move from a null value",
+			operations: `[{"op":"move","from":"/text","path":"/link"}]`,
+			wantErr:    domain.ErrInvalidPatch,
+		},
+		{
+			name:       "copy from a null value",
+			operations: `[{"op":"copy","from":"/text","path":"/link"}]`,
+			wantErr:    domain.ErrInvalidPatch,
+		},
+		{
+			name:       "remove of a value cleared by an earlier operation",
+			operations: `[{"op":"remove","path":"/link"},{"op":"remove","path":"/link"}]`,
+			wantErr:    domain.ErrInvalidPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := map[string]*string{"group": ptr("Muse"), "song": ptr("Hysteria"), "text": nil, "link": ptr("https://example.com")}
+
+			err := SongsService{}.applyJSONPatch(document, jsonPatch(t, tt.operations))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyJSONPatch() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(document, tt.want) {
+				t.Errorf("document = %v, want %v", document, tt.want)
+			}
+		})
+	}
+}
+
+// patchSongsRepo is a SongsRepo patching a single song and recording the params it is replaced with.
+type patchSongsRepo struct {
+	SongsRepo
+	song   domain.Song
+	params map[string]interface{}
+}
+
+func (r *patchSongsRepo) PatchSong(_ context.Context, _ int32, fn func(domain.Song) (map[string]interface{}, error)) (domain.Song, error) {
+	params, err := fn(r.song)
+	if err != nil {
+		return domain.Song{}, err
+	}
+
+	r.params = params
+	return r.song, nil
+}
+
+func TestPatchWithJSONPatch(t *testing.T) {
+	song := domain.Song{ID: 1, Group: "Muse", Song: "Hysteria", ReleaseDate: time.Date(2003, 12, 1, 0, 0, 0, 0, time.UTC), Link: "https://example.com"}
+
+	tests := []struct {
+		name       string
+		operations string
+		want       map[string]interface{}
+		wantErr    error
+	}{
+		{
+			name:       "trims values and keeps unchanged fields",
+			operations: `[{"op":"replace","path":"/song","value":"  Starlight "},{"op":"add","path":"/text","value":"Far away"}]`,
+			want: map[string]interface{}{
+				"group":        "Muse",
+				"song":         "Starlight",
+				"release_date": song.ReleaseDate,
+				"text":         "Far away",
+				"link":         "https://example.com",
+			},
+		},
+		{
+			name:       "blank values clear fields",
+			operations: `[{"op":"replace","path":"/link","value":"  "},{"op":"remove","path":"/release_date"}]`,
+			want:       map[string]interface{}{"group": "Muse", "song": "Hysteria", "release_date": nil, "text": nil, "link": nil},
+		},
+		{
+			name:       "group can't be removed",
+			operations: `[{"op":"remove","path":"/group"}]`,
+			wantErr:    domain.ErrInvalidPatch,
+		},
+		{
+			name:       "song can't be moved away",
+			operations: `[{"op":"move","from":"/song","path":"/text"}]`,
+			wantErr:    domain.ErrInvalidPatch,
+		},
+		{
+			name:       "result is validated",
+			operations: `[{"op":"replace","path":"/release_date","value":"2003-12-01"}]`,
+			wantErr:    domain.ErrInvalidPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &patchSongsRepo{song: song}
+			s := NewSongsService(repo, validator.Init(), "")
+
+			_, err := s.Patch(context.Background(), song.ID, dto.SongPatchDto{Operations: jsonPatch(t, tt.operations)})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Patch() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(repo.params, tt.want) {
+				t.Errorf("params = %v, want %v", repo.params, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"songs-library-go/internal/delivery/dto"
+	"songs-library-go/internal/domain"
+	"songs-library-go/internal/tracing"
+	"time"
+)
+
+// GetRandomSongs retrieves up to params.Count distinct random songs matching the same filters as GetSongs.
+func (s SongsService) GetRandomSongs(ctx context.Context, userID int32, params dto.RandomSongsParamsDto) ([]domain.Song, error) {
+	ctx, span := tracing.Start(ctx, "SongsService.GetRandomSongs")
+	defer span.End()
+
+	pivots := make([]float64, params.Count)
+	for i := range pivots {
+		pivots[i] = rand.Float64()
+	}
+
+	return s.repo.GetRandomSongs(ctx, pivots, s.makeSongParamsMap(params.Filters), userID, params.Favorited, params.MinRating)
+}
+
+// GetDailySong retrieves the song of the current UTC day, which is the same for everyone.
+// The song is picked by a hash of the date, so every instance picks the same song.
+func (s SongsService) GetDailySong(ctx context.Context, userID int32) (domain.Song, error) {
+	ctx, span := tracing.Start(ctx, "SongsService.GetDailySong")
+	defer span.End()
+
+	day := time.Now().UTC()
+
+	hash := fnv.New64a()
+	hash.Write([]byte(day.Format(time.DateOnly)))
+	pivot := float64(hash.Sum64()>>11) / (1 << 53)
+
+	return s.repo.GetDailySong(ctx, day, pivot, userID)
+}
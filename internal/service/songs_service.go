@@ -1,47 +1,103 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	log "github.com/sirupsen/logrus"
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
 	"math"
 	"net/http"
 	"net/url"
+	"slices"
 	"songs-library-go/internal/delivery/dto"
 	"songs-library-go/internal/domain"
+	"songs-library-go/internal/logging"
+	"songs-library-go/internal/metrics"
+	"songs-library-go/internal/tracing"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // SongsRepo defines methods for interacting with the song data store, including retrieval, creation, updating, and deletion of songs.
 type SongsRepo interface {
-	GetSongs(page int, limit int, filtersMap map[string]interface{}) ([]domain.Song, int, error)
-	GetSongText(songID int32) (string, error)
-	Delete(songID int32) error
-	UpdateSong(songID int32, paramsMap map[string]interface{}) (domain.Song, error)
-	Create(groupName, songName string) (domain.Song, error)
-	AddDetails(songID int32, paramsMap map[string]interface{}) error
+	GetSongs(ctx context.Context, page int, limit int, filtersMap map[string]interface{}, userID int32, favoritedOnly bool, minRating float64, sort domain.SongsSort) ([]domain.Song, int, error)
+	GetRandomSongs(ctx context.Context, pivots []float64, filtersMap map[string]interface{}, userID int32, favoritedOnly bool, minRating float64) ([]domain.Song, error)
+	GetDailySong(ctx context.Context, day time.Time, pivot float64, userID int32) (domain.Song, error)
+	GetSongText(ctx context.Context, songID int32) (string, error)
+	Delete(ctx context.Context, songID int32) error
+	UpdateSong(ctx context.Context, songID int32, paramsMap map[string]interface{}) (domain.Song, error)
+	PatchSong(ctx context.Context, songID int32, fn func(domain.Song) (map[string]interface{}, error)) (domain.Song, error)
+	Create(ctx context.Context, groupName, songName string) (domain.Song, error)
+	AddDetails(ctx context.Context, songID int32, paramsMap map[string]interface{}) error
+	ApplyBatch(ctx context.Context, ops []domain.SongOperation, atomic bool) ([]domain.SongOperationResult, error)
+	ExportSongs(ctx context.Context, filtersMap map[string]interface{}, fn func(domain.Song) error) error
+	ImportSongs(ctx context.Context, policy domain.ConflictPolicy, fn func(insert func([]domain.Song) ([]domain.ImportedSong, error)) error) error
+	AddFavorite(ctx context.Context, userID, songID int32) error
+	RemoveFavorite(ctx context.Context, userID, songID int32) error
 }
 
+const (
+	maxConcurrentEnrichments = 8
+	musicInfoAPI             = "music_info"
+	mesEnrichmentSkipped     = "song details enrichment skipped at shutdown"
+)
+
 // SongsService manages song operations and interacts with the repository and external music information API.
+// Enrichments run with enrichmentCtx, which is canceled if they don't finish before the shutdown deadline.
 type SongsService struct {
-	repo            SongsRepo
-	musicInfoAPIURL string
+	repo               SongsRepo
+	validator          *validator.Validate
+	musicInfoAPIURL    string
+	enrichmentSlots    chan struct{}
+	enrichmentWG       *sync.WaitGroup
+	enrichmentCtx      context.Context
+	cancelEnrichment   context.CancelFunc
+	enrichmentMu       *sync.Mutex
+	pendingEnrichments map[int32]int
 }
 
-// NewSongsService initializes and returns a new instance of SongsService with the provided repository and music info API URL.
-func NewSongsService(repo SongsRepo, musicInfoAPIURL string) *SongsService {
+// NewSongsService initializes and returns a new instance of SongsService with the provided repository, validator and music info API URL.
+func NewSongsService(repo SongsRepo, validator *validator.Validate, musicInfoAPIURL string) *SongsService {
+	enrichmentCtx, cancelEnrichment := context.WithCancel(context.Background())
+
 	return &SongsService{
-		repo:            repo,
-		musicInfoAPIURL: musicInfoAPIURL,
+		repo:               repo,
+		validator:          validator,
+		musicInfoAPIURL:    musicInfoAPIURL,
+		enrichmentSlots:    make(chan struct{}, maxConcurrentEnrichments),
+		enrichmentWG:       &sync.WaitGroup{},
+		enrichmentCtx:      enrichmentCtx,
+		cancelEnrichment:   cancelEnrichment,
+		enrichmentMu:       &sync.Mutex{},
+		pendingEnrichments: make(map[int32]int),
 	}
 }
 
 // GetSongs retrieves songs from the repository based on the provided filtering and pagination parameters.
-func (s SongsService) GetSongs(params dto.GetSongsDto) ([]domain.Song, int, error) {
+// Songs are flagged as favorites of the user, and params.Favorited limits them to the user's favorites
+// and params.MinRating to songs with at least this average rating. Songs sorted by popularity are ordered by their plays in params.Period.
+func (s SongsService) GetSongs(ctx context.Context, userID int32, params dto.GetSongsDto) ([]domain.Song, int, error) {
+	ctx, span := tracing.Start(ctx, "SongsService.GetSongs")
+	defer span.End()
+
 	filtersMap := s.makeSongParamsMap(params.Filters)
 
-	songs, totalPages, err := s.repo.GetSongs(params.PaginationParams.Page, params.PaginationParams.Limit, filtersMap)
+	sort := domain.SongsSort{By: params.Sort}
+	if params.Sort == domain.SortPopular {
+		days, err := domain.ParsePeriod(params.Period)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		sort.Since = domain.PeriodStart(time.Now(), days)
+	}
+
+	songs, totalPages, err := s.repo.GetSongs(ctx, params.PaginationParams.Page, params.PaginationParams.Limit, filtersMap, userID, params.Favorited, params.MinRating, sort)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -49,19 +105,45 @@ func (s SongsService) GetSongs(params dto.GetSongsDto) ([]domain.Song, int, erro
 	return songs, totalPages, nil
 }
 
+// AddFavorite adds the song to the favorites of the user.
+func (s SongsService) AddFavorite(ctx context.Context, userID, songID int32) error {
+	ctx, span := tracing.Start(ctx, "SongsService.AddFavorite")
+	defer span.End()
+
+	return s.repo.AddFavorite(ctx, userID, songID)
+}
+
+// RemoveFavorite removes the song from the favorites of the user.
+func (s SongsService) RemoveFavorite(ctx context.Context, userID, songID int32) error {
+	ctx, span := tracing.Start(ctx, "SongsService.RemoveFavorite")
+	defer span.End()
+
+	return s.repo.RemoveFavorite(ctx, userID, songID)
+}
+
+// Export streams all songs matching the provided filters to fn without loading them into memory at once.
+func (s SongsService) Export(ctx context.Context, params dto.ExportSongsDto, fn func(domain.Song) error) error {
+	ctx, span := tracing.Start(ctx, "SongsService.Export")
+	defer span.End()
+
+	return s.repo.ExportSongs(ctx, s.makeSongParamsMap(params.Filters), fn)
+}
+
 // GetSongText retrieves the text of a song by its ID and paginates the verses based on the provided parameters.
-func (s SongsService) GetSongText(songID int32, params dto.PaginationParamsDto) ([]string, int, error) {
-	songText, err := s.repo.GetSongText(songID)
+func (s SongsService) GetSongText(ctx context.Context, songID int32, params dto.PaginationParamsDto) ([]string, int, error) {
+	ctx, span := tracing.Start(ctx, "SongsService.GetSongText")
+	defer span.End()
+
+	songText, err := s.repo.GetSongText(ctx, songID)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	if songText == "" {
+	verses := domain.SplitVerses(songText)
+	if len(verses) == 0 {
 		return make([]string, 0), 0, nil
 	}
 
-	verses := strings.Split(songText, "\n\n")
-
 	totalPages := int(math.Ceil(float64(len(verses)) / float64(params.Limit)))
 
 	if params.Page > totalPages {
@@ -78,32 +160,75 @@ func (s SongsService) GetSongText(songID int32, params dto.PaginationParamsDto)
 }
 
 // Delete removes a song by its ID from the repository.
-func (s SongsService) Delete(songID int32) error {
-	return s.repo.Delete(songID)
+func (s SongsService) Delete(ctx context.Context, songID int32) error {
+	ctx, span := tracing.Start(ctx, "SongsService.Delete")
+	defer span.End()
+
+	return s.repo.Delete(ctx, songID)
 }
 
-// Update modifies an existing song's details based on the provided parameters.
-func (s SongsService) Update(songID int32, updateSongInput dto.SongParamsDto) (domain.Song, error) {
-	paramsMap := s.makeSongParamsMap(updateSongInput)
+// Create adds a new song to the repository and initiates the process to fetch and save its details.
+func (s SongsService) Create(ctx context.Context, createSongInput dto.CreateSongDto) (domain.Song, error) {
+	ctx, span := tracing.Start(ctx, "SongsService.Create")
+	defer span.End()
 
-	song, err := s.repo.UpdateSong(songID, paramsMap)
+	song, err := s.repo.Create(ctx, createSongInput.Group, createSongInput.Song)
 	if err != nil {
 		return domain.Song{}, err
 	}
 
+	s.enqueueDetails(ctx, song.ID, createSongInput.Group, createSongInput.Song)
+
 	return song, nil
 }
 
-// Create adds a new song to the repository and initiates the process to fetch and save its details.
-func (s SongsService) Create(createSongInput dto.CreateSongDto) (domain.Song, error) {
-	song, err := s.repo.Create(createSongInput.Group, createSongInput.Song)
-	if err != nil {
-		return domain.Song{}, err
+// Batch applies a list of mixed create, update and delete operations, either atomically or best-effort.
+// Operations that failed validation are reported as failed and are not applied.
+// Details are fetched for every song created by the batch once it has been applied.
+func (s SongsService) Batch(ctx context.Context, batch dto.BatchDto) ([]domain.SongOperationResult, error) {
+	ctx, span := tracing.Start(ctx, "SongsService.Batch")
+	defer span.End()
+
+	results := make([]domain.SongOperationResult, len(batch.Operations))
+	ops := make([]domain.SongOperation, 0, len(batch.Operations))
+	opIndexes := make([]int, 0, len(batch.Operations))
+
+	for i, operation := range batch.Operations {
+		if operation.Error != "" {
+			results[i].Err = fmt.Errorf("%w: %s", domain.ErrInvalidBatchOperation, operation.Error)
+			continue
+		}
+
+		op := domain.SongOperation{
+			Type:   domain.SongOperationType(operation.Op),
+			SongID: int32(operation.SongID),
+		}
+
+		switch op.Type {
+		case domain.OpCreate:
+			op.Group, op.Song = operation.Create.Group, operation.Create.Song
+		case domain.OpUpdate:
+			op.Params = s.makeSongParamsMap(operation.Update)
+		}
+
+		ops = append(ops, op)
+		opIndexes = append(opIndexes, i)
 	}
 
-	go s.getAndSaveDetails(song.ID, createSongInput.Group, createSongInput.Song)
+	opResults, err := s.repo.ApplyBatch(ctx, ops, batch.Atomic)
+	if opResults == nil {
+		return nil, err
+	}
 
-	return song, nil
+	for i, result := range opResults {
+		results[opIndexes[i]] = result
+
+		if ops[i].Type == domain.OpCreate && result.Err == nil {
+			s.enqueueDetails(ctx, result.Song.ID, result.Song.Group, result.Song.Song)
+		}
+	}
+
+	return results, err
 }
 
 func (s SongsService) makeSongParamsMap(params dto.SongParamsDto) map[string]interface{} {
@@ -151,52 +276,169 @@ func (s SongsService) toSongDto(song domain.Song) dto.SongDto {
 	return songDto
 }
 
-func (s SongsService) getAndSaveDetails(songID int32, groupName, songName string) {
-	details, err := s.getDetails(songName, groupName)
+// WaitForEnrichment blocks until all queued song details enrichments are finished or ctx is done.
+// If ctx is done first, the running and queued enrichments are canceled, the songs left without details are logged,
+// and it returns the error of ctx once the enrichments have stopped, so none of them uses the database after it is closed.
+// Songs created after that aren't enriched.
+func (s SongsService) WaitForEnrichment(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.enrichmentWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.enrichmentMu.Lock()
+	s.cancelEnrichment()
+	songIDs := make([]int32, 0, len(s.pendingEnrichments))
+	for songID := range s.pendingEnrichments {
+		songIDs = append(songIDs, songID)
+	}
+	s.enrichmentMu.Unlock()
+
+	slices.Sort(songIDs)
+	for range songIDs {
+		metrics.ObserveEnrichment(metrics.EnrichmentSkipped)
+	}
+	logging.FromContext(ctx).WithField("song_ids", songIDs).Warn(mesEnrichmentSkipped)
+
+	// Requests to the music info API and queries are bound to the canceled context, so the enrichments stop promptly.
+	<-done
+
+	return ctx.Err()
+}
+
+// enqueueDetails enriches the song in the background. The enrichment outlives the request, so it is traced
+// in its own trace linked to the trace of the request, and logged with the logger of the request.
+// Songs aren't enriched if the music info API isn't configured or the enrichments have been canceled at shutdown.
+func (s SongsService) enqueueDetails(ctx context.Context, songID int32, groupName, songName string) {
+	if s.musicInfoAPIURL == "" {
+		return
+	}
+
+	link := trace.LinkFromContext(ctx)
+	logger := logging.FromContext(ctx).WithField("song_id", songID)
+
+	s.enrichmentMu.Lock()
+	if s.enrichmentCtx.Err() != nil {
+		s.enrichmentMu.Unlock()
+		metrics.ObserveEnrichment(metrics.EnrichmentSkipped)
+		logger.Warn(mesEnrichmentSkipped)
+		return
+	}
+	s.pendingEnrichments[songID]++
+	s.enrichmentWG.Add(1)
+	s.enrichmentMu.Unlock()
+
+	go func() {
+		defer s.enrichmentWG.Done()
+		defer s.finishEnrichment(songID)
+
+		select {
+		case s.enrichmentSlots <- struct{}{}:
+		case <-s.enrichmentCtx.Done():
+			return
+		}
+		defer func() { <-s.enrichmentSlots }()
+
+		ctx, span := tracing.Start(s.enrichmentCtx, "SongsService.enrichDetails", trace.WithNewRoot(), trace.WithLinks(link))
+		defer span.End()
+
+		ctx = logging.NewContext(ctx, logger)
+		s.getAndSaveDetails(ctx, songID, groupName, songName)
+	}()
+}
+
+// finishEnrichment removes a finished or canceled enrichment of the song from the pending enrichments.
+func (s SongsService) finishEnrichment(songID int32) {
+	s.enrichmentMu.Lock()
+	defer s.enrichmentMu.Unlock()
+
+	if s.pendingEnrichments[songID]--; s.pendingEnrichments[songID] == 0 {
+		delete(s.pendingEnrichments, songID)
+	}
+}
+
+func (s SongsService) getAndSaveDetails(ctx context.Context, songID int32, groupName, songName string) {
+	details, err := s.getDetails(ctx, songName, groupName)
 	if err != nil {
-		log.WithError(err).Error(domain.ErrGettingDetails)
+		if ctx.Err() != nil {
+			// Canceled at shutdown, the song is logged by WaitForEnrichment.
+			return
+		}
+
+		logging.FromContext(ctx).WithError(err).Error(domain.ErrGettingDetails)
+		metrics.ObserveEnrichment(enrichmentOutcome(err))
 		return
 	}
 
 	paramsMap := s.makeSongParamsMap(details)
 	if len(paramsMap) == 0 {
-		log.Errorf("%s (group name: %s, song name: %s)", domain.ErrDetailsNotFound, groupName, songName)
+		logging.FromContext(ctx).Errorf("%s (group name: %s, song name: %s)", domain.ErrDetailsNotFound, groupName, songName)
+		metrics.ObserveEnrichment(metrics.EnrichmentNotFound)
 		return
 	}
 
-	if err := s.repo.AddDetails(songID, paramsMap); err != nil {
-		log.WithError(err).Error(domain.ErrAddingDetails)
+	if err := s.repo.AddDetails(ctx, songID, paramsMap); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+
+		logging.FromContext(ctx).WithError(err).Error(domain.ErrAddingDetails)
+		metrics.ObserveEnrichment(metrics.EnrichmentDBError)
 		return
 	}
 
-	log.Info(fmt.Sprintf("%s %d", domain.SuccessfulDetailAddition, songID))
+	metrics.ObserveEnrichment(metrics.EnrichmentSuccess)
+	logging.FromContext(ctx).Info(fmt.Sprintf("%s %d", domain.SuccessfulDetailAddition, songID))
 }
 
-func (s SongsService) getDetails(songName, groupName string) (dto.SongParamsDto, error) {
+func (s SongsService) getDetails(ctx context.Context, songName, groupName string) (dto.SongParamsDto, error) {
 	requestURL := fmt.Sprintf("%s/info?group=%s&song=%s", s.musicInfoAPIURL, url.QueryEscape(groupName), url.QueryEscape(songName))
 
-	req, err := http.NewRequest("GET", requestURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
-		return dto.SongParamsDto{}, fmt.Errorf("%s: %s", domain.ErrCreatingRequest, err)
+		return dto.SongParamsDto{}, fmt.Errorf("%w: %s", domain.ErrCreatingRequest, err)
 	}
 
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
+	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
-		return dto.SongParamsDto{}, fmt.Errorf("%s: %s", domain.ErrSendingRequest, err)
+		metrics.ObserveExternalAPI(musicInfoAPI, "error", time.Since(start))
+		return dto.SongParamsDto{}, fmt.Errorf("%w: %s", domain.ErrSendingRequest, err)
 	}
 	defer resp.Body.Close()
 
+	metrics.ObserveExternalAPI(musicInfoAPI, strconv.Itoa(resp.StatusCode), time.Since(start))
+
 	if resp.StatusCode != http.StatusOK {
-		return dto.SongParamsDto{}, fmt.Errorf("%s: %s", domain.ErrResponseError, resp.Status)
+		return dto.SongParamsDto{}, fmt.Errorf("%w: %s", domain.ErrResponseError, resp.Status)
 	}
 
 	var details dto.SongParamsDto
 	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
-		return dto.SongParamsDto{}, fmt.Errorf("%s: %s", domain.ErrDecodingResponse, err)
+		return dto.SongParamsDto{}, fmt.Errorf("%w: %s", domain.ErrDecodingResponse, err)
 	}
 
 	return details, nil
 }
+
+// enrichmentOutcome classifies an error of getDetails for the enrichment metrics.
+func enrichmentOutcome(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrResponseError):
+		return metrics.EnrichmentResponseError
+	case errors.Is(err, domain.ErrDecodingResponse):
+		return metrics.EnrichmentDecodingError
+	default:
+		return metrics.EnrichmentRequestError
+	}
+}
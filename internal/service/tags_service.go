@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"songs-library-go/internal/delivery/dto"
+)
+
+// TagsRepo defines methods for storing the tags of songs.
+type TagsRepo interface {
+	GetTags(ctx context.Context, songID int32) ([]string, error)
+	ReplaceTags(ctx context.Context, songID int32, tags []string) error
+}
+
+// TagsService manages the tags of songs, such as genres and moods.
+type TagsService struct {
+	repo TagsRepo
+}
+
+// NewTagsService initializes and returns a new instance of TagsService with the provided repository.
+func NewTagsService(repo TagsRepo) *TagsService {
+	return &TagsService{
+		repo: repo,
+	}
+}
+
+// GetTags retrieves the tags of a song in alphabetical order.
+func (s TagsService) GetTags(ctx context.Context, songID int32) ([]string, error) {
+	return s.repo.GetTags(ctx, songID)
+}
+
+// ReplaceTags replaces the tags of a song and returns them in alphabetical order without duplicates.
+func (s TagsService) ReplaceTags(ctx context.Context, songID int32, tagsInput dto.SongTagsDto) ([]string, error) {
+	tags := append([]string{}, tagsInput.Tags...)
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+
+	if err := s.repo.ReplaceTags(ctx, songID, tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
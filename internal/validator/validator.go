@@ -4,18 +4,35 @@ import (
 	"github.com/go-playground/validator/v10"
 	"reflect"
 	"songs-library-go/internal/domain"
+	"strings"
 	"time"
 )
 
-// Init initializes a new validator and registers a custom validation function.
+// Init initializes a new validator and registers custom validation functions.
+// Validation errors report fields by their JSON names so they can be returned to clients as is.
 func Init() *validator.Validate {
 	validate := validator.New()
 
 	validate.RegisterValidation("customDate", customDateValidation)
+	validate.RegisterValidation("period", periodValidation)
+	validate.RegisterTagNameFunc(jsonFieldName)
 
 	return validate
 }
 
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
 func customDateValidation(fl validator.FieldLevel) bool {
 	if fl.Field().Kind() == reflect.Ptr && fl.Field().IsNil() {
 		return true
@@ -31,3 +48,8 @@ func customDateValidation(fl validator.FieldLevel) bool {
 	_, err := time.Parse(domain.DateFormat, dateStr)
 	return err == nil
 }
+
+func periodValidation(fl validator.FieldLevel) bool {
+	_, err := domain.ParsePeriod(fl.Field().String())
+	return err == nil
+}